@@ -22,57 +22,222 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/deploy"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
 	configError "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/selector"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2/topologysort"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/schema"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/state"
 	"github.com/spf13/afero"
 )
 
+const (
+	defaultConcurrentDeploymentRequests = 10
+	concurrentRequestsEnvKey            = "CONCURRENT_REQUESTS"
+)
+
+// Options holds the options of a deployment
+type Options struct {
+	// DryRun only validates the configurations instead of deploying them
+	DryRun bool
+	// Online validates the configurations against the environments during dry-runs, using the validation endpoints
+	// of the Dynatrace APIs. Nothing is persisted in the environments.
+	Online bool
+	// ContinueOnError continues the deployment of other configurations if a configuration fails to deploy
+	ContinueOnError bool
+	// Parallel deploys configurations that do not depend on each other in parallel
+	Parallel bool
+	// UseState enables the deployment state file stored next to the manifest, see state.FileName
+	UseState bool
+	// Prune deletes objects created by monaco which are not defined in any project anymore after a successful deployment
+	Prune bool
+	// ReportFile is the optional path of a report file the outcome of each config is written to
+	ReportFile string
+	// ReportFormat is the format of the ReportFile
+	ReportFormat report.Format
+	// Rollback restores all objects of an environment modified by the deployment, if the deployment of the
+	// environment fails
+	Rollback bool
+	// SkipUnchanged skips updating objects that already equal the rendered config
+	SkipUnchanged bool
+	// Only restricts the deployment to the configs matched by any of the selectors, and their dependencies
+	Only []selector.Selector
+	// Exclude excludes the configs matched by any of the selectors from the deployment
+	Exclude []selector.Selector
+	// SchemaCache is the optional folder of cached settings schemas, see schema.Download. If set, settings objects
+	// are validated against their schema before they are deployed.
+	SchemaCache string
+}
+
 func Deploy(fs afero.Fs, deploymentManifestPath string, specificEnvironments []string, environmentGroup string,
-	specificProject []string, dryRun, continueOnError bool) error {
+	specificProject []string, opts Options) error {
 
-	deploymentManifestPath = filepath.Clean(deploymentManifestPath)
-	absManifestPath, err := filepath.Abs(deploymentManifestPath)
+	recorder := report.NewRecorder()
 
+	d, err := loadDeployment(fs, deploymentManifestPath, specificEnvironments, environmentGroup, specificProject)
 	if err != nil {
-		return fmt.Errorf("error while finding absolute path for `%s`: %w", deploymentManifestPath, err)
+		return reportFailureBeforeDeployment(fs, opts, recorder, err)
 	}
 
-	manifest, errs := manifest.LoadManifest(&manifest.ManifestLoaderContext{
-		Fs:           fs,
-		ManifestPath: absManifestPath,
-	})
+	logProjectsAndEnvironments("Projects to be deployed:", d)
 
-	if errs != nil {
-		// TODO add grouping and print proper error repot
-		errutils.PrintErrors(errs)
-		return errors.New("error while loading manifest")
+	if err := selectConfigs(d.sortedConfigs, opts.Only, opts.Exclude); err != nil {
+		return reportFailureBeforeDeployment(fs, opts, recorder, err)
 	}
 
-	environments := manifest.Environments
-	if environmentGroup != "" {
-		environments = environments.FilterByGroup(environmentGroup)
-
-		if len(environments) == 0 {
-			return fmt.Errorf("no environments in group %q", environmentGroup)
-		} else {
-			log.Info("Environments loaded in group %q: %v", environmentGroup, maps.Keys(environments))
+	if opts.SchemaCache != "" {
+		if d.schemaCache, err = schema.LoadCache(fs, opts.SchemaCache); err != nil {
+			return reportFailureBeforeDeployment(fs, opts, recorder, err)
 		}
+	}
 
+	if !opts.UseState || opts.DryRun {
+		err = execDeployment(d.sortedConfigs, d.environments, opts, d.apis, nil, d.schemaCache, recorder)
+	} else {
+		err = execDeploymentWithState(fs, filepath.Join(d.workingDir, state.FileName), d, opts, recorder)
 	}
 
-	if len(specificEnvironments) > 0 {
-		environments, err = environments.FilterByNames(specificEnvironments)
-		if err != nil {
-			return fmt.Errorf("failed to filter environments: %w", err)
+	logSummary(recorder)
+	writeReport(fs, opts, recorder)
+
+	if err != nil {
+		return err
+	}
+
+	if opts.Prune {
+		return prune(d, opts.DryRun)
+	}
+
+	return nil
+}
+
+// writeReport writes the report of the deployment, if one is requested
+func writeReport(fs afero.Fs, opts Options, recorder *report.Recorder) {
+	if opts.ReportFile == "" {
+		return
+	}
+
+	if err := report.WriteFile(fs, opts.ReportFile, opts.ReportFormat, recorder); err != nil {
+		log.Error("Failed to write deployment report: %v", err)
+	} else {
+		log.Info("Deployment report written to %q", opts.ReportFile)
+	}
+}
+
+// reportFailureBeforeDeployment records the given error, which stopped the deployment before any config was deployed,
+// and writes the report. If projects failed to load or sort, each of their errors is recorded. The error is returned.
+func reportFailureBeforeDeployment(fs afero.Fs, opts Options, recorder *report.Recorder, err error) error {
+	var loadErr deploymentLoadError
+	if errors.As(err, &loadErr) {
+		for _, e := range loadErr.errs {
+			recorder.RecordError(e)
 		}
+	} else {
+		recorder.RecordError(err)
+	}
+
+	writeReport(fs, opts, recorder)
+	return err
+}
+
+// execDeploymentWithState locks and loads the state file at the given path, deploys all configs and writes the updated
+// state back to the file. The state is written even if the deployment failed, to keep track of all deployed objects.
+func execDeploymentWithState(fs afero.Fs, statePath string, d loadedDeployment, opts Options, recorder *report.Recorder) (err error) {
+	unlock, err := state.Lock(fs, statePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := unlock(); unlockErr != nil {
+			log.Error("Failed to release lock of state file %q: %v", statePath, unlockErr)
+		}
+	}()
+
+	deployState, err := state.Load(fs, statePath)
+	if err != nil {
+		return err
+	}
+
+	deploymentErr := execDeployment(d.sortedConfigs, d.environments, opts, d.apis, deployState, d.schemaCache, recorder)
+
+	if err := deployState.Write(fs, statePath); err != nil {
+		return errors.Join(deploymentErr, err)
+	}
+	log.Debug("Updated state file %q", statePath)
+
+	return deploymentErr
+}
+
+// loadedDeployment holds all information loaded from a manifest that is needed to deploy its projects
+type loadedDeployment struct {
+	environments manifest.Environments
+	// projects holds the projects to deploy, allProjects all projects defined in the manifest
+	projects      []project.Project
+	allProjects   []project.Project
+	sortedConfigs map[string][]config.Config
+	apis          api.ApiMap
+	workingDir    string
+	// projectDefinitions holds the definitions of all projects in the manifest
+	projectDefinitions manifest.ProjectDefinitionByProjectId
+	// schemaCache holds the settings schemas settings objects are validated against. It is nil if objects are not
+	// validated.
+	schemaCache *schema.Cache
+}
+
+// deploymentLoadError is returned if the projects of a deployment fail to load or their configs fail to sort. The
+// individual errors are printed already, and kept to be recorded in the report of the deployment.
+type deploymentLoadError struct {
+	message string
+	errs    []error
+}
+
+func (e deploymentLoadError) Error() string {
+	return e.message
+}
+
+func (e deploymentLoadError) Unwrap() []error {
+	return e.errs
+}
+
+// loadDeployment loads the manifest and the projects defined in it, filters them by the given environments, group
+// and projects and sorts the configs of each environment.
+func loadDeployment(fs afero.Fs, deploymentManifestPath string, specificEnvironments []string, environmentGroup string,
+	specificProject []string) (loadedDeployment, error) {
+
+	d, err := loadUnsortedDeployment(fs, deploymentManifestPath, specificEnvironments, environmentGroup, specificProject)
+	if err != nil {
+		return loadedDeployment{}, err
+	}
+
+	sortedConfigs, errs := topologysort.GetSortedConfigsForEnvironments(d.projects, maps.Keys(d.environments))
+
+	if errs != nil {
+		printErrorReport(errs)
+		return loadedDeployment{}, deploymentLoadError{message: "error during sort", errs: errs}
+	}
+
+	d.sortedConfigs = sortedConfigs
+	return d, nil
+}
+
+// loadUnsortedDeployment loads the manifest and the projects defined in it, and filters them by the given
+// environments, group and projects. The configs are not sorted, so loading succeeds even for circular dependencies.
+func loadUnsortedDeployment(fs afero.Fs, deploymentManifestPath string, specificEnvironments []string, environmentGroup string,
+	specificProject []string) (loadedDeployment, error) {
+
+	manifest, environments, absManifestPath, err := loadManifest(fs, deploymentManifestPath, specificEnvironments, environmentGroup)
+	if err != nil {
+		return loadedDeployment{}, err
 	}
 
 	environmentNames := maps.Keys(environments)
@@ -91,39 +256,77 @@ func Deploy(fs afero.Fs, deploymentManifestPath string, specificEnvironments []s
 	if errs != nil {
 		printErrorReport(errs)
 
-		return errors.New("error while loading projects - you may be loading v1 projects, please 'convert' to v2")
+		return loadedDeployment{}, deploymentLoadError{message: "error while loading projects - you may be loading v1 projects, please 'convert' to v2", errs: errs}
 	}
 
-	projects, err = loadProjectsToDeploy(specificProject, projects, environmentNames)
+	projectsToDeploy, err := loadProjectsToDeploy(specificProject, projects, environmentNames)
 	if err != nil {
-		return err
+		return loadedDeployment{}, err
+	}
+
+	return loadedDeployment{
+		environments:       environments,
+		projects:           projectsToDeploy,
+		allProjects:        projects,
+		apis:               apis,
+		workingDir:         workingDir,
+		projectDefinitions: manifest.Projects,
+	}, nil
+}
+
+// loadManifest loads the manifest at the given path, and returns it together with its environments filtered by the
+// given environments and group, and the absolute path of the manifest.
+func loadManifest(fs afero.Fs, deploymentManifestPath string, specificEnvironments []string, environmentGroup string) (manifest.Manifest, manifest.Environments, string, error) {
+	deploymentManifestPath = filepath.Clean(deploymentManifestPath)
+	absManifestPath, err := filepath.Abs(deploymentManifestPath)
+
+	if err != nil {
+		return manifest.Manifest{}, nil, "", fmt.Errorf("error while finding absolute path for `%s`: %w", deploymentManifestPath, err)
 	}
 
-	sortedConfigs, errs := topologysort.GetSortedConfigsForEnvironments(projects, environmentNames)
+	m, errs := manifest.LoadManifest(&manifest.ManifestLoaderContext{
+		Fs:           fs,
+		ManifestPath: absManifestPath,
+	})
 
 	if errs != nil {
 		// TODO add grouping and print proper error repot
 		errutils.PrintErrors(errs)
-		return errors.New("error during sort")
+		return manifest.Manifest{}, nil, "", errors.New("error while loading manifest")
 	}
 
-	log.Info("Projects to be deployed:")
-	for _, p := range projects {
-		log.Info("  - %s", p)
+	environments := m.Environments
+	if environmentGroup != "" {
+		environments = environments.FilterByGroup(environmentGroup)
+
+		if len(environments) == 0 {
+			return manifest.Manifest{}, nil, "", fmt.Errorf("no environments in group %q", environmentGroup)
+		} else {
+			log.Info("Environments loaded in group %q: %v", environmentGroup, maps.Keys(environments))
+		}
+
 	}
 
-	log.Info("Environments to deploy to:")
-	for _, name := range environmentNames {
-		log.Info("  - %s", name)
+	if len(specificEnvironments) > 0 {
+		environments, err = environments.FilterByNames(specificEnvironments)
+		if err != nil {
+			return manifest.Manifest{}, nil, "", fmt.Errorf("failed to filter environments: %w", err)
+		}
 	}
 
-	err = execDeployment(sortedConfigs, environments, continueOnError, dryRun, apis)
+	return m, environments, absManifestPath, nil
+}
 
-	if err != nil {
-		return err
+func logProjectsAndEnvironments(projectsHeadline string, d loadedDeployment) {
+	log.Info(projectsHeadline)
+	for _, p := range d.projects {
+		log.Info("  - %s", p)
 	}
 
-	return nil
+	log.Info("Environments to deploy to:")
+	for _, name := range maps.Keys(d.environments) {
+		log.Info("  - %s", name)
+	}
 }
 
 func loadProjectsToDeploy(specificProject []string, projects []project.Project, environmentNames []string) ([]project.Project, error) {
@@ -146,33 +349,58 @@ func loadProjectsToDeploy(specificProject []string, projects []project.Project,
 	return projects, nil
 }
 
-func execDeployment(sortedConfigs map[string][]config.Config, environmentMap map[string]manifest.EnvironmentDefinition, continueOnError bool, dryRun bool, apis map[string]api.Api) error {
+func execDeployment(sortedConfigs map[string][]config.Config, environmentMap map[string]manifest.EnvironmentDefinition, opts Options, apis map[string]api.Api, deployState *state.State, schemaCache *schema.Cache, recorder *report.Recorder) error {
 	var deploymentErrors []error
+	continueOnError, dryRun := opts.ContinueOnError, opts.DryRun
+	attempted := make(map[string]struct{}, len(sortedConfigs))
 
 	for envName, configs := range sortedConfigs {
 		logDeploymentInfo(dryRun, envName)
+		attempted[envName] = struct{}{}
 		env, found := environmentMap[envName]
 
 		if !found {
+			err := fmt.Errorf("cannot find environment `%s`", envName)
+			recordError(recorder, err)
 			if continueOnError {
-				deploymentErrors = append(deploymentErrors, fmt.Errorf("cannot find environment `%s`", envName))
+				deploymentErrors = append(deploymentErrors, err)
 				continue
 			} else {
-				return fmt.Errorf("cannot find environment `%s`", envName)
+				recordNotRunEnvironments(recorder, sortedConfigs, envName, attempted)
+				return err
 			}
 		}
 
-		dtClient, err := createDynatraceClient(env, dryRun)
+		dtClient, err := createDynatraceClient(env, dryRun, opts.Online)
 		if err != nil {
+			recordError(recorder, err)
 			if continueOnError {
 				deploymentErrors = append(deploymentErrors, err)
 				continue
 			} else {
+				recordNotRunEnvironments(recorder, sortedConfigs, envName, attempted)
 				return err
 			}
 		}
 
-		errs := deploy.DeployConfigs(dtClient, apis, configs, deploy.DeployConfigsOptions{ContinueOnErr: continueOnError, DryRun: dryRun})
+		if opts.Parallel {
+			dtClient = client.LimitClientParallelRequests(dtClient, concurrentRequestLimitFromEnv())
+		}
+
+		if schemaCache != nil {
+			dtClient = schema.NewValidatingClient(dtClient, schemaCache)
+		}
+
+		errs := deploy.DeployConfigs(dtClient, apis, configs, deploy.DeployConfigsOptions{
+			ContinueOnErr: continueOnError,
+			DryRun:        dryRun,
+			Online:        dryRun && opts.Online,
+			Parallel:      opts.Parallel,
+			State:         deployState,
+			Report:        recorder,
+			Rollback:      opts.Rollback,
+			SkipUnchanged: opts.SkipUnchanged,
+		})
 		deploymentErrors = append(deploymentErrors, errs...)
 	}
 
@@ -187,6 +415,90 @@ func execDeployment(sortedConfigs map[string][]config.Config, environmentMap map
 	return nil
 }
 
+// selectConfigs restricts the configs of each environment to the ones selected by the given selectors
+func selectConfigs(sortedConfigs map[string][]config.Config, only []selector.Selector, exclude []selector.Selector) error {
+	if len(only) == 0 && len(exclude) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for envName, configs := range sortedConfigs {
+		selected, selectErrs := selector.Select(configs, only, exclude)
+		if len(selectErrs) > 0 {
+			errs = append(errs, selectErrs...)
+			continue
+		}
+
+		log.Info("Selected %d of %d configurations for environment `%s`", len(selected), len(configs), envName)
+		sortedConfigs[envName] = selected
+	}
+
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return errors.New("error while selecting configurations: required dependencies are excluded")
+	}
+
+	return nil
+}
+
+// summaryActions defines which actions are part of the summary logged after the deployment, and in which order
+var summaryActions = []report.Action{
+	report.ActionCreated,
+	report.ActionUpdated,
+	report.ActionUnchanged,
+	report.ActionDeployed,
+	report.ActionValidated,
+	report.ActionSkipped,
+	report.ActionFailed,
+	report.ActionNotRun,
+	report.ActionRolledBack,
+}
+
+// logSummary logs the number of configs per action recorded during the deployment
+func logSummary(recorder *report.Recorder) {
+	counts := recorder.CountByAction()
+
+	var parts []string
+	for _, a := range summaryActions {
+		if counts[a] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[a], a))
+		}
+	}
+
+	if len(parts) > 0 {
+		log.Info("Summary: %s", strings.Join(parts, ", "))
+	}
+}
+
+// recordError records the given error not related to a single config, if a report is requested
+func recordError(recorder *report.Recorder, err error) {
+	if recorder != nil {
+		recorder.RecordError(err)
+	}
+}
+
+// recordNotRunEnvironments records the configs of the failed environment and of all environments not attempted yet as
+// not run, if a report is requested
+func recordNotRunEnvironments(recorder *report.Recorder, sortedConfigs map[string][]config.Config, failedEnv string, attempted map[string]struct{}) {
+	if recorder == nil {
+		return
+	}
+
+	for envName, configs := range sortedConfigs {
+		if _, found := attempted[envName]; found && envName != failedEnv {
+			continue
+		}
+
+		for _, c := range configs {
+			recorder.Record(report.Record{
+				Environment: c.Environment,
+				Coordinate:  c.Coordinate,
+				Action:      report.ActionNotRun,
+			})
+		}
+	}
+}
+
 func logDeploymentInfo(dryRun bool, envName string) {
 	if dryRun {
 		log.Info("Validating configurations for environment `%s`...", envName)
@@ -207,11 +519,12 @@ func printErrorReport(deploymentErrors []error) {
 	var generalErrors []error
 
 	for _, err := range deploymentErrors {
-		switch e := err.(type) {
-		case configError.ConfigError:
-			configErrors = append(configErrors, e)
-		default:
-			generalErrors = append(generalErrors, e)
+		// config errors might be wrapped, e.g. by the deployment of a config
+		var configErr configError.ConfigError
+		if errors.As(err, &configErr) {
+			configErrors = append(configErrors, configErr)
+		} else {
+			generalErrors = append(generalErrors, err)
 		}
 	}
 
@@ -390,9 +703,25 @@ func containsName(names []string, name string) bool {
 	return slices.Contains(names, name)
 }
 
-func createDynatraceClient(environment manifest.EnvironmentDefinition, dryRun bool) (client.Client, error) {
+// createDynatraceClient creates the client to deploy to the given environment. Dry-runs use a client which accepts
+// any config, or a client which only validates configs in the environment, if online is set.
+func createDynatraceClient(environment manifest.EnvironmentDefinition, dryRun bool, online bool) (client.Client, error) {
+	if dryRun && online {
+		return client.CreateClientForEnvironment(environment, client.WithValidateOnly())
+	}
 	if dryRun {
 		return client.NewDummyClient(), nil
 	}
 	return client.CreateClientForEnvironment(environment)
 }
+
+// concurrentRequestLimitFromEnv returns the maximum amount of parallel requests during parallel deployments.
+// It can be configured using the CONCURRENT_REQUESTS environment variable. Invalid values and values less than 1
+// fall back to the default, as they would not allow any request to be sent.
+func concurrentRequestLimitFromEnv() int {
+	limit, err := strconv.Atoi(os.Getenv(concurrentRequestsEnvKey))
+	if err != nil || limit <= 0 {
+		limit = defaultConcurrentDeploymentRequests
+	}
+	return limit
+}
@@ -17,11 +17,21 @@
 package deploy
 
 import (
+	"errors"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/selector"
 	p "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/state"
 	"github.com/spf13/afero"
 	"gotest.tools/assert"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -175,7 +185,7 @@ func TestDeploy_ReportsErrorWhenRunningOnV1Config(t *testing.T) {
 	manifestPath, _ := filepath.Abs("manifest.yaml")
 	_ = afero.WriteFile(testFs, manifestPath, []byte("manifestVersion: 1.0\nprojects:\n- name: project\nenvironmentGroups:\n- name: default\n  environments:\n  - name: environment1\n    url:\n      type: environment\n      value: ENV_URL\n    token:\n      name: ENV_TOKEN\n"), 0644)
 
-	err := Deploy(testFs, "manifest.yaml", []string{}, "", []string{}, true, false)
+	err := Deploy(testFs, "manifest.yaml", []string{}, "", []string{}, Options{DryRun: true})
 	assert.ErrorContains(t, err, "error while loading projects")
 }
 
@@ -191,6 +201,147 @@ func TestDeploy_ReportsErrorForBrokenV2Config(t *testing.T) {
 	manifestPath, _ := filepath.Abs("manifest.yaml")
 	_ = afero.WriteFile(testFs, manifestPath, []byte("manifestVersion: 1.0\nprojects:\n- name: project\nenvironmentGroups:\n- name: default\n  environments:\n  - name: environment1\n    url:\n      type: environment\n      value: ENV_URL\n    token:\n      name: ENV_TOKEN\n"), 0644)
 
-	err := Deploy(testFs, "manifest.yaml", []string{}, "", []string{}, true, false)
+	err := Deploy(testFs, "manifest.yaml", []string{}, "", []string{}, Options{DryRun: true})
 	assert.ErrorContains(t, err, "error while loading projects")
 }
+
+func TestDeploy_OnlineDryRunReportsServerSideValidationErrors(t *testing.T) {
+	var persistingRequests []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost && req.URL.Path == "/api/v2/settings/objects" {
+			if req.URL.Query().Get("validateOnly") != "true" {
+				persistingRequests = append(persistingRequests, req.URL.String())
+			}
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`[{"code": 400, "error": {"code": 400, "message": "Constraints violated.", "constraintViolations": [{"path": "severity", "message": "unknown severity"}]}}]`))
+			return
+		}
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	t.Setenv("VALIDATE_TEST_ENV_URL", server.URL)
+	t.Setenv("VALIDATE_TEST_ENV_TOKEN", "token")
+
+	testFs := afero.NewMemMapFs()
+	writeValidateTestProject(t, testFs, `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+  type:
+    settings:
+      schema: builtin:alerting.profile
+      scope: environment`, `{"name": "{{ .name }}", "severity": "WARNING"}`)
+
+	err := Deploy(testFs, "manifest.yaml", []string{}, "", []string{}, Options{DryRun: true, Online: true, ReportFile: "report.json", ReportFormat: report.FormatJSON})
+	assert.ErrorContains(t, err, "errors during Validation")
+	assert.Equal(t, len(persistingRequests), 0, "no objects should be persisted during an online dry-run")
+
+	content, err := afero.ReadFile(testFs, "report.json")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(content), `"configId": "profile"`), "report should contain the config:\n%s", content)
+	assert.Assert(t, strings.Contains(string(content), "severity: unknown severity"), "report should contain the violation:\n%s", content)
+}
+
+func TestDeploy_WritesReportIfConfigsFailToSort(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	writeValidateTestProject(t, testFs, `
+configs:
+- id: first
+  config:
+    name: First
+    template: profile.json
+    parameters:
+      other: [project, alerting-profile, second, id]
+  type:
+    api: alerting-profile
+- id: second
+  config:
+    name: Second
+    template: profile.json
+    parameters:
+      other: [project, alerting-profile, first, id]
+  type:
+    api: alerting-profile`, `{"name": "{{ .name }}"}`)
+
+	err := Deploy(testFs, "manifest.yaml", []string{}, "", []string{}, Options{DryRun: true, ReportFile: "report.json", ReportFormat: report.FormatJSON})
+	assert.ErrorContains(t, err, "error during sort")
+
+	content, err := afero.ReadFile(testFs, "report.json")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(content), "alerting-profile:first: is part of circular dependency"), "report should contain the sort errors:\n%s", content)
+	assert.Assert(t, strings.Contains(string(content), "alerting-profile:second: is part of circular dependency"), "report should contain the sort errors:\n%s", content)
+}
+
+func TestDeploy_WritesReportIfProjectsFailToLoad(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	writeValidateTestProject(t, testFs, "configs:\n- id: profile\n  config:\n    name: Star Trek Service\n", "{}")
+
+	err := Deploy(testFs, "manifest.yaml", []string{}, "", []string{}, Options{DryRun: true, ReportFile: "report.json", ReportFormat: report.FormatJSON})
+	assert.ErrorContains(t, err, "error while loading projects")
+
+	content, err := afero.ReadFile(testFs, "report.json")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(content), "type configuration is missing"), "report should contain the load errors:\n%s", content)
+}
+
+func TestExecDeploymentWithState_FailsIfStateFileIsLocked(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "monaco-state.json.lock", []byte{}, 0644)
+
+	err := execDeploymentWithState(testFs, "monaco-state.json", loadedDeployment{}, Options{}, nil)
+	assert.Assert(t, errors.Is(err, state.ErrLocked))
+}
+
+func TestExecDeploymentWithState_WritesStateAndReleasesLock(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+
+	err := execDeploymentWithState(testFs, "monaco-state.json", loadedDeployment{}, Options{}, nil)
+	assert.NilError(t, err)
+
+	exists, _ := afero.Exists(testFs, "monaco-state.json")
+	assert.Assert(t, exists, "state file should have been written")
+
+	locked, _ := afero.Exists(testFs, "monaco-state.json.lock")
+	assert.Assert(t, !locked, "lock file should have been removed")
+}
+
+func TestSelectConfigs(t *testing.T) {
+	zone := config.Config{Coordinate: coordinate.Coordinate{Project: "p", Type: "management-zone", ConfigId: "zone"}, Type: config.Type{Api: "management-zone"}}
+	profile := config.Config{
+		Coordinate: coordinate.Coordinate{Project: "p", Type: "alerting-profile", ConfigId: "profile"},
+		Type:       config.Type{Api: "alerting-profile"},
+		Parameters: config.Parameters{"zone": reference.NewWithCoordinate(zone.Coordinate, "id")},
+	}
+	dashboard := config.Config{Coordinate: coordinate.Coordinate{Project: "p", Type: "dashboard", ConfigId: "dashboard"}, Type: config.Type{Api: "dashboard"}}
+
+	only, err := selector.ParseAll([]string{"p:alerting-profile:*"})
+	assert.NilError(t, err)
+
+	sortedConfigs := map[string][]config.Config{"env": {zone, profile, dashboard}}
+	err = selectConfigs(sortedConfigs, only, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sortedConfigs["env"], []config.Config{zone, profile})
+
+	exclude, err := selector.ParseAll([]string{"api=management-zone"})
+	assert.NilError(t, err)
+
+	err = selectConfigs(map[string][]config.Config{"env": {zone, profile, dashboard}}, only, exclude)
+	assert.ErrorContains(t, err, "required dependencies are excluded")
+}
+
+func TestConcurrentRequestLimitFromEnv(t *testing.T) {
+	for value, want := range map[string]int{
+		"":        defaultConcurrentDeploymentRequests,
+		"invalid": defaultConcurrentDeploymentRequests,
+		"-1":      defaultConcurrentDeploymentRequests,
+		"0":       defaultConcurrentDeploymentRequests,
+		"1":       1,
+		"51":      51,
+	} {
+		t.Setenv(concurrentRequestsEnvKey, value)
+		assert.Equal(t, concurrentRequestLimitFromEnv(), want, "value %q", value)
+	}
+}
@@ -0,0 +1,231 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/deploy"
+	"github.com/spf13/afero"
+	"sort"
+)
+
+// Drift compares the configurations of the given manifest against the live objects of each environment, and reports
+// all differences. It never modifies an environment. If outputFile is set, the result is additionally written to it
+// as JSON. It returns an error if any configuration drifted or is missing, so that it can be used in CI pipelines.
+func Drift(fs afero.Fs, deploymentManifestPath string, specificEnvironments []string, environmentGroup string,
+	specificProject []string, outputFile string) error {
+
+	d, err := loadDeployment(fs, deploymentManifestPath, specificEnvironments, environmentGroup, specificProject)
+	if err != nil {
+		return err
+	}
+
+	logProjectsAndEnvironments("Projects to be checked for drift:", d)
+
+	var driftErrors []error
+	var environments []driftEnvironmentOutput
+	driftedConfigs := 0
+
+	envNames := make([]string, 0, len(d.sortedConfigs))
+	for envName := range d.sortedConfigs {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+
+	for _, envName := range envNames {
+		env, found := d.environments[envName]
+		if !found {
+			driftErrors = append(driftErrors, fmt.Errorf("cannot find environment `%s`", envName))
+			continue
+		}
+
+		log.Info("Detecting drift in environment `%s`...", envName)
+
+		dtClient, err := client.CreateClientForEnvironment(env)
+		if err != nil {
+			driftErrors = append(driftErrors, err)
+			continue
+		}
+
+		drifts, errs := deploy.DetectDrift(dtClient, d.apis, d.sortedConfigs[envName])
+		driftErrors = append(driftErrors, errs...)
+
+		printDrift(envName, drifts)
+		environments = append(environments, newDriftEnvironmentOutput(envName, drifts))
+
+		for _, drift := range drifts {
+			if drift.Status == deploy.DriftStatusDrifted || drift.Status == deploy.DriftStatusMissing {
+				driftedConfigs++
+			}
+		}
+	}
+
+	if outputFile != "" {
+		if err := writeDriftOutput(fs, outputFile, environments); err != nil {
+			driftErrors = append(driftErrors, err)
+		}
+	}
+
+	if len(driftErrors) > 0 {
+		printErrorReport(driftErrors)
+		return errors.New("errors during drift detection")
+	}
+
+	if driftedConfigs > 0 {
+		return fmt.Errorf("drift detected in %d configuration(s)", driftedConfigs)
+	}
+
+	log.Info("Drift detection finished: all configurations in sync")
+	return nil
+}
+
+var driftStatusSymbols = map[deploy.DriftStatus]string{
+	deploy.DriftStatusMissing: "+",
+	deploy.DriftStatusDrifted: "~",
+	deploy.DriftStatusInSync:  "=",
+	deploy.DriftStatusSkipped: "-",
+}
+
+// driftCounts counts the configs per drift status
+type driftCounts struct {
+	InSync  int `json:"inSync"`
+	Drifted int `json:"drifted"`
+	Missing int `json:"missing"`
+	Skipped int `json:"skipped"`
+}
+
+func (c *driftCounts) add(status deploy.DriftStatus) {
+	switch status {
+	case deploy.DriftStatusInSync:
+		c.InSync++
+	case deploy.DriftStatusDrifted:
+		c.Drifted++
+	case deploy.DriftStatusMissing:
+		c.Missing++
+	case deploy.DriftStatusSkipped:
+		c.Skipped++
+	}
+}
+
+func (c driftCounts) String() string {
+	return fmt.Sprintf("%d in sync, %d drifted, %d missing, %d skipped", c.InSync, c.Drifted, c.Missing, c.Skipped)
+}
+
+func printDrift(envName string, drifts []deploy.Drift) {
+	output := newDriftEnvironmentOutput(envName, drifts)
+
+	for _, drift := range drifts {
+		if drift.Status == deploy.DriftStatusInSync || drift.Status == deploy.DriftStatusSkipped {
+			log.Debug("  %s %-8s %s", driftStatusSymbols[drift.Status], drift.Status, drift.Coordinate)
+			continue
+		}
+
+		log.Info("  %s %-8s %s", driftStatusSymbols[drift.Status], drift.Status, drift.Coordinate)
+		for _, diff := range drift.Differences {
+			log.Info("        %s", diff)
+		}
+	}
+
+	apis := make([]string, 0, len(output.Apis))
+	for a := range output.Apis {
+		apis = append(apis, a)
+	}
+	sort.Strings(apis)
+
+	for _, a := range apis {
+		log.Info("  %s: %s", a, output.Apis[a])
+	}
+
+	log.Info("Drift in environment `%s`: %s", envName, output.Summary)
+}
+
+type driftEnvironmentOutput struct {
+	Name    string                  `json:"name"`
+	Summary driftCounts             `json:"summary"`
+	Apis    map[string]*driftCounts `json:"apis"`
+	Configs []driftConfigOutput     `json:"configs"`
+}
+
+type driftConfigOutput struct {
+	Project     string                  `json:"project"`
+	Type        string                  `json:"type"`
+	ConfigId    string                  `json:"configId"`
+	Status      deploy.DriftStatus      `json:"status"`
+	Differences []driftDifferenceOutput `json:"differences,omitempty"`
+}
+
+type driftDifferenceOutput struct {
+	Path     string `json:"path"`
+	Expected any    `json:"expected"`
+	Actual   any    `json:"actual"`
+}
+
+func newDriftEnvironmentOutput(envName string, drifts []deploy.Drift) driftEnvironmentOutput {
+	output := driftEnvironmentOutput{
+		Name:    envName,
+		Apis:    map[string]*driftCounts{},
+		Configs: make([]driftConfigOutput, 0, len(drifts)),
+	}
+
+	for _, drift := range drifts {
+		output.Summary.add(drift.Status)
+
+		if _, found := output.Apis[drift.Coordinate.Type]; !found {
+			output.Apis[drift.Coordinate.Type] = &driftCounts{}
+		}
+		output.Apis[drift.Coordinate.Type].add(drift.Status)
+
+		config := driftConfigOutput{
+			Project:  drift.Coordinate.Project,
+			Type:     drift.Coordinate.Type,
+			ConfigId: drift.Coordinate.ConfigId,
+			Status:   drift.Status,
+		}
+		for _, diff := range drift.Differences {
+			config.Differences = append(config.Differences, driftDifferenceOutput{
+				Path:     diff.Path,
+				Expected: diff.Expected,
+				Actual:   diff.Actual,
+			})
+		}
+		output.Configs = append(output.Configs, config)
+	}
+
+	return output
+}
+
+func writeDriftOutput(fs afero.Fs, path string, environments []driftEnvironmentOutput) error {
+	if environments == nil {
+		environments = []driftEnvironmentOutput{}
+	}
+
+	data, err := json.MarshalIndent(struct {
+		Environments []driftEnvironmentOutput `json:"environments"`
+	}{environments}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal drift output: %w", err)
+	}
+
+	if err := afero.WriteFile(fs, path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write drift output to %q: %w", path, err)
+	}
+
+	log.Info("Drift written to %q", path)
+	return nil
+}
@@ -0,0 +1,87 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/graph"
+	"github.com/spf13/afero"
+	"io"
+	"sort"
+)
+
+// Graph writes the dependency graph of the configs of the given manifest for each environment in the given format.
+// The graph is written to the given file, or to out if no file is given. Circular dependencies do not fail, but are
+// highlighted in the graph.
+func Graph(fs afero.Fs, deploymentManifestPath string, specificEnvironments []string, environmentGroup string,
+	filter graph.Filter, format graph.Format, outputFile string, out io.Writer) error {
+
+	d, err := loadUnsortedDeployment(fs, deploymentManifestPath, specificEnvironments, environmentGroup, nil)
+	if err != nil {
+		return err
+	}
+
+	envNames := maps.Keys(d.environments)
+	sort.Strings(envNames)
+
+	graphs := make([]graph.Graph, 0, len(envNames))
+	cycles := 0
+
+	for _, envName := range envNames {
+		var configs []config.Config
+		for _, p := range d.projects {
+			for _, c := range p.Configs[envName] {
+				configs = append(configs, c...)
+			}
+		}
+
+		g := graph.Build(envName, configs, filter)
+		for _, e := range g.Edges {
+			if e.InCycle {
+				cycles++
+			}
+		}
+
+		graphs = append(graphs, g)
+	}
+
+	if cycles > 0 {
+		log.Warn("Found %d dependencies forming circular dependencies, they are highlighted in the graph", cycles)
+	}
+
+	if outputFile == "" {
+		return graph.Write(out, format, graphs)
+	}
+
+	f, err := fs.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create graph file %q: %w", outputFile, err)
+	}
+
+	err = graph.Write(f, format, graphs)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write graph file %q: %w", outputFile, err)
+	}
+
+	log.Info("Dependency graph written to %q", outputFile)
+	return nil
+}
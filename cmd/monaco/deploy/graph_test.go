@@ -0,0 +1,54 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"bytes"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/graph"
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"strings"
+	"testing"
+)
+
+func TestGraph_WritesGraphWithCircularDependencies(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	writeValidateTestProject(t, testFs, `
+configs:
+- id: a
+  config:
+    name: A
+    template: profile.json
+    parameters:
+      other: [b, id]
+  type:
+    api: alerting-profile
+- id: b
+  config:
+    name: B
+    template: profile.json
+    parameters:
+      other: [a, id]
+  type:
+    api: alerting-profile`, `{}`)
+
+	var out bytes.Buffer
+	err := Graph(testFs, "manifest.yaml", []string{}, "", graph.Filter{}, graph.FormatDOT, "", &out)
+	assert.NilError(t, err)
+
+	assert.Assert(t, strings.Contains(out.String(), `[label="other", color=red]`), "cycle should be highlighted in graph:\n%s", out.String())
+}
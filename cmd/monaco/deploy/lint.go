@@ -0,0 +1,97 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/lint"
+	"github.com/spf13/afero"
+)
+
+// Lint loads the configs of the given manifest and reports templates referencing undefined parameters, parameters
+// which are never used and JSON files in project folders which are not used by any config. All warnings are logged,
+// and written as JSON to outputFile if it is set. An error is returned if any warning is found.
+func Lint(fs afero.Fs, deploymentManifestPath string, specificEnvironments []string, environmentGroup string,
+	specificProjects []string, outputFile string) error {
+
+	d, err := loadUnsortedDeployment(fs, deploymentManifestPath, specificEnvironments, environmentGroup, specificProjects)
+	if err != nil {
+		return err
+	}
+
+	configsPerEnvironment := make(map[string][]config.Config, len(d.environments))
+	projectPaths := make([]string, 0, len(d.projects))
+
+	for _, p := range d.projects {
+		projectPaths = append(projectPaths, d.projectDefinitions[p.Id].Path)
+
+		for envName := range d.environments {
+			for _, c := range p.Configs[envName] {
+				configsPerEnvironment[envName] = append(configsPerEnvironment[envName], c...)
+			}
+		}
+	}
+
+	warnings := lint.Configs(configsPerEnvironment)
+
+	// config and template paths are relative to the manifest, the same as when loading projects
+	workingDirFs := fs
+	if d.workingDir != "." {
+		workingDirFs = afero.NewBasePathFs(fs, d.workingDir)
+	}
+
+	orphans, err := lint.OrphanedFiles(workingDirFs, projectPaths, configsPerEnvironment)
+	if err != nil {
+		return err
+	}
+	warnings = append(warnings, orphans...)
+
+	for _, w := range warnings {
+		log.Warn("%s", w)
+	}
+
+	if outputFile != "" {
+		if err := writeLintWarnings(fs, outputFile, warnings); err != nil {
+			return err
+		}
+		log.Info("Lint warnings written to %q", outputFile)
+	}
+
+	if len(warnings) > 0 {
+		return fmt.Errorf("found %d lint warning(s)", len(warnings))
+	}
+
+	log.Info("No lint warnings found")
+	return nil
+}
+
+func writeLintWarnings(fs afero.Fs, outputFile string, warnings []lint.Warning) error {
+	if warnings == nil {
+		warnings = []lint.Warning{}
+	}
+
+	data, err := json.MarshalIndent(warnings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lint warnings: %w", err)
+	}
+
+	if err := afero.WriteFile(fs, outputFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write lint warnings to %q: %w", outputFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint_ReportsWarnings(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	writeValidateTestProject(t, testFs, `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    parameters:
+      unused: value
+  type:
+    api: alerting-profile`, `{"name": "{{ .name }}", "threshold": {{ .threshold }}}`)
+
+	orphan, err := filepath.Abs("project/alerting-profile/orphan.json")
+	assert.NilError(t, err)
+	assert.NilError(t, afero.WriteFile(testFs, orphan, []byte("{}"), 0644))
+
+	err = Lint(testFs, "manifest.yaml", []string{}, "", []string{}, "lint.json")
+	assert.ErrorContains(t, err, "found 3 lint warning(s)")
+
+	content, err := afero.ReadFile(testFs, "lint.json")
+	assert.NilError(t, err)
+
+	for _, expected := range []string{
+		`"kind": "undefined-parameter"`,
+		`"parameter": "threshold"`,
+		`"kind": "unused-parameter"`,
+		`"parameter": "unused"`,
+		`"kind": "orphaned-file"`,
+		`"path": "project/alerting-profile/orphan.json"`,
+	} {
+		assert.Assert(t, strings.Contains(string(content), expected), "expected %s in lint output:\n%s", expected, content)
+	}
+}
+
+func TestLint_SucceedsWithoutWarnings(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	writeValidateTestProject(t, testFs, `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+  type:
+    api: alerting-profile`, `{"name": "{{ .name }}"}`)
+
+	err := Lint(testFs, "manifest.yaml", []string{}, "", []string{}, "")
+	assert.NilError(t, err)
+}
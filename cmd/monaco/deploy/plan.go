@@ -0,0 +1,110 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/deploy"
+	"github.com/spf13/afero"
+	"sort"
+)
+
+// Plan shows the changes a deployment of the given manifest would apply to each environment, without modifying
+// anything. It returns an error if any configuration would be created or updated, so that it can be used to gate
+// deployments in CI pipelines.
+func Plan(fs afero.Fs, deploymentManifestPath string, specificEnvironments []string, environmentGroup string,
+	specificProject []string) error {
+
+	d, err := loadDeployment(fs, deploymentManifestPath, specificEnvironments, environmentGroup, specificProject)
+	if err != nil {
+		return err
+	}
+
+	logProjectsAndEnvironments("Projects to be planned:", d)
+
+	var planErrors []error
+	changedConfigs := 0
+
+	envNames := make([]string, 0, len(d.sortedConfigs))
+	for envName := range d.sortedConfigs {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+
+	for _, envName := range envNames {
+		env, found := d.environments[envName]
+		if !found {
+			planErrors = append(planErrors, fmt.Errorf("cannot find environment `%s`", envName))
+			continue
+		}
+
+		log.Info("Planning configurations for environment `%s`...", envName)
+
+		dtClient, err := client.CreateClientForEnvironment(env)
+		if err != nil {
+			planErrors = append(planErrors, err)
+			continue
+		}
+
+		changes, errs := deploy.PlanConfigs(dtClient, d.apis, d.sortedConfigs[envName])
+		planErrors = append(planErrors, errs...)
+
+		printPlan(envName, changes)
+
+		for _, c := range changes {
+			if c.HasChanges() {
+				changedConfigs++
+			}
+		}
+	}
+
+	if len(planErrors) > 0 {
+		printErrorReport(planErrors)
+		return errors.New("errors during planning")
+	}
+
+	if changedConfigs > 0 {
+		return fmt.Errorf("deployment would change %d configuration(s)", changedConfigs)
+	}
+
+	log.Info("Planning finished: no changes")
+	return nil
+}
+
+var planActionSymbols = map[deploy.PlanAction]string{
+	deploy.PlanActionCreate:    "+",
+	deploy.PlanActionUpdate:    "~",
+	deploy.PlanActionUnchanged: "=",
+	deploy.PlanActionSkip:      "-",
+}
+
+func printPlan(envName string, changes []deploy.PlannedChange) {
+	counts := make(map[deploy.PlanAction]int)
+
+	for _, c := range changes {
+		counts[c.Action]++
+
+		log.Info("  %s %-9s %s", planActionSymbols[c.Action], c.Action, c.Coordinate)
+		for _, diff := range c.Differences {
+			log.Info("        %s", diff)
+		}
+	}
+
+	log.Info("Plan for environment `%s`: %d to create, %d to update, %d unchanged, %d skipped", envName,
+		counts[deploy.PlanActionCreate], counts[deploy.PlanActionUpdate], counts[deploy.PlanActionUnchanged], counts[deploy.PlanActionSkip])
+}
@@ -0,0 +1,104 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/delete"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"sort"
+)
+
+// prune deletes all objects created by monaco from the deployed environments, which are not defined by any config of
+// the manifest's projects anymore. In dry-run mode, the objects are only listed.
+//
+// Pruning is online-only: the objects to prune are found by listing the objects of the environments, so even in
+// dry-run mode a real client is created for each environment, which requires valid tokens. Nothing is modified in
+// dry-run mode.
+func prune(d loadedDeployment, dryRun bool) error {
+	var pruneErrors []error
+
+	envNames := make([]string, 0, len(d.environments))
+	for envName := range d.environments {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+
+	for _, envName := range envNames {
+		log.Info("Searching objects to prune in environment `%s`...", envName)
+
+		dtClient, err := client.CreateClientForEnvironment(d.environments[envName])
+		if err != nil {
+			pruneErrors = append(pruneErrors, err)
+			continue
+		}
+
+		objects, errs := delete.FindObjectsToPrune(dtClient, d.apis, configsOfEnvironment(d.allProjects, envName))
+		if len(errs) > 0 {
+			// don't prune anything if not all objects could be inspected, to not delete objects based on partial information
+			pruneErrors = append(pruneErrors, errs...)
+			continue
+		}
+
+		printPruneSummary(envName, objects, dryRun)
+
+		if dryRun || len(objects) == 0 {
+			continue
+		}
+
+		errs = delete.PruneObjects(dtClient, d.apis, objects)
+		pruneErrors = append(pruneErrors, errs...)
+
+		log.Info("Pruned %d object(s) from environment `%s`", len(objects)-len(errs), envName)
+	}
+
+	if len(pruneErrors) > 0 {
+		printErrorReport(pruneErrors)
+		return errors.New("errors during pruning")
+	}
+
+	return nil
+}
+
+// configsOfEnvironment returns all configs of the given projects for the given environment
+func configsOfEnvironment(projects []project.Project, envName string) []config.Config {
+	var result []config.Config
+	for _, p := range projects {
+		for _, configs := range p.Configs[envName] {
+			result = append(result, configs...)
+		}
+	}
+	return result
+}
+
+func printPruneSummary(envName string, objects []delete.PruneObject, dryRun bool) {
+	if len(objects) == 0 {
+		log.Info("No objects to prune in environment `%s`", envName)
+		return
+	}
+
+	if dryRun {
+		log.Info("The following %d object(s) are not defined in any project and would be pruned from environment `%s`:", len(objects), envName)
+	} else {
+		log.Info("The following %d object(s) are not defined in any project and will be pruned from environment `%s`:", len(objects), envName)
+	}
+
+	for _, o := range objects {
+		log.Info("  - %s", o)
+	}
+}
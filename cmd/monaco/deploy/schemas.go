@@ -0,0 +1,64 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/schema"
+	"github.com/spf13/afero"
+	"sort"
+)
+
+// DownloadSchemas downloads the settings schemas of the given environments of the manifest into the given schema
+// cache folder, which can be used to validate settings configs without access to the environments.
+func DownloadSchemas(fs afero.Fs, deploymentManifestPath string, specificEnvironments []string, environmentGroup string,
+	schemaCache string) error {
+
+	_, environments, _, err := loadManifest(fs, deploymentManifestPath, specificEnvironments, environmentGroup)
+	if err != nil {
+		return err
+	}
+
+	envNames := maps.Keys(environments)
+	sort.Strings(envNames)
+
+	var downloadErrors []error
+	for _, envName := range envNames {
+		log.Info("Downloading settings schemas of environment `%s`...", envName)
+
+		dtClient, err := client.CreateClientForEnvironment(environments[envName])
+		if err != nil {
+			downloadErrors = append(downloadErrors, err)
+			continue
+		}
+
+		count, err := schema.Download(dtClient, fs, schemaCache)
+		if err != nil {
+			downloadErrors = append(downloadErrors, err)
+		}
+
+		log.Info("Downloaded %d settings schemas of environment `%s` to %q", count, envName, schemaCache)
+	}
+
+	if len(downloadErrors) > 0 {
+		printErrorReport(downloadErrors)
+		return errors.New("errors during download of settings schemas")
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/jsonschema"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	envParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/environment"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/schema"
+	"github.com/dynatrace/dynatrace-configuration-as-code/schemas"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// environmentVariablePlaceholder is used as value of environment variable parameters whose variable is not set during
+// validation. It is valid JSON both within strings and as plain value, so that rendered templates stay valid.
+const environmentVariablePlaceholder = "0"
+
+// Validate validates the given manifest and its projects without any access to the environments. Manifest and
+// configs are loaded, references are resolved and sorted, and each config is rendered and checked to be valid JSON.
+// Environment variable parameters whose variable is not set are replaced by a placeholder, and no tokens are required.
+// Before loading, the manifest is validated against the manifest JSON schema, see schemas.Manifest.
+// If schemaCache is set, rendered settings objects are additionally validated against the cached settings schemas.
+func Validate(fs afero.Fs, deploymentManifestPath string, specificEnvironments []string, environmentGroup string,
+	specificProject []string, schemaCache string) error {
+
+	if errs := validateManifestSchema(fs, deploymentManifestPath); len(errs) > 0 {
+		printErrorReport(errs)
+		return fmt.Errorf("manifest %q does not match the manifest schema", deploymentManifestPath)
+	}
+
+	d, err := loadDeployment(fs, deploymentManifestPath, specificEnvironments, environmentGroup, specificProject)
+	if err != nil {
+		return err
+	}
+
+	var validationClient client.Client = client.NewDummyClient()
+	if schemaCache != "" {
+		cache, err := schema.LoadCache(fs, schemaCache)
+		if err != nil {
+			return err
+		}
+		validationClient = schema.NewValidatingClient(validationClient, cache)
+	}
+
+	logProjectsAndEnvironments("Projects to be validated:", d)
+
+	envNames := make([]string, 0, len(d.sortedConfigs))
+	for envName := range d.sortedConfigs {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+
+	var validationErrors []error
+	for _, envName := range envNames {
+		if _, found := d.environments[envName]; !found {
+			validationErrors = append(validationErrors, fmt.Errorf("cannot find environment `%s`", envName))
+			continue
+		}
+
+		log.Info("Validating configurations for environment `%s`...", envName)
+
+		configs := stubEnvironmentVariableParameters(d.sortedConfigs[envName])
+
+		errs := deploy.DeployConfigs(validationClient, d.apis, configs, deploy.DeployConfigsOptions{
+			ContinueOnErr: true,
+			DryRun:        true,
+		})
+		validationErrors = append(validationErrors, errs...)
+	}
+
+	if len(validationErrors) > 0 {
+		printErrorReport(validationErrors)
+		return errors.New("errors during validation")
+	}
+
+	log.Info("Validation finished without errors")
+	return nil
+}
+
+// validateManifestSchema validates the YAML of the manifest at the given path against the manifest JSON schema
+func validateManifestSchema(fs afero.Fs, manifestPath string) []error {
+	manifestSchema, err := jsonschema.Parse(schemas.Manifest)
+	if err != nil {
+		return []error{err}
+	}
+
+	absPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return []error{fmt.Errorf("failed to resolve path of manifest %q: %w", manifestPath, err)}
+	}
+
+	data, err := afero.ReadFile(fs, absPath)
+	if err != nil {
+		return []error{fmt.Errorf("failed to read manifest %q: %w", manifestPath, err)}
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return []error{fmt.Errorf("failed to parse manifest %q: %w", manifestPath, err)}
+	}
+
+	var errs []error
+	for _, validationErr := range manifestSchema.Validate(document) {
+		errs = append(errs, fmt.Errorf("manifest %q: %w", manifestPath, validationErr))
+	}
+	return errs
+}
+
+// stubEnvironmentVariableParameters returns copies of the given configs, in which all environment variable parameters
+// referencing unset variables without default value are replaced by a placeholder value.
+func stubEnvironmentVariableParameters(configs []config.Config) []config.Config {
+	result := make([]config.Config, len(configs))
+
+	for i, c := range configs {
+		parameters := make(config.Parameters, len(c.Parameters))
+
+		for name, p := range c.Parameters {
+			parameters[name] = p
+
+			envVarParam, ok := p.(*envParam.EnvironmentVariableParameter)
+			if !ok || envVarParam.HasDefaultValue {
+				continue
+			}
+
+			if _, found := os.LookupEnv(envVarParam.Name); !found {
+				log.Debug("Environment variable %q of parameter %q of config %s is not set, using a placeholder", envVarParam.Name, name, c.Coordinate)
+				parameters[name] = valueParam.New(environmentVariablePlaceholder)
+			}
+		}
+
+		c.Parameters = parameters
+		result[i] = c
+	}
+
+	return result
+}
@@ -0,0 +1,173 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"path/filepath"
+	"testing"
+)
+
+const validateTestManifest = "manifestVersion: 1.0\nprojects:\n- name: project\nenvironmentGroups:\n- name: default\n  environments:\n  - name: environment1\n    url:\n      type: environment\n      value: VALIDATE_TEST_ENV_URL\n    token:\n      name: VALIDATE_TEST_ENV_TOKEN\n"
+
+func writeValidateTestProject(t *testing.T, fs afero.Fs, config string, template string) {
+	for path, content := range map[string]string{
+		"manifest.yaml":                         validateTestManifest,
+		"project/alerting-profile/profile.yaml": config,
+		"project/alerting-profile/profile.json": template,
+	} {
+		abs, err := filepath.Abs(path)
+		assert.NilError(t, err)
+		assert.NilError(t, afero.WriteFile(fs, abs, []byte(content), 0644))
+	}
+}
+
+func TestValidate_SucceedsWithoutTokensAndEnvironmentVariables(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	writeValidateTestProject(t, testFs, `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    parameters:
+      threshold:
+        type: environment
+        name: VALIDATE_TEST_UNSET_VARIABLE
+  type:
+    api: alerting-profile
+- id: other-profile
+  config:
+    name: Other Profile
+    template: profile.json
+    parameters:
+      threshold: 5
+      profileId: [profile, id]
+  type:
+    api: alerting-profile`, `{"name": "{{ .name }}", "threshold": {{ .threshold }}}`)
+
+	err := Validate(testFs, "manifest.yaml", []string{}, "", []string{}, "")
+	assert.NilError(t, err)
+}
+
+func TestValidate_ReportsInvalidJson(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	writeValidateTestProject(t, testFs, `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+  type:
+    api: alerting-profile`, `{"name": "{{ .name }}",}`)
+
+	err := Validate(testFs, "manifest.yaml", []string{}, "", []string{}, "")
+	assert.ErrorContains(t, err, "errors during validation")
+}
+
+func TestValidate_ReportsUnresolvableReferences(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	writeValidateTestProject(t, testFs, `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    parameters:
+      other: [missing, id]
+  type:
+    api: alerting-profile`, `{"name": "{{ .name }}"}`)
+
+	err := Validate(testFs, "manifest.yaml", []string{}, "", []string{}, "")
+	assert.Assert(t, err != nil, "expected validation to fail for unresolvable reference")
+}
+
+func TestValidate_ValidatesManifestAgainstSchema(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	writeValidateTestProject(t, testFs, `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+  type:
+    api: alerting-profile`, `{"name": "{{ .name }}"}`)
+
+	manifestPath, err := filepath.Abs("manifest.yaml")
+	assert.NilError(t, err)
+	assert.NilError(t, afero.WriteFile(testFs, manifestPath, []byte(validateTestManifest+"    rateLimit:\n      strategy: token-bucket\n      burst: -1\n"), 0644))
+
+	errs := validateManifestSchema(testFs, "manifest.yaml")
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorContains(t, errs[0], "environmentGroups[0].environments[0].rateLimit.burst: expected a value of at least 0, but got -1")
+
+	err = Validate(testFs, "manifest.yaml", []string{}, "", []string{}, "")
+	assert.ErrorContains(t, err, "does not match the manifest schema")
+}
+
+func TestValidate_ValidatesSettingsAgainstSchemaCache(t *testing.T) {
+	settingsConfig := `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+  type:
+    settings:
+      schema: builtin:alerting.profile
+      scope: environment`
+
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{
+			name:     "valid settings object",
+			template: `{"name": "{{ .name }}", "severity": "ERROR"}`,
+		},
+		{
+			name:     "invalid settings object",
+			template: `{"name": "{{ .name }}", "severity": "WARNING"}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFs := afero.NewMemMapFs()
+			writeValidateTestProject(t, testFs, settingsConfig, tt.template)
+			assert.NilError(t, afero.WriteFile(testFs, filepath.Join("schemas", "profile.json"), []byte(`{
+  "schemaId": "builtin:alerting.profile",
+  "version": "1.0.0",
+  "properties": {
+    "name": {"type": "text", "nullable": false},
+    "severity": {"type": {"$ref": "#/enums/Severity"}, "nullable": false}
+  },
+  "enums": {"Severity": {"items": [{"value": "AVAILABILITY"}, {"value": "ERROR"}]}}
+}`), 0644))
+
+			err := Validate(testFs, "manifest.yaml", []string{}, "", []string{}, "schemas")
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "errors during validation")
+			} else {
+				assert.NilError(t, err)
+			}
+		})
+	}
+}
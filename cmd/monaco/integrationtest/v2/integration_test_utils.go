@@ -97,7 +97,9 @@ func AssertAllConfigsAvailability(t *testing.T, fs afero.Fs, manifestPath string
 		c, err := client.CreateClientForEnvironment(env)
 		assert.NilError(t, err)
 
+		apis := api.NewApis()
 		entities := make(map[coordinate.Coordinate]parameter.ResolvedEntity)
+		lookup := deploy.NewEnvironmentLookup(c, apis)
 		var parameters []topologysort.ParameterWithName
 
 		for _, theConfig := range configs {
@@ -118,7 +120,7 @@ func AssertAllConfigsAvailability(t *testing.T, fs afero.Fs, manifestPath string
 
 			parameters = append(parameters, configParameters...)
 
-			properties, errs := deploy.ResolveParameterValues(&theConfig, entities, parameters)
+			properties, errs := deploy.ResolveParameterValues(&theConfig, entities, parameters, lookup)
 			testutils.FailTestOnAnyError(t, errs, "resolving of parameter values failed")
 
 			properties[config.IdParameter] = "NO REAL ID NEEDED FOR CHECKING AVAILABILITY"
@@ -133,7 +135,6 @@ func AssertAllConfigsAvailability(t *testing.T, fs afero.Fs, manifestPath string
 				Skip:       false,
 			}
 
-			apis := api.NewApis()
 			if _, found := projectsToValidate[coord.Project]; found {
 				if theConfig.Type.IsSettings() {
 					assertSetting(t, c, env, available, theConfig)
@@ -20,10 +20,16 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/featureflags"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/selector"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/graph"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/state"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/version"
 	"io"
 	"os"
 	"path"
+	"time"
 
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -35,6 +41,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/deploy"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/download"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/secret"
 )
 
 var errWrongUsage = errors.New("")
@@ -45,6 +52,7 @@ func Run() int {
 	rootCmd := BuildCli(afero.NewOsFs())
 
 	err := rootCmd.Execute()
+	rest.LogRateLimitStatistics()
 
 	if err != nil {
 		if !errors.Is(err, errWrongUsage) {
@@ -66,6 +74,8 @@ func BuildCliWithCapturedLog(fs afero.Fs, logOutput io.Writer) *cobra.Command {
 
 func BuildCli(fs afero.Fs) *cobra.Command {
 	var verbose bool
+	var maxRetries int
+	var maxRetryTime time.Duration
 
 	var rootCmd = &cobra.Command{
 		Use:   "monaco <command>",
@@ -78,7 +88,10 @@ Examples:
   Deploy a specific environment within an manifest
     monaco deploy service.yaml -e dev`,
 
-		PersistentPreRun: configureDebugLogging(fs, &verbose),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			configureDebugLogging(fs, &verbose)(cmd, args)
+			return configureRetryPolicy(cmd, maxRetries, maxRetryTime)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			_ = cmd.Help()
 		},
@@ -86,11 +99,20 @@ Examples:
 
 	// global flags
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable debug logging")
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", rest.DefaultRetryPolicy.MaxRetries, "Maximum number of retries of a failing request. Overrides "+rest.RetryMaxRetriesEnvKey)
+	rootCmd.PersistentFlags().DurationVar(&maxRetryTime, "max-retry-time", rest.DefaultRetryPolicy.MaxElapsedTime, "Time after which failing requests are not retried anymore. Overrides "+rest.RetryMaxElapsedTimeEnvKey)
 
 	// commands
 	downloadCommand := download.GetDownloadCommand(fs, &download.DefaultCommand{})
 	convertCommand := getConvertCommand(fs)
 	deployCommand := getDeployCommand(fs)
+	planCommand := getPlanCommand(fs)
+	driftCommand := getDriftCommand(fs)
+	validateCommand := getValidateCommand(fs)
+	graphCommand := getGraphCommand(fs)
+	lintCommand := getLintCommand(fs)
+	schemasCommand := getSchemasCommand(fs)
+	secretCommand := secret.GetSecretCommand(fs)
 	deleteCommand := getDeleteCommand(fs)
 	purgeCommand := getPurgeCommand(fs)
 	versionCommand := getVersionCommand()
@@ -98,6 +120,13 @@ Examples:
 	rootCmd.AddCommand(downloadCommand)
 	rootCmd.AddCommand(convertCommand)
 	rootCmd.AddCommand(deployCommand)
+	rootCmd.AddCommand(planCommand)
+	rootCmd.AddCommand(driftCommand)
+	rootCmd.AddCommand(validateCommand)
+	rootCmd.AddCommand(graphCommand)
+	rootCmd.AddCommand(lintCommand)
+	rootCmd.AddCommand(schemasCommand)
+	rootCmd.AddCommand(secretCommand)
 	rootCmd.AddCommand(deleteCommand)
 	rootCmd.AddCommand(versionCommand)
 
@@ -111,6 +140,29 @@ Examples:
 	return rootCmd
 }
 
+// configureRetryPolicy sets the retry policy of all requests from the environment variables, overridden by the
+// global flags if they are set
+func configureRetryPolicy(cmd *cobra.Command, maxRetries int, maxRetryTime time.Duration) error {
+	policy, err := rest.RetryPolicyFromEnv()
+	if err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("max-retries") {
+		policy.MaxRetries = maxRetries
+	}
+	if cmd.Flags().Changed("max-retry-time") {
+		policy.MaxElapsedTime = maxRetryTime
+	}
+
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("invalid retry settings: %w", err)
+	}
+
+	rest.SetRetryPolicy(policy)
+	return nil
+}
+
 func configureDebugLogging(fs afero.Fs, verbose *bool) func(cmd *cobra.Command, args []string) {
 	return func(cmd *cobra.Command, args []string) {
 		if *verbose {
@@ -121,9 +173,9 @@ func configureDebugLogging(fs afero.Fs, verbose *bool) func(cmd *cobra.Command,
 }
 
 func getDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
-	var dryRun, continueOnError bool
-	var manifestName, group string
-	var environment, project []string
+	var dryRun, online, continueOnError, parallel, useState, prune, force, rollback, skipUnchanged bool
+	var manifestName, group, reportFile, reportFormat, schemaCache string
+	var environment, project, only, exclude []string
 
 	deployCmd = &cobra.Command{
 		Use:               "deploy <manifest.yaml>",
@@ -141,7 +193,44 @@ func getDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
 				return err
 			}
 
-			return deploy.Deploy(fs, manifestName, environment, group, project, dryRun, continueOnError)
+			if online && !dryRun {
+				return errors.New("'--online' can only be used together with '--dry-run'")
+			}
+
+			if prune && !dryRun && !force {
+				return errors.New("'--prune' deletes objects from the environments and needs to be confirmed using '--force'. Use '--dry-run' to only list the objects to prune")
+			}
+
+			format, err := report.ParseFormat(reportFormat)
+			if err != nil {
+				return err
+			}
+
+			onlySelectors, err := selector.ParseAll(only)
+			if err != nil {
+				return err
+			}
+
+			excludeSelectors, err := selector.ParseAll(exclude)
+			if err != nil {
+				return err
+			}
+
+			return deploy.Deploy(fs, manifestName, environment, group, project, deploy.Options{
+				DryRun:          dryRun,
+				Online:          online,
+				ContinueOnError: continueOnError,
+				Parallel:        parallel,
+				UseState:        useState,
+				Prune:           prune,
+				ReportFile:      reportFile,
+				ReportFormat:    format,
+				Rollback:        rollback,
+				SkipUnchanged:   skipUnchanged,
+				Only:            onlySelectors,
+				Exclude:         excludeSelectors,
+				SchemaCache:     schemaCache,
+			})
 		},
 	}
 
@@ -149,7 +238,19 @@ func getDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
 	deployCmd.Flags().StringVarP(&group, "group", "g", "", "Specify the environmentGroup that should be used for deployment. If this flag is specified, all environments within this group will be used for deployment. This flag is mutually exclusive with '--environment'")
 	deployCmd.Flags().StringSliceVarP(&project, "project", "p", make([]string, 0), "Project configuration to deploy (also deploys any dependent configurations)")
 	deployCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Switches to just validation instead of actual deployment")
+	deployCmd.Flags().BoolVar(&online, "online", false, "Validate configurations against the environments during a dry-run, using the validation endpoints of the Dynatrace APIs. Settings objects are sent with 'validateOnly', classic configurations to their API's validator, if it provides one. Nothing is persisted. Requires '--dry-run' and valid tokens")
 	deployCmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "c", false, "Proceed deployment even if config upload fails")
+	deployCmd.Flags().BoolVar(&parallel, "parallel", false, "Deploy configurations that do not depend on each other in parallel. The amount of concurrent requests can be limited using the CONCURRENT_REQUESTS environment variable (default: 10)")
+	deployCmd.Flags().BoolVar(&useState, "state", false, "Track the Dynatrace objects of deployed configurations in a state file ("+state.FileName+") next to the manifest. Known objects are updated by their ID, which allows renaming configurations. Not used during dry-runs")
+	deployCmd.Flags().BoolVar(&prune, "prune", false, "After a successful deployment, delete all objects created by monaco which are not defined in any project of the manifest anymore. Requires '--force'. Combined with '--dry-run', the objects are only listed. Finding the objects to prune always reads the environments, so valid tokens are required even in a dry-run")
+	deployCmd.Flags().BoolVar(&force, "force", false, "Confirm deleting objects from the environments when using '--prune' without '--dry-run'")
+	deployCmd.Flags().StringVar(&reportFile, "report-file", "", "Write a machine-readable report of the outcome of each configuration to the given file")
+	deployCmd.Flags().StringVar(&reportFormat, "report-format", string(report.FormatJSON), "The format of the report file. One of 'json' or 'junit'")
+	deployCmd.Flags().BoolVar(&rollback, "rollback", false, "Snapshot each object before modifying it. If the deployment to an environment fails, all modified objects are restored and all created objects are deleted")
+	deployCmd.Flags().StringArrayVar(&only, "only", nil, "Only deploy configurations matching the selector, and the configurations they depend on. Selectors are globs matched against the coordinate 'project:type:configId', or against a single property using 'project=', 'api=', 'schema=' or 'configId='. Can be repeated")
+	deployCmd.Flags().StringArrayVar(&exclude, "exclude", nil, "Do not deploy configurations matching the selector. Uses the same syntax as '--only'. Fails if an excluded configuration is required by a deployed one. Can be repeated")
+	deployCmd.Flags().BoolVar(&skipUnchanged, "skip-unchanged", false, "Read the existing object of each configuration before deploying it, and skip the update if it already equals the configuration. Classic configurations are only skipped if the existing object has no additional fields, as those are removed by the update. Not used during dry-runs")
+	deployCmd.Flags().StringVar(&schemaCache, "schema-cache", "", "Validate settings objects against the settings schemas cached in the given folder before deploying them. The cache is created using 'monaco schemas'")
 
 	err := deployCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag)
 	if err != nil {
@@ -166,6 +267,265 @@ func getDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
 	return deployCmd
 }
 
+func getPlanCommand(fs afero.Fs) (planCmd *cobra.Command) {
+	var manifestName, group string
+	var environment, project []string
+
+	planCmd = &cobra.Command{
+		Use:               "plan <manifest.yaml>",
+		Short:             "Show which configurations a deployment would create, update or leave unchanged",
+		Long:              "Show which configurations a deployment would create, update or leave unchanged, without modifying anything. Exits with a non-zero code if a deployment would change any configuration.",
+		Example:           "monaco plan manifest.yaml -e dev-environment",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            silenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			manifestName = args[0]
+
+			if !files.IsYamlFileExtension(manifestName) {
+				err := fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", manifestName)
+				return err
+			}
+
+			return deploy.Plan(fs, manifestName, environment, group, project)
+		},
+	}
+
+	planCmd.Flags().StringSliceVarP(&environment, "environment", "e", make([]string, 0), "Specify one (or multiple) environments to plan. To set multiple environments either repeat this flag, or seperate them using a comma (,). This flag is mutually exclusive with '--group'.")
+	planCmd.Flags().StringVarP(&group, "group", "g", "", "Specify the environmentGroup that should be planned. This flag is mutually exclusive with '--environment'")
+	planCmd.Flags().StringSliceVarP(&project, "project", "p", make([]string, 0), "Project configuration to plan (also plans any dependent configurations)")
+
+	if err := planCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	if err := planCmd.RegisterFlagCompletionFunc("project", completion.ProjectsFromManifest); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	planCmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return planCmd
+}
+
+func getDriftCommand(fs afero.Fs) (driftCmd *cobra.Command) {
+	var manifestName, group, outputFile string
+	var environment, project []string
+
+	driftCmd = &cobra.Command{
+		Use:               "drift <manifest.yaml>",
+		Short:             "Detect configurations that were changed in Dynatrace environments",
+		Long:              "Compare the configurations of all projects against the live objects of each environment, and report all differing fields. Nothing is modified. Exits with a non-zero code if any configuration drifted or is missing.",
+		Example:           "monaco drift manifest.yaml -e dev-environment --output-file drift.json",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            silenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			manifestName = args[0]
+
+			if !files.IsYamlFileExtension(manifestName) {
+				err := fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", manifestName)
+				return err
+			}
+
+			return deploy.Drift(fs, manifestName, environment, group, project, outputFile)
+		},
+	}
+
+	driftCmd.Flags().StringSliceVarP(&environment, "environment", "e", make([]string, 0), "Specify one (or multiple) environments to check for drift. To set multiple environments either repeat this flag, or seperate them using a comma (,). This flag is mutually exclusive with '--group'.")
+	driftCmd.Flags().StringVarP(&group, "group", "g", "", "Specify the environmentGroup that should be checked for drift. This flag is mutually exclusive with '--environment'")
+	driftCmd.Flags().StringSliceVarP(&project, "project", "p", make([]string, 0), "Project configuration to check for drift (also checks any dependent configurations)")
+	driftCmd.Flags().StringVar(&outputFile, "output-file", "", "Additionally write the detected drift as JSON to the given file")
+
+	if err := driftCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	if err := driftCmd.RegisterFlagCompletionFunc("project", completion.ProjectsFromManifest); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	driftCmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return driftCmd
+}
+
+func getValidateCommand(fs afero.Fs) (validateCmd *cobra.Command) {
+	var manifestName, group, schemaCache string
+	var environment, project []string
+
+	validateCmd = &cobra.Command{
+		Use:               "validate <manifest.yaml>",
+		Short:             "Validate configurations without access to Dynatrace environments",
+		Long:              "Load the manifest and all projects, resolve and sort all references, and render every configuration to check it is valid JSON. No requests are sent and no tokens are required. Environment variable parameters without value are replaced by a placeholder. The manifest is validated against the manifest JSON schema before it is loaded.",
+		Example:           "monaco validate manifest.yaml -e dev-environment",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            silenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			manifestName = args[0]
+
+			if !files.IsYamlFileExtension(manifestName) {
+				err := fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", manifestName)
+				return err
+			}
+
+			return deploy.Validate(fs, manifestName, environment, group, project, schemaCache)
+		},
+	}
+
+	validateCmd.Flags().StringSliceVarP(&environment, "environment", "e", make([]string, 0), "Specify one (or multiple) environments to validate. To set multiple environments either repeat this flag, or seperate them using a comma (,). This flag is mutually exclusive with '--group'.")
+	validateCmd.Flags().StringVarP(&group, "group", "g", "", "Specify the environmentGroup that should be validated. This flag is mutually exclusive with '--environment'")
+	validateCmd.Flags().StringSliceVarP(&project, "project", "p", make([]string, 0), "Project configuration to validate (also validates any dependent configurations)")
+	validateCmd.Flags().StringVar(&schemaCache, "schema-cache", "", "Validate settings objects against the settings schemas cached in the given folder. The cache is created using 'monaco schemas'")
+
+	if err := validateCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	if err := validateCmd.RegisterFlagCompletionFunc("project", completion.ProjectsFromManifest); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	validateCmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return validateCmd
+}
+
+func getGraphCommand(fs afero.Fs) (graphCmd *cobra.Command) {
+	var manifestName, group, format, outputFile string
+	var environment, project, types []string
+
+	graphCmd = &cobra.Command{
+		Use:               "graph <manifest.yaml>",
+		Short:             "Print the dependency graph of configurations",
+		Long:              "Print the dependency graph of the configurations of each environment, with an edge for each reference labeled by the referencing parameters. Configurations forming circular dependencies are highlighted.",
+		Example:           "monaco graph manifest.yaml -e dev-environment --format mermaid",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            silenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			manifestName = args[0]
+
+			if !files.IsYamlFileExtension(manifestName) {
+				err := fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", manifestName)
+				return err
+			}
+
+			graphFormat, err := graph.ParseFormat(format)
+			if err != nil {
+				return err
+			}
+
+			return deploy.Graph(fs, manifestName, environment, group, graph.Filter{Projects: project, Types: types}, graphFormat, outputFile, cmd.OutOrStdout())
+		},
+	}
+
+	graphCmd.Flags().StringSliceVarP(&environment, "environment", "e", make([]string, 0), "Specify one (or multiple) environments to print the graph of. To set multiple environments either repeat this flag, or seperate them using a comma (,). This flag is mutually exclusive with '--group'.")
+	graphCmd.Flags().StringVarP(&group, "group", "g", "", "Specify the environmentGroup that should be printed. This flag is mutually exclusive with '--environment'")
+	graphCmd.Flags().StringSliceVarP(&project, "project", "p", make([]string, 0), "Only include the configurations of the given projects, and the configurations they depend on or are required by")
+	graphCmd.Flags().StringSliceVarP(&types, "type", "t", make([]string, 0), "Only include the configurations of the given APIs or settings schemas, and the configurations they depend on or are required by")
+	graphCmd.Flags().StringVar(&format, "format", string(graph.FormatDOT), "The format of the graph. One of 'dot', 'mermaid' or 'json'")
+	graphCmd.Flags().StringVar(&outputFile, "output-file", "", "Write the graph to the given file instead of the standard output")
+
+	if err := graphCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	if err := graphCmd.RegisterFlagCompletionFunc("project", completion.ProjectsFromManifest); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	graphCmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return graphCmd
+}
+
+func getLintCommand(fs afero.Fs) (lintCmd *cobra.Command) {
+	var manifestName, group, outputFile string
+	var environment, project []string
+
+	lintCmd = &cobra.Command{
+		Use:               "lint <manifest.yaml>",
+		Short:             "Find likely mistakes in configurations",
+		Long:              "Report templates referencing parameters a configuration does not define, parameters neither used by the template nor referenced by other configurations, and JSON files in project folders no configuration uses. Fails if any warning is found.",
+		Example:           "monaco lint manifest.yaml -e dev-environment --output-file lint.json",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            silenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			manifestName = args[0]
+
+			if !files.IsYamlFileExtension(manifestName) {
+				err := fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", manifestName)
+				return err
+			}
+
+			return deploy.Lint(fs, manifestName, environment, group, project, outputFile)
+		},
+	}
+
+	lintCmd.Flags().StringSliceVarP(&environment, "environment", "e", make([]string, 0), "Specify one (or multiple) environments to lint. To set multiple environments either repeat this flag, or seperate them using a comma (,). This flag is mutually exclusive with '--group'.")
+	lintCmd.Flags().StringVarP(&group, "group", "g", "", "Specify the environmentGroup that should be linted. This flag is mutually exclusive with '--environment'")
+	lintCmd.Flags().StringSliceVarP(&project, "project", "p", make([]string, 0), "Project configuration to lint")
+	lintCmd.Flags().StringVar(&outputFile, "output-file", "", "Write the warnings as JSON to the given file")
+
+	if err := lintCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	if err := lintCmd.RegisterFlagCompletionFunc("project", completion.ProjectsFromManifest); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	lintCmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return lintCmd
+}
+
+func getSchemasCommand(fs afero.Fs) (schemasCmd *cobra.Command) {
+	var manifestName, group, schemaCache string
+	var environment []string
+
+	schemasCmd = &cobra.Command{
+		Use:               "schemas <manifest.yaml>",
+		Short:             "Download settings schemas into a local cache",
+		Long:              "Download the latest version of all Settings 2.0 schemas of the environments into a local cache folder. Previously downloaded versions are kept. The cache is used by 'validate' and 'deploy' to validate settings configurations without access to the environments.",
+		Example:           "monaco schemas manifest.yaml -e dev-environment --schema-cache schemas",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            silenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			manifestName = args[0]
+
+			if !files.IsYamlFileExtension(manifestName) {
+				err := fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", manifestName)
+				return err
+			}
+
+			return deploy.DownloadSchemas(fs, manifestName, environment, group, schemaCache)
+		},
+	}
+
+	schemasCmd.Flags().StringSliceVarP(&environment, "environment", "e", make([]string, 0), "Specify one (or multiple) environments to download the schemas of. To set multiple environments either repeat this flag, or seperate them using a comma (,). This flag is mutually exclusive with '--group'.")
+	schemasCmd.Flags().StringVarP(&group, "group", "g", "", "Specify the environmentGroup to download the schemas of. This flag is mutually exclusive with '--environment'")
+	schemasCmd.Flags().StringVar(&schemaCache, "schema-cache", "schemas", "The folder to store the downloaded schemas in")
+
+	if err := schemasCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	schemasCmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return schemasCmd
+}
+
 // silenceUsageCommand gives back a command that is just configured to skip printing of usage info.
 // We use it as a PreRun hook to enforce the behavior of printing usage info when the command structure
 // given by the user is faulty
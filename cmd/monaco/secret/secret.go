@@ -0,0 +1,99 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/secret"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"io"
+	"strings"
+)
+
+// GetSecretCommand returns the `secret` command, which groups helpers to work with secret parameters
+func GetSecretCommand(fs afero.Fs) *cobra.Command {
+	secretCmd := &cobra.Command{
+		Use:   "secret",
+		Short: "Helpers to work with encrypted secret parameters",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("'encrypt' sub-command is required")
+		},
+	}
+
+	secretCmd.AddCommand(getEncryptCommand(fs))
+
+	return secretCmd
+}
+
+func getEncryptCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "encrypt [value]",
+		Short: "Encrypt a value to be used in a secret parameter",
+		Long: fmt.Sprintf(`Encrypt a value to be used in a secret parameter
+
+The key is read from the environment variable %s, or from the file referenced by %s.
+The key needs to be 32 random bytes encoded in base64, e.g. generated by 'openssl rand -base64 32'.
+
+If no value is given as argument, it is read from stdin. This prevents the value from ending up in the shell history.
+
+The printed encrypted value can be used in a config as follows:
+
+  parameters:
+    token:
+      type: secret
+      value: <encrypted value>`, secret.EnvKeySecretKey, secret.EnvKeySecretKeyFile),
+		Example: `- monaco secret encrypt < token.txt
+- monaco secret encrypt my-secret-value`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			var value string
+			if len(args) == 1 {
+				value = args[0]
+			} else {
+				input, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("failed to read value from stdin: %w", err)
+				}
+				value = strings.TrimRight(string(input), "\r\n")
+			}
+
+			encrypted, err := Encrypt(fs, value)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), encrypted)
+			return err
+		},
+	}
+}
+
+// Encrypt encrypts the given value with the configured key, see secret.LoadKey
+func Encrypt(fs afero.Fs, value string) (string, error) {
+	if value == "" {
+		return "", errors.New("value to encrypt must not be empty")
+	}
+
+	key, err := secret.LoadKey(fs)
+	if err != nil {
+		return "", err
+	}
+
+	return secret.Encrypt(key, []byte(value))
+}
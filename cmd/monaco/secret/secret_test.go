@@ -0,0 +1,54 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/secret"
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"strings"
+	"testing"
+)
+
+func TestEncryptCommandReadsValueFromStdin(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	t.Setenv(secret.EnvKeySecretKey, base64.StdEncoding.EncodeToString(key))
+
+	cmd := GetSecretCommand(afero.NewMemMapFs())
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetIn(strings.NewReader("my-token\n"))
+	cmd.SetArgs([]string{"encrypt"})
+
+	assert.NilError(t, cmd.Execute())
+
+	decrypted, err := secret.Decrypt(key, strings.TrimSpace(out.String()))
+	assert.NilError(t, err)
+	assert.Equal(t, string(decrypted), "my-token")
+}
+
+func TestEncryptFailsWithoutKey(t *testing.T) {
+	_, err := Encrypt(afero.NewMemMapFs(), "my-token")
+	assert.ErrorContains(t, err, "no secret key configured")
+}
+
+func TestEncryptFailsOnEmptyValue(t *testing.T) {
+	_, err := Encrypt(afero.NewMemMapFs(), "")
+	assert.ErrorContains(t, err, "must not be empty")
+}
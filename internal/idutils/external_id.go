@@ -21,11 +21,14 @@ import (
 	"fmt"
 )
 
+// ExternalIDPrefix is the prefix of all external IDs generated by GenerateExternalID
+const ExternalIDPrefix = "monaco:"
+
 // GenerateExternalID generates the externalID for settings 2.0 objects based on the schema, and ID.
 // The result of the function is pure.
 // Max length for the external ID is 500
 func GenerateExternalID(schema, ID string) string {
-	const prefix = "monaco:"
+	const prefix = ExternalIDPrefix
 	const format = "%s$%s"
 	const externalIDMaxLength = 500
 
@@ -37,7 +40,7 @@ func GenerateExternalID(schema, ID string) string {
 		encodedID = encodedID[encodedIDMaxLength:]
 	}
 
-	externalID := fmt.Sprintf("monaco:%s", encodedID)
+	externalID := prefix + encodedID
 
 	return externalID
 }
@@ -0,0 +1,161 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Difference describes a single differing value between two JSON documents
+type Difference struct {
+	// Path is the location of the differing value in the document, e.g. `rules[0].enabled`
+	Path string
+	// Expected is the value in the expected document
+	Expected any
+	// Actual is the value in the actual document, nil if the value is not present
+	Actual any
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Path, toJsonString(d.Actual), toJsonString(d.Expected))
+}
+
+// toJsonString returns the given value as JSON. HTML characters are not escaped, so that values like secrets are
+// printed as they are and can be redacted from logs, see log.Redact.
+func toJsonString(v any) string {
+	if v == nil {
+		return "<missing>"
+	}
+
+	var b strings.Builder
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Diff semantically compares the expected JSON document to the actual one, ignoring formatting and the order of keys.
+//
+// Only values present in the expected document are compared. Fields only present in the actual document are
+// considered to be server-managed or defaulted by the Dynatrace API and are not reported as differences.
+// Additionally, top level keys given in ignoredKeys are not compared at all, e.g. `id` or `metadata`.
+//
+// The returned differences are sorted by their path. If both documents are equal, an empty slice is returned.
+func Diff(expected, actual []byte, ignoredKeys ...string) ([]Difference, error) {
+	return diff(expected, actual, false, ignoredKeys)
+}
+
+// DiffExact works like Diff, but also reports fields only present in the actual document, with a nil Expected value.
+// It is used where the actual document is replaced by the expected one, which removes all fields not present in it.
+func DiffExact(expected, actual []byte, ignoredKeys ...string) ([]Difference, error) {
+	return diff(expected, actual, true, ignoredKeys)
+}
+
+func diff(expected, actual []byte, exact bool, ignoredKeys []string) ([]Difference, error) {
+	var expectedValue, actualValue any
+
+	if err := json.Unmarshal(expected, &expectedValue); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal expected json: %w", err)
+	}
+
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal actual json: %w", err)
+	}
+
+	expectedValue = withoutKeys(expectedValue, ignoredKeys)
+	actualValue = withoutKeys(actualValue, ignoredKeys)
+
+	return diffValues("", expectedValue, actualValue, exact), nil
+}
+
+// withoutKeys removes the given keys from the value if it is a JSON object
+func withoutKeys(value any, keys []string) any {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	for _, k := range keys {
+		delete(obj, k)
+	}
+	return obj
+}
+
+func diffValues(path string, expected, actual any, exact bool) []Difference {
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			return []Difference{{Path: pathOrRoot(path), Expected: expected, Actual: actual}}
+		}
+
+		keys := make([]string, 0, len(e))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		if exact {
+			for k := range a {
+				if _, found := e[k]; !found {
+					keys = append(keys, k)
+				}
+			}
+		}
+		sort.Strings(keys)
+
+		differences := make([]Difference, 0)
+		for _, k := range keys {
+			differences = append(differences, diffValues(joinPath(path, k), e[k], a[k], exact)...)
+		}
+		return differences
+
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(a) != len(e) {
+			return []Difference{{Path: pathOrRoot(path), Expected: expected, Actual: actual}}
+		}
+
+		differences := make([]Difference, 0)
+		for i := range e {
+			differences = append(differences, diffValues(fmt.Sprintf("%s[%d]", path, i), e[i], a[i], exact)...)
+		}
+		return differences
+
+	default:
+		if !reflect.DeepEqual(expected, actual) {
+			return []Difference{{Path: pathOrRoot(path), Expected: expected, Actual: actual}}
+		}
+		return []Difference{}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
@@ -0,0 +1,146 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name        string
+		expected    string
+		actual      string
+		ignoredKeys []string
+		want        []Difference
+	}{
+		{
+			name:     "equal documents",
+			expected: `{"name": "a", "enabled": true}`,
+			actual:   `{"enabled": true, "name": "a"}`,
+			want:     []Difference{},
+		},
+		{
+			name:     "fields only present in actual document are ignored",
+			expected: `{"name": "a"}`,
+			actual:   `{"name": "a", "id": "1234", "metadata": {"clusterVersion": "1.262"}}`,
+			want:     []Difference{},
+		},
+		{
+			name:     "changed value",
+			expected: `{"name": "a", "rules": [{"enabled": true}]}`,
+			actual:   `{"name": "a", "rules": [{"enabled": false}]}`,
+			want:     []Difference{{Path: "rules[0].enabled", Expected: true, Actual: false}},
+		},
+		{
+			name:     "missing value",
+			expected: `{"name": "a", "description": "b"}`,
+			actual:   `{"name": "a"}`,
+			want:     []Difference{{Path: "description", Expected: "b", Actual: nil}},
+		},
+		{
+			name:     "differing array length",
+			expected: `{"tags": ["a", "b"]}`,
+			actual:   `{"tags": ["a"]}`,
+			want:     []Difference{{Path: "tags", Expected: []any{"a", "b"}, Actual: []any{"a"}}},
+		},
+		{
+			name:        "ignored keys are not compared",
+			expected:    `{"id": "1", "name": "a"}`,
+			actual:      `{"id": "2", "name": "a"}`,
+			ignoredKeys: []string{"id"},
+			want:        []Difference{},
+		},
+		{
+			name:     "differences are sorted by path",
+			expected: `{"b": 1, "a": 1}`,
+			actual:   `{"b": 2, "a": 2}`,
+			want: []Difference{
+				{Path: "a", Expected: float64(1), Actual: float64(2)},
+				{Path: "b", Expected: float64(1), Actual: float64(2)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff([]byte(tt.expected), []byte(tt.actual), tt.ignoredKeys...)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestDiffExact(t *testing.T) {
+	tests := []struct {
+		name        string
+		expected    string
+		actual      string
+		ignoredKeys []string
+		want        []Difference
+	}{
+		{
+			name:     "equal documents",
+			expected: `{"name": "a", "enabled": true}`,
+			actual:   `{"enabled": true, "name": "a"}`,
+			want:     []Difference{},
+		},
+		{
+			name:        "fields only present in actual document are reported",
+			expected:    `{"name": "a", "rules": [{"enabled": true}]}`,
+			actual:      `{"name": "a", "id": "1234", "description": "b", "rules": [{"enabled": true, "type": "c"}]}`,
+			ignoredKeys: []string{"id"},
+			want: []Difference{
+				{Path: "description", Expected: nil, Actual: "b"},
+				{Path: "rules[0].type", Expected: nil, Actual: "c"},
+			},
+		},
+		{
+			name:     "missing value",
+			expected: `{"name": "a", "description": "b"}`,
+			actual:   `{"name": "a"}`,
+			want:     []Difference{{Path: "description", Expected: "b", Actual: nil}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiffExact([]byte(tt.expected), []byte(tt.actual), tt.ignoredKeys...)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestDiffFailsOnInvalidJson(t *testing.T) {
+	_, err := Diff([]byte(`{`), []byte(`{}`))
+	assert.ErrorContains(t, err, "expected json")
+
+	_, err = Diff([]byte(`{}`), []byte(`{`))
+	assert.ErrorContains(t, err, "actual json")
+}
+
+func TestDifferenceString(t *testing.T) {
+	d := Difference{Path: "name", Expected: "new", Actual: nil}
+	assert.Equal(t, d.String(), `name: <missing> -> "new"`)
+}
+
+func TestDifferenceString_DoesNotEscapeHtmlCharacters(t *testing.T) {
+	d := Difference{Path: "token", Expected: "<a&b>", Actual: "old"}
+	assert.Equal(t, d.String(), `token: "old" -> "<a&b>"`)
+}
@@ -0,0 +1,264 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonschema validates documents against JSON schemas. Only the subset of keywords used by the schemas of
+// monaco is supported: type, properties, required, items, additionalProperties, enum, const, oneOf and minimum.
+// All other keywords, e.g. descriptions, are ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Schema is a parsed JSON schema
+type Schema struct {
+	Type                 types              `json:"type"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	AdditionalProperties *additional        `json:"additionalProperties"`
+	Enum                 []any              `json:"enum"`
+	Const                *any               `json:"const"`
+	OneOf                []*Schema          `json:"oneOf"`
+	Minimum              *float64           `json:"minimum"`
+}
+
+// types holds the allowed types of a schema, which are defined either as single string or as array of strings
+type types []string
+
+func (t *types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = types{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("'type' must be a string or an array of strings: %w", err)
+	}
+	*t = multiple
+	return nil
+}
+
+// additional holds the additionalProperties of a schema, which are defined either as boolean or as schema
+type additional struct {
+	allowed bool
+	schema  *Schema
+}
+
+func (a *additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.allowed); err == nil {
+		return nil
+	}
+
+	a.allowed = true
+	return json.Unmarshal(data, &a.schema)
+}
+
+// ValidationError describes a single value of a document not matching the schema
+type ValidationError struct {
+	// Path is the location of the value in the document, e.g. `environmentGroups[0].name`
+	Path string
+	// Reason describes why the value does not match the schema
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Reason)
+}
+
+// Parse parses the given JSON schema
+func Parse(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+	return &s, nil
+}
+
+// Validate validates the given document against the schema and returns a ValidationError for each value not matching
+// it. The document is expected in the generic form produced by unmarshalling JSON or YAML into an interface{}.
+func (s *Schema) Validate(document any) []ValidationError {
+	return s.validate("", normalize(document))
+}
+
+func (s *Schema) validate(path string, value any) []ValidationError {
+	if len(s.Type) > 0 && !s.Type.matches(value) {
+		return []ValidationError{{Path: path, Reason: fmt.Sprintf("expected %s, but got %s", strings.Join(s.Type, " or "), typeOf(value))}}
+	}
+
+	var errs []ValidationError
+
+	if s.Const != nil && !reflect.DeepEqual(normalize(*s.Const), value) {
+		errs = append(errs, ValidationError{Path: path, Reason: fmt.Sprintf("expected %v, but got %v", *s.Const, value)})
+	}
+
+	if len(s.Enum) > 0 && !s.inEnum(value) {
+		errs = append(errs, ValidationError{Path: path, Reason: fmt.Sprintf("expected one of %v, but got %v", s.Enum, value)})
+	}
+
+	if len(s.OneOf) > 0 && s.countMatching(path, value) != 1 {
+		errs = append(errs, ValidationError{Path: path, Reason: fmt.Sprintf("value %v does not match exactly one of the allowed schemas", value)})
+	}
+
+	if n, ok := value.(float64); ok && s.Minimum != nil && n < *s.Minimum {
+		errs = append(errs, ValidationError{Path: path, Reason: fmt.Sprintf("expected a value of at least %v, but got %v", *s.Minimum, n)})
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		errs = append(errs, s.validateObject(path, v)...)
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				errs = append(errs, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+			}
+		}
+	}
+
+	return errs
+}
+
+func (s *Schema) validateObject(path string, object map[string]any) []ValidationError {
+	var errs []ValidationError
+
+	for _, name := range s.Required {
+		if _, found := object[name]; !found {
+			errs = append(errs, ValidationError{Path: path, Reason: fmt.Sprintf("missing required property %q", name)})
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertyPath := name
+		if path != "" {
+			propertyPath = path + "." + name
+		}
+
+		if property, found := s.Properties[name]; found {
+			errs = append(errs, property.validate(propertyPath, object[name])...)
+			continue
+		}
+
+		if s.AdditionalProperties == nil {
+			continue
+		}
+		if !s.AdditionalProperties.allowed {
+			errs = append(errs, ValidationError{Path: propertyPath, Reason: "property is not allowed"})
+		} else if s.AdditionalProperties.schema != nil {
+			errs = append(errs, s.AdditionalProperties.schema.validate(propertyPath, object[name])...)
+		}
+	}
+
+	return errs
+}
+
+func (s *Schema) inEnum(value any) bool {
+	for _, e := range s.Enum {
+		if reflect.DeepEqual(normalize(e), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) countMatching(path string, value any) int {
+	count := 0
+	for _, candidate := range s.OneOf {
+		if len(candidate.validate(path, value)) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func (t types) matches(value any) bool {
+	actual := typeOf(value)
+	for _, expected := range t {
+		if expected == actual || (expected == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// normalize converts the given value to the types produced by unmarshalling JSON. Maps with non-string keys, as
+// produced by unmarshalling YAML, are converted to maps with string keys, and all numbers are converted to float64.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = normalize(item)
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[key] = normalize(item)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = normalize(item)
+		}
+		return result
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	default:
+		return value
+	}
+}
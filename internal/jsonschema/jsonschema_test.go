@@ -0,0 +1,142 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"gopkg.in/yaml.v2"
+	"gotest.tools/assert"
+	"testing"
+)
+
+const testSchema = `{
+  "type": "object",
+  "properties": {
+    "version": {"type": ["string", "number"]},
+    "items": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "kind": {"type": "string", "oneOf": [{"const": "simple"}, {"const": "grouping"}]},
+          "strategy": {"enum": ["simple", "token-bucket"]},
+          "burst": {"type": "integer", "minimum": 0},
+          "variables": {"type": "object", "additionalProperties": {"type": ["string", "number", "boolean"]}},
+          "strict": {"type": "object", "additionalProperties": false}
+        },
+        "required": ["name"]
+      }
+    }
+  },
+  "required": ["version", "items"]
+}`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     []ValidationError
+	}{
+		{
+			name: "valid document",
+			document: `
+version: 1.0
+items:
+- name: a
+  kind: grouping
+  strategy: token-bucket
+  burst: 5
+  variables:
+    a: b
+    c: 1
+    d: true
+  strict: {}
+  unknown: value`,
+			want: nil,
+		},
+		{
+			name:     "missing required properties",
+			document: `items: [{kind: simple}]`,
+			want: []ValidationError{
+				{Path: "", Reason: `missing required property "version"`},
+				{Path: "items[0]", Reason: `missing required property "name"`},
+			},
+		},
+		{
+			name:     "wrong types",
+			document: `{version: [1], items: {name: a}}`,
+			want: []ValidationError{
+				{Path: "items", Reason: "expected array, but got object"},
+				{Path: "version", Reason: "expected string or number, but got array"},
+			},
+		},
+		{
+			name:     "integer expected",
+			document: `{version: "1.0", items: [{name: a, burst: 1.5}]}`,
+			want: []ValidationError{
+				{Path: "items[0].burst", Reason: "expected integer, but got number"},
+			},
+		},
+		{
+			name:     "value below minimum",
+			document: `{version: "1.0", items: [{name: a, burst: -1}]}`,
+			want: []ValidationError{
+				{Path: "items[0].burst", Reason: "expected a value of at least 0, but got -1"},
+			},
+		},
+		{
+			name:     "value not in enum",
+			document: `{version: "1.0", items: [{name: a, strategy: fast}]}`,
+			want: []ValidationError{
+				{Path: "items[0].strategy", Reason: "expected one of [simple token-bucket], but got fast"},
+			},
+		},
+		{
+			name:     "value matching no oneOf schema",
+			document: `{version: "1.0", items: [{name: a, kind: complex}]}`,
+			want: []ValidationError{
+				{Path: "items[0].kind", Reason: "value complex does not match exactly one of the allowed schemas"},
+			},
+		},
+		{
+			name:     "additional properties",
+			document: `{version: "1.0", items: [{name: a, variables: {a: [b]}, strict: {a: b}}]}`,
+			want: []ValidationError{
+				{Path: "items[0].strict.a", Reason: "property is not allowed"},
+				{Path: "items[0].variables.a", Reason: "expected string or number or boolean, but got array"},
+			},
+		},
+	}
+
+	schema, err := Parse([]byte(testSchema))
+	assert.NilError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var document interface{}
+			assert.NilError(t, yaml.Unmarshal([]byte(tt.document), &document))
+
+			got := schema.Validate(document)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestParse_FailsOnInvalidSchema(t *testing.T) {
+	_, err := Parse([]byte(`{"type": 1}`))
+	assert.ErrorContains(t, err, "failed to parse JSON schema")
+}
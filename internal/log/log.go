@@ -200,7 +200,7 @@ func Debug(msg string, a ...interface{}) {
 }
 
 func doLog(logger *extendedLogger, level logLevel, msg string, a ...interface{}) {
-	msg = fmt.Sprintf(level.prefix()+msg, a...)
+	msg = Redact(fmt.Sprintf(level.prefix()+msg, a...))
 	if logger.level >= level && logger.consoleLogger != nil {
 		logger.consoleLogger.Println(msg)
 	}
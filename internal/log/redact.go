@@ -0,0 +1,70 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redacted replaces all registered secrets in logs
+const redacted = "***"
+
+var (
+	secretsLock sync.RWMutex
+	secrets     []string
+)
+
+// RegisterSecret registers a secret value, which is redacted from all logs, request and response logs and
+// from all values passed to Redact. Empty values are ignored.
+func RegisterSecret(values ...string) {
+	secretsLock.Lock()
+	defer secretsLock.Unlock()
+
+	for _, v := range values {
+		if v == "" || containsSecret(v) {
+			continue
+		}
+		secrets = append(secrets, v)
+	}
+
+	// replace longer secrets first, so that secrets containing other secrets are fully redacted
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+}
+
+func containsSecret(v string) bool {
+	for _, s := range secrets {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact replaces all registered secrets in the given string
+func Redact(s string) string {
+	secretsLock.RLock()
+	defer secretsLock.RUnlock()
+
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bytes"
+	"gotest.tools/assert"
+	"log"
+	"testing"
+)
+
+func resetSecrets(t *testing.T) {
+	t.Cleanup(func() {
+		secretsLock.Lock()
+		secrets = nil
+		secretsLock.Unlock()
+	})
+}
+
+func TestRedact(t *testing.T) {
+	resetSecrets(t)
+
+	RegisterSecret("token", "", "token-with-suffix", "token")
+
+	assert.Equal(t, Redact("no secrets here"), "no secrets here")
+	assert.Equal(t, Redact("the token is token"), "the *** is ***")
+	assert.Equal(t, Redact("value: token-with-suffix"), "value: ***")
+}
+
+func TestLogsAreRedacted(t *testing.T) {
+	resetSecrets(t)
+	RegisterSecret("s3cr3t")
+
+	var buf bytes.Buffer
+	logger := New(log.New(&buf, "", 0), nil, LevelDebug)
+
+	logger.Info("deploying value %s", "s3cr3t")
+
+	assert.Equal(t, buf.String(), "INFO  deploying value ***\n")
+}
@@ -90,7 +90,7 @@ func LogRequest(id string, request *http.Request) error {
 		return err
 	}
 
-	stringDump := string(dump)
+	stringDump := Redact(string(dump))
 
 	_, err = requestLogFile.WriteString(fmt.Sprintf(`Request-ID: %s
 %s
@@ -123,13 +123,13 @@ func LogResponse(id string, response *http.Response, body string) error {
 		}
 	}
 
-	stringDump := string(dump)
+	stringDump := Redact(string(dump))
 
 	_, err = responseLogFile.WriteString(fmt.Sprintf(`%s
 %s
 
 =========================
-`, stringDump, body))
+`, stringDump, Redact(body)))
 
 	if err != nil {
 		return err
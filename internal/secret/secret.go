@@ -0,0 +1,158 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secret encrypts and decrypts secret values stored in monaco configurations, using AES-256-GCM.
+//
+// The key is read from the environment variable MONACO_SECRET_KEY, or from the file referenced by the environment
+// variable MONACO_SECRET_KEY_FILE. In both cases the key needs to be 32 random bytes encoded in base64, as e.g.
+// generated by `openssl rand -base64 32`.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// EnvKeySecretKey is the environment variable holding the base64 encoded key
+	EnvKeySecretKey = "MONACO_SECRET_KEY"
+	// EnvKeySecretKeyFile is the environment variable holding the path of a file containing the base64 encoded key
+	EnvKeySecretKeyFile = "MONACO_SECRET_KEY_FILE"
+
+	keySize = 32
+)
+
+// ErrNoKey is returned by LoadKey if no key is configured
+var ErrNoKey = fmt.Errorf("no secret key configured, set %s or %s", EnvKeySecretKey, EnvKeySecretKeyFile)
+
+// LoadKey loads the key from the environment variable EnvKeySecretKey, or from the file referenced by
+// EnvKeySecretKeyFile if the former is not set.
+func LoadKey(fs afero.Fs) ([]byte, error) {
+	if encoded, found := os.LookupEnv(EnvKeySecretKey); found {
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key in %s: %w", EnvKeySecretKey, err)
+		}
+		return key, nil
+	}
+
+	if path, found := os.LookupEnv(EnvKeySecretKeyFile); found {
+		content, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file %q: %w", path, err)
+		}
+
+		key, err := decodeKey(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("invalid key in file %q: %w", path, err)
+		}
+		return key, nil
+	}
+
+	return nil, ErrNoKey
+}
+
+// KeyLoader loads the key using LoadKey on first use, and returns the same result to all later calls. This allows
+// to load the key only if it is needed, e.g. once the first secret is decrypted. It is safe for concurrent use.
+type KeyLoader struct {
+	fs   afero.Fs
+	once sync.Once
+	key  []byte
+	err  error
+}
+
+// NewKeyLoader returns a KeyLoader reading key files from the given filesystem
+func NewKeyLoader(fs afero.Fs) *KeyLoader {
+	return &KeyLoader{fs: fs}
+}
+
+// Load returns the key, loading it on the first call
+func (l *KeyLoader) Load() ([]byte, error) {
+	l.once.Do(func() {
+		l.key, l.err = LoadKey(l.fs)
+	})
+	return l.key, l.err
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("key is not base64 encoded: %w", err)
+	}
+
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes long, but is %d bytes", keySize, len(key))
+	}
+
+	return key, nil
+}
+
+// Encrypt encrypts the given plaintext with the given key. The result contains the random nonce followed by the
+// ciphertext, encoded in base64.
+func Encrypt(key []byte, plaintext []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value encrypted by Encrypt with the given key
+func Decrypt(key []byte, encrypted string) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encrypted))
+	if err != nil {
+		return nil, fmt.Errorf("encrypted value is not base64 encoded: %w", err)
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encrypted value is too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt value, the key might be wrong")
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,117 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package secret
+
+import (
+	"encoding/base64"
+	"errors"
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"testing"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestEncryptDecrypt(t *testing.T) {
+	encrypted, err := Encrypt(testKey, []byte("my secret"))
+	assert.NilError(t, err)
+	assert.Assert(t, encrypted != "my secret")
+
+	decrypted, err := Decrypt(testKey, encrypted)
+	assert.NilError(t, err)
+	assert.Equal(t, string(decrypted), "my secret")
+}
+
+func TestEncryptUsesRandomNonce(t *testing.T) {
+	first, err := Encrypt(testKey, []byte("my secret"))
+	assert.NilError(t, err)
+	second, err := Encrypt(testKey, []byte("my secret"))
+	assert.NilError(t, err)
+
+	assert.Assert(t, first != second, "encrypting the same value twice should not produce the same result")
+}
+
+func TestDecryptFailsWithWrongKey(t *testing.T) {
+	encrypted, err := Encrypt(testKey, []byte("my secret"))
+	assert.NilError(t, err)
+
+	_, err = Decrypt([]byte("fedcba9876543210fedcba9876543210"), encrypted)
+	assert.ErrorContains(t, err, "failed to decrypt value")
+}
+
+func TestDecryptFailsOnInvalidValue(t *testing.T) {
+	_, err := Decrypt(testKey, "not base64!")
+	assert.ErrorContains(t, err, "not base64 encoded")
+
+	_, err = Decrypt(testKey, base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.ErrorContains(t, err, "too short")
+}
+
+func TestLoadKeyFromEnv(t *testing.T) {
+	t.Setenv(EnvKeySecretKey, base64.StdEncoding.EncodeToString(testKey))
+
+	key, err := LoadKey(afero.NewMemMapFs())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, key, testKey)
+}
+
+func TestLoadKeyFromFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NilError(t, afero.WriteFile(fs, "monaco.key", []byte(base64.StdEncoding.EncodeToString(testKey)+"\n"), 0600))
+	t.Setenv(EnvKeySecretKeyFile, "monaco.key")
+
+	key, err := LoadKey(fs)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, key, testKey)
+}
+
+func TestLoadKeyErrors(t *testing.T) {
+	t.Run("no key", func(t *testing.T) {
+		_, err := LoadKey(afero.NewMemMapFs())
+		assert.Assert(t, errors.Is(err, ErrNoKey))
+	})
+
+	t.Run("wrong length", func(t *testing.T) {
+		t.Setenv(EnvKeySecretKey, base64.StdEncoding.EncodeToString([]byte("short")))
+		_, err := LoadKey(afero.NewMemMapFs())
+		assert.ErrorContains(t, err, "must be 32 bytes long")
+	})
+
+	t.Run("missing key file", func(t *testing.T) {
+		t.Setenv(EnvKeySecretKeyFile, "missing.key")
+		_, err := LoadKey(afero.NewMemMapFs())
+		assert.ErrorContains(t, err, "failed to read key file")
+	})
+}
+
+func TestKeyLoaderLoadsKeyOnce(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NilError(t, afero.WriteFile(fs, "key", []byte(base64.StdEncoding.EncodeToString(testKey)), 0600))
+	t.Setenv(EnvKeySecretKeyFile, "key")
+
+	loader := NewKeyLoader(fs)
+
+	key, err := loader.Load()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, key, testKey)
+
+	assert.NilError(t, fs.Remove("key"))
+
+	key, err = loader.Load()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, key, testKey)
+}
@@ -62,6 +62,13 @@ type ConfigClient interface {
 	//	 PUT <environment-url>/api/config/v1/alertingProfiles/<id> ... with the given (or found by unique name) entity ID
 	UpsertConfigByNonUniqueNameAndId(a Api, entityId string, name string, payload []byte) (entity DynatraceEntity, err error)
 
+	// UpdateConfigById updates the Dynatrace config identified by id of the given API, without looking up the config by name.
+	// If no config with the given id exists, ErrConfigNotFound is returned.
+	// It calls the underlying GET and PUT endpoints for the API. E.g. for alerting profiles this would be:
+	//    GET <environment-url>/api/config/v1/alertingProfiles/<id> ... to check if the config exists
+	//    PUT <environment-url>/api/config/v1/alertingProfiles/<id> ... to update the config
+	UpdateConfigById(a Api, id string, name string, payload []byte) (entity DynatraceEntity, err error)
+
 	// DeleteConfigById removes a given config for a given API using its id.
 	// It calls the DELETE endpoint for the API. E.g. for alerting profiles this would be:
 	//    DELETE <environment-url>/api/config/v1/alertingProfiles/<id> ... to delete the config
@@ -86,6 +93,9 @@ type DownloadSettingsObject struct {
 // ErrSettingNotFound is returned when no settings 2.0 object could be found
 var ErrSettingNotFound = errors.New("settings object not found")
 
+// ErrConfigNotFound is returned when no classic config could be found
+var ErrConfigNotFound = errors.New("config not found")
+
 // SettingsClient is the abstraction layer for CRUD operations on the Dynatrace Settings API.
 // Its design is intentionally not dependent on Monaco objects.
 //
@@ -106,6 +116,9 @@ type SettingsClient interface {
 	// ListSchemas returns all schemas that the Dynatrace environment reports
 	ListSchemas() (SchemaList, error)
 
+	// GetSchemaById returns the JSON definition of the latest version of the schema with the given ID
+	GetSchemaById(schemaId string) (schema []byte, err error)
+
 	// ListSettings returns all settings objects for a given schema.
 	ListSettings(string, ListSettingsOptions) ([]DownloadSettingsObject, error)
 
@@ -154,6 +167,9 @@ type EntitiesClient interface {
 
 	// ListEntities returns all entities objects for a given type.
 	ListEntities(string) ([]string, error)
+
+	// ListEntityIds returns the IDs of all entities matching the given entity selector.
+	ListEntityIds(entitySelector string) ([]string, error)
 }
 
 //go:generate mockgen -source=client.go -destination=client_mock.go -package=client -imports .=github.com/dynatrace/dynatrace-configuration-as-code/pkg/api DynatraceClient
@@ -187,6 +203,10 @@ type DynatraceClient struct {
 	client *http.Client
 	// retrySettings specify the retry behavior of the dynatrace client in case something goes wrong
 	retrySettings rest.RetrySettings
+	// rateLimitSettings specify how requests of the dynatrace client are rate limited
+	rateLimitSettings rest.RateLimitSettings
+	// validateOnly makes the client only validate configs instead of modifying them, see WithValidateOnly
+	validateOnly bool
 }
 
 var (
@@ -203,6 +223,14 @@ func WithRetrySettings(retrySettings rest.RetrySettings) func(*DynatraceClient)
 	}
 }
 
+// WithRateLimitSettings sets the rate limit settings to be used by the DynatraceClient. If not set, the settings are
+// read from the environment variables, see rest.RateLimitSettingsFromEnv
+func WithRateLimitSettings(rateLimitSettings rest.RateLimitSettings) func(*DynatraceClient) {
+	return func(d *DynatraceClient) {
+		d.rateLimitSettings = rateLimitSettings
+	}
+}
+
 // WithHTTPClient sets the http client to be used by the DynatraceClient
 func WithHTTPClient(client *http.Client) func(dynatraceClient *DynatraceClient) {
 	return func(d *DynatraceClient) {
@@ -233,7 +261,9 @@ func WithAutoServerVersion() func(client *DynatraceClient) {
 
 // CreateClientForEnvironment takes an EnvironmentDefinition and creates a Dynatrace client to be used for
 // this Dynatrace environment/tenant.
-func CreateClientForEnvironment(environment manifest.EnvironmentDefinition) (Client, error) {
+// Rate limit settings configured for the environment take precedence over the ones read from environment variables.
+// Additional options are applied after the server version is determined.
+func CreateClientForEnvironment(environment manifest.EnvironmentDefinition, opts ...func(*DynatraceClient)) (Client, error) {
 	envToken, err := environment.GetToken()
 	if err != nil {
 		return nil, fmt.Errorf("unable to get token for environment %q: %w", environment.Name, err)
@@ -244,7 +274,21 @@ func CreateClientForEnvironment(environment manifest.EnvironmentDefinition) (Cli
 		return nil, fmt.Errorf("unable to get URL for environment %q: %w", environment.Name, err)
 	}
 
-	return NewDynatraceClient(envURL, envToken, WithAutoServerVersion())
+	defaultOpts := []func(*DynatraceClient){WithAutoServerVersion()}
+	if environment.RateLimit != nil {
+		defaultOpts = append(defaultOpts, WithRateLimitSettings(rateLimitSettingsOf(*environment.RateLimit)))
+	}
+
+	return NewDynatraceClient(envURL, envToken, append(defaultOpts, opts...)...)
+}
+
+// rateLimitSettingsOf maps the rate limit settings defined in the manifest to the settings of the rest client
+func rateLimitSettingsOf(rateLimit manifest.RateLimit) rest.RateLimitSettings {
+	return rest.RateLimitSettings{
+		Strategy:          rest.RateLimitStrategyType(rateLimit.Strategy),
+		RequestsPerSecond: rateLimit.RequestsPerSecond,
+		Burst:             rateLimit.Burst,
+	}
 }
 
 // NewDynatraceClient creates a new DynatraceClient
@@ -273,18 +317,29 @@ func NewDynatraceClient(environmentURL string, token string, opts ...func(dynatr
 		log.Warn("More information: https://www.dynatrace.com/support/help/dynatrace-api/basics/dynatrace-api-authentication")
 	}
 
+	rateLimitSettings, err := rest.RateLimitSettingsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	dtClient := &DynatraceClient{
-		environmentUrl: environmentURL,
-		token:          token,
-		client:         &http.Client{},
-		retrySettings:  rest.DefaultRetrySettings,
-		serverVersion:  version.Version{},
+		environmentUrl:    environmentURL,
+		token:             token,
+		client:            &http.Client{},
+		retrySettings:     rest.DefaultRetrySettings,
+		rateLimitSettings: rateLimitSettings,
+		serverVersion:     version.Version{},
 	}
 
 	for _, o := range opts {
 		o(dtClient)
 	}
 
+	dtClient.client, err = rest.NewRateLimitedClient(dtClient.client, dtClient.rateLimitSettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up rate limiting: %w", err)
+	}
+
 	if dtClient.serverVersion.Invalid() {
 		log.Warn("Dynatrace Client was created without specifying a version of the targeting Dynatrace server. This can result in faulty behavior.")
 	}
@@ -297,6 +352,9 @@ func isNewDynatraceTokenFormat(token string) bool {
 }
 
 func (d *DynatraceClient) UpsertSettings(obj SettingsObject) (DynatraceEntity, error) {
+	if d.validateOnly {
+		return d.validateSettings(obj)
+	}
 
 	// special handling for updating settings 2.0 objects on tenants with version pre 1.262.0
 	// Tenants with versions < 1.262 are not able to handle updates of existing
@@ -318,6 +376,9 @@ func (d *DynatraceClient) UpsertSettings(obj SettingsObject) (DynatraceEntity, e
 	}
 
 	externalId := idutils.GenerateExternalID(obj.SchemaId, obj.Id)
+	if obj.ExternalId != nil {
+		externalId = *obj.ExternalId
+	}
 	// special handling of this Settings object.
 	// It is delete-protected BUT has a key property which is internally
 	// used to find the object to be updated
@@ -332,7 +393,12 @@ func (d *DynatraceClient) UpsertSettings(obj SettingsObject) (DynatraceEntity, e
 
 	requestUrl := d.environmentUrl + pathSettingsObjects
 
-	resp, err := rest.SendWithRetryWithInitialTry(d.client, rest.Post, obj.Id, requestUrl, payload, d.token, d.retrySettings.Normal)
+	// settings objects are upserted by their external ID, so sending the same object again is safe
+	resp, err := rest.PostIdempotent(d.client, requestUrl, payload, d.token)
+	// concurrent modifications of settings are rejected with a conflict and succeed once the other modification is done
+	if err == nil && resp.StatusCode == http.StatusConflict {
+		resp, err = rest.SendWithRetry(d.client, rest.PostIdempotent, obj.Id, requestUrl, payload, d.token, d.retrySettings.Normal)
+	}
 	if err != nil {
 		return DynatraceEntity{}, fmt.Errorf("failed to upsert dynatrace obj: %w", err)
 	}
@@ -381,6 +447,9 @@ func (d *DynatraceClient) ReadConfigById(api Api, id string) (json []byte, err e
 }
 
 func (d *DynatraceClient) DeleteConfigById(api Api, id string) error {
+	if d.validateOnly {
+		return ErrValidateOnly
+	}
 
 	return rest.DeleteConfig(d.client, api.GetUrl(d.environmentUrl), d.token, id)
 }
@@ -392,6 +461,9 @@ func (d *DynatraceClient) ConfigExistsByName(api Api, name string) (exists bool,
 }
 
 func (d *DynatraceClient) UpsertConfigByName(api Api, name string, payload []byte) (entity DynatraceEntity, err error) {
+	if d.validateOnly {
+		return d.validateConfig(api, name, "", payload)
+	}
 
 	if api.GetId() == "extension" {
 		fullUrl := api.GetUrl(d.environmentUrl)
@@ -401,9 +473,34 @@ func (d *DynatraceClient) UpsertConfigByName(api Api, name string, payload []byt
 }
 
 func (d *DynatraceClient) UpsertConfigByNonUniqueNameAndId(api Api, entityId string, name string, payload []byte) (entity DynatraceEntity, err error) {
+	if d.validateOnly {
+		return d.validateConfig(api, name, entityId, payload)
+	}
 	return upsertDynatraceEntityByNonUniqueNameAndId(d.client, d.environmentUrl, entityId, name, api, payload, d.token, d.retrySettings)
 }
 
+func (d *DynatraceClient) UpdateConfigById(api Api, id string, name string, payload []byte) (entity DynatraceEntity, err error) {
+	if d.validateOnly {
+		return d.validateConfig(api, name, id, payload)
+	}
+	fullUrl := api.GetUrl(d.environmentUrl)
+
+	resp, err := rest.Get(d.client, joinUrl(fullUrl, url.PathEscape(id)), d.token)
+	if err != nil {
+		return DynatraceEntity{}, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return DynatraceEntity{}, ErrConfigNotFound
+	}
+
+	if !success(resp) {
+		return DynatraceEntity{}, fmt.Errorf("failed to get existing config %q for api %v (HTTP %v)!\n    Response was: %v", id, api.GetId(), resp.StatusCode, string(resp.Body))
+	}
+
+	return updateDynatraceObject(d.client, fullUrl, name, id, api, payload, d.token, d.retrySettings)
+}
+
 // SchemaListResponse is the response type returned by the ListSchemas operation
 type SchemaListResponse struct {
 	Items      SchemaList `json:"items"`
@@ -442,6 +539,24 @@ func (d *DynatraceClient) ListSchemas() (SchemaList, error) {
 	return result.Items, nil
 }
 
+func (d *DynatraceClient) GetSchemaById(schemaId string) ([]byte, error) {
+	u, err := url.Parse(d.environmentUrl + pathSchemas + "/" + url.PathEscape(schemaId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse url: %w", err)
+	}
+
+	resp, err := rest.Get(d.client, u.String(), d.token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GET schema %q: %w", schemaId, err)
+	}
+
+	if !success(resp) {
+		return nil, fmt.Errorf("request failed with HTTP (%d).\n\tResponse content: %s", resp.StatusCode, string(resp.Body))
+	}
+
+	return resp.Body, nil
+}
+
 func (d *DynatraceClient) GetSettingById(objectId string) (*DownloadSettingsObject, error) {
 	u, err := url.Parse(d.environmentUrl + pathSettingsObjects + "/" + objectId)
 	if err != nil {
@@ -593,6 +708,45 @@ func (d *DynatraceClient) ListEntities(entityType string) ([]string, error) {
 	return result, err
 }
 
+type entityIdListResponse struct {
+	Entities []struct {
+		EntityId string `json:"entityId"`
+	} `json:"entities"`
+}
+
+func (d *DynatraceClient) ListEntityIds(entitySelector string) ([]string, error) {
+
+	params := url.Values{
+		"entitySelector": []string{entitySelector},
+		"pageSize":       []string{defaultPageSize},
+		"from":           []string{defaultEntityRelativeTimeframe},
+	}
+
+	result := make([]string, 0)
+
+	addToResult := func(body []byte) error {
+		var parsed entityIdListResponse
+
+		if err1 := json.Unmarshal(body, &parsed); err1 != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err1)
+		}
+
+		for _, e := range parsed.Entities {
+			result = append(result, e.EntityId)
+		}
+
+		return nil
+	}
+
+	err := d.ListPaginated(pathEntitiesObjects, params, addToResult)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, err
+}
+
 func (d *DynatraceClient) ListPaginated(urlPath string, params url.Values, addToResult func(body []byte) error) error {
 	u, err := url.Parse(d.environmentUrl + urlPath)
 	if err != nil {
@@ -642,6 +796,10 @@ func (d *DynatraceClient) ListPaginated(urlPath string, params url.Values, addTo
 }
 
 func (d *DynatraceClient) DeleteSettings(objectID string) error {
+	if d.validateOnly {
+		return ErrValidateOnly
+	}
+
 	u, err := url.Parse(d.environmentUrl + pathSettingsObjects)
 	if err != nil {
 		return fmt.Errorf("failed to parse URL '%s': %w", d.environmentUrl+pathSettingsObjects, err)
@@ -19,19 +19,27 @@
 package client
 
 import (
+	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
 	"gotest.tools/assert"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 )
 
 var mockApi = api.NewApi("mock-api", "/mock-api", "", true, true, "", false)
 var mockApiNotSingle = api.NewApi("mock-api", "/mock-api", "", false, true, "", false)
 
+func TestMain(m *testing.M) {
+	rest.SetRetryPolicy(testRetryPolicy)
+	os.Exit(m.Run())
+}
+
 func TestNewClientNoUrl(t *testing.T) {
 	_, err := NewDynatraceClient("", "abc")
 	assert.ErrorContains(t, err, "empty url")
@@ -76,6 +84,35 @@ func TestNewClientNoToken(t *testing.T) {
 	assert.ErrorContains(t, err, "no token")
 }
 
+func TestNewClientRateLimitSettingsFromEnv(t *testing.T) {
+	t.Setenv(rest.RateLimitStrategyEnvKey, "token-bucket")
+
+	client, err := NewDynatraceClient("https://my-environment.live.dynatrace.com", "abc")
+	assert.NilError(t, err)
+	assert.Equal(t, client.rateLimitSettings, rest.RateLimitSettings{Strategy: rest.TokenBucketRateLimitStrategy})
+	assert.Assert(t, client.client.Transport != nil, "expected the HTTP client to be rate limited")
+}
+
+func TestNewClientRateLimitSettingsOverrideEnv(t *testing.T) {
+	t.Setenv(rest.RateLimitStrategyEnvKey, "token-bucket")
+
+	client, err := NewDynatraceClient("https://my-environment.live.dynatrace.com", "abc", WithRateLimitSettings(rest.RateLimitSettings{Strategy: rest.SimpleSleepRateLimitStrategy}))
+	assert.NilError(t, err)
+	assert.Assert(t, client.client.Transport == nil, "expected the HTTP client not to be rate limited")
+}
+
+func TestNewClientInvalidRateLimitSettingsInEnv(t *testing.T) {
+	t.Setenv(rest.RateLimitStrategyEnvKey, "leaky-bucket")
+
+	_, err := NewDynatraceClient("https://my-environment.live.dynatrace.com", "abc")
+	assert.ErrorContains(t, err, "unknown rate limit strategy")
+}
+
+func TestRateLimitSettingsOf(t *testing.T) {
+	settings := rateLimitSettingsOf(manifest.RateLimit{Strategy: manifest.TokenBucketRateLimitStrategy, RequestsPerSecond: 2.5, Burst: 5})
+	assert.Equal(t, settings, rest.RateLimitSettings{Strategy: rest.TokenBucketRateLimitStrategy, RequestsPerSecond: 2.5, Burst: 5})
+}
+
 func TestNewClientNoValidUrlLocalPath(t *testing.T) {
 	_, err := NewDynatraceClient("/my-environment/live/dynatrace.com/", "abc")
 	assert.ErrorContains(t, err, "no host specified")
@@ -142,6 +179,40 @@ func TestReadByIdReturnsTheResponseGivenNoError(t *testing.T) {
 	assert.DeepEqual(t, body, resp)
 }
 
+func TestUpdateConfigById(t *testing.T) {
+	var putCalled bool
+
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, req.URL.Path, "/mock-api/existing-id")
+		if req.Method == http.MethodPut {
+			putCalled = true
+		}
+		res.WriteHeader(http.StatusOK)
+	}))
+	defer func() { testServer.Close() }()
+
+	client, _ := NewDynatraceClient(testServer.URL, "abc", WithHTTPClient(testServer.Client()))
+
+	entity, err := client.UpdateConfigById(mockApiNotSingle, "existing-id", "name", []byte("{}"))
+	assert.NilError(t, err)
+	assert.Assert(t, putCalled, "expected PUT request to be sent")
+	assert.Equal(t, entity.Id, "existing-id")
+	assert.Equal(t, entity.Name, "name")
+}
+
+func TestUpdateConfigByIdReturnsErrConfigNotFound(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, req.Method, http.MethodGet, "no update should be sent for a not existing config")
+		http.Error(res, "", http.StatusNotFound)
+	}))
+	defer func() { testServer.Close() }()
+
+	client, _ := NewDynatraceClient(testServer.URL, "abc", WithHTTPClient(testServer.Client()))
+
+	_, err := client.UpdateConfigById(mockApiNotSingle, "deleted-id", "name", []byte("{}"))
+	assert.Assert(t, errors.Is(err, ErrConfigNotFound))
+}
+
 func TestListKnownSettings(t *testing.T) {
 
 	tests := []struct {
@@ -872,6 +943,65 @@ func TestListEntities(t *testing.T) {
 	}
 }
 
+func TestListEntityIds(t *testing.T) {
+	selector := `type("HOST"),tag("env:prod")`
+	apiCalls := 0
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		apiCalls++
+		if apiCalls == 1 {
+			assert.Equal(t, req.URL.Query().Get("entitySelector"), selector)
+			assert.Equal(t, req.URL.Query().Get("pageSize"), defaultPageSize)
+			assert.Equal(t, req.URL.Query().Get("from"), defaultEntityRelativeTimeframe)
+			assert.Equal(t, req.URL.Query().Get("fields"), "", "no additional fields are needed to get the IDs")
+			_, _ = rw.Write([]byte(`{ "entities": [ {"entityId": "HOST-1A28B791C329D741", "type": "HOST"} ], "nextPageKey": "page42" }`))
+			return
+		}
+
+		assert.Equal(t, req.URL.Query().Get("nextPageKey"), "page42")
+		_, _ = rw.Write([]byte(`{ "entities": [ {"entityId": "HOST-C329D7411A28B791", "type": "HOST"} ] }`))
+	}))
+	defer server.Close()
+
+	client, err := NewDynatraceClient(server.URL, "abc", WithHTTPClient(server.Client()), WithRetrySettings(testRetrySettings))
+	assert.NilError(t, err)
+
+	ids, err := client.ListEntityIds(selector)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ids, []string{"HOST-1A28B791C329D741", "HOST-C329D7411A28B791"})
+	assert.Equal(t, apiCalls, 2)
+}
+
+func TestGetSchemaById(t *testing.T) {
+	schema := `{"schemaId": "builtin:alerting.profile", "version": "1.2.3", "properties": {}}`
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, req.URL.Path, pathSchemas+"/builtin:alerting.profile")
+		_, _ = rw.Write([]byte(schema))
+	}))
+	defer server.Close()
+
+	client, err := NewDynatraceClient(server.URL, "abc", WithHTTPClient(server.Client()), WithRetrySettings(testRetrySettings))
+	assert.NilError(t, err)
+
+	got, err := client.GetSchemaById("builtin:alerting.profile")
+	assert.NilError(t, err)
+	assert.Equal(t, string(got), schema)
+}
+
+func TestGetSchemaByIdReturnsErrorForFailedRequest(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewDynatraceClient(server.URL, "abc", WithHTTPClient(server.Client()), WithRetrySettings(testRetrySettings))
+	assert.NilError(t, err)
+
+	_, err = client.GetSchemaById("builtin:unknown")
+	assert.ErrorContains(t, err, "HTTP (404)")
+}
+
 func TestCreateDynatraceClientWithAutoServerVersion(t *testing.T) {
 	t.Run("Server version is correctly set to determined value", func(t *testing.T) {
 		server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
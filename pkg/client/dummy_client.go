@@ -21,7 +21,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"hash/fnv"
 	"path/filepath"
+	"regexp"
+	"sync"
 	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
@@ -40,6 +43,9 @@ type DummyClient struct {
 	Entries          map[api.Api][]DataEntry
 	Fs               afero.Fs
 	RequestOutputDir string
+
+	// entriesLock guards Entries, as the DummyClient may be used concurrently during parallel deployments
+	entriesLock sync.Mutex
 }
 
 var (
@@ -52,6 +58,9 @@ func NewDummyClient() *DummyClient {
 }
 
 func (c *DummyClient) ListConfigs(a api.Api) (values []api.Value, err error) {
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
 	entries, found := c.Entries[a]
 
 	if !found {
@@ -73,6 +82,9 @@ func (c *DummyClient) ListConfigs(a api.Api) (values []api.Value, err error) {
 }
 
 func (c *DummyClient) ReadByName(a api.Api, name string) ([]byte, error) {
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
 	entries, found := c.Entries[a]
 
 	if !found {
@@ -89,6 +101,9 @@ func (c *DummyClient) ReadByName(a api.Api, name string) ([]byte, error) {
 }
 
 func (c *DummyClient) ReadConfigById(a api.Api, id string) ([]byte, error) {
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
 	entries, found := c.Entries[a]
 
 	if !found {
@@ -105,6 +120,9 @@ func (c *DummyClient) ReadConfigById(a api.Api, id string) ([]byte, error) {
 }
 
 func (c *DummyClient) UpsertConfigByName(a api.Api, name string, data []byte) (entity api.DynatraceEntity, err error) {
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
 	entries, found := c.Entries[a]
 
 	if c.Entries == nil {
@@ -146,6 +164,9 @@ func (c *DummyClient) UpsertConfigByName(a api.Api, name string, data []byte) (e
 }
 
 func (c *DummyClient) UpsertConfigByNonUniqueNameAndId(a api.Api, entityId string, name string, data []byte) (entity api.DynatraceEntity, err error) {
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
 	entries, found := c.Entries[a]
 
 	if c.Entries == nil {
@@ -186,6 +207,28 @@ func (c *DummyClient) UpsertConfigByNonUniqueNameAndId(a api.Api, entityId strin
 	}, nil
 }
 
+func (c *DummyClient) UpdateConfigById(a api.Api, id string, name string, data []byte) (entity api.DynatraceEntity, err error) {
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
+	entries := c.Entries[a]
+
+	for i := range entries {
+		if entries[i].Id == id {
+			entries[i].Name = name
+			entries[i].Payload = data
+			c.writeRequest(a, name, data)
+
+			return api.DynatraceEntity{
+				Id:   id,
+				Name: name,
+			}, nil
+		}
+	}
+
+	return api.DynatraceEntity{}, ErrConfigNotFound
+}
+
 func (c *DummyClient) writeRequest(a api.Api, name string, payload []byte) {
 	if c.Fs == nil {
 		return
@@ -206,6 +249,9 @@ func (c *DummyClient) writeRequest(a api.Api, name string, payload []byte) {
 }
 
 func (c *DummyClient) DeleteConfigById(a api.Api, id string) error {
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
 	entries, found := c.Entries[a]
 
 	if !found {
@@ -229,6 +275,9 @@ func (c *DummyClient) DeleteConfigById(a api.Api, id string) error {
 }
 
 func (c *DummyClient) ConfigExistsByName(a api.Api, name string) (exists bool, id string, err error) {
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
 	entries, found := c.Entries[a]
 
 	if !found {
@@ -255,6 +304,10 @@ func (c *DummyClient) ListSchemas() (SchemaList, error) {
 	return make(SchemaList, 0), nil
 }
 
+func (c *DummyClient) GetSchemaById(_ string) ([]byte, error) {
+	return []byte("{}"), nil
+}
+
 func (c *DummyClient) GetSettingById(_ string) (*DownloadSettingsObject, error) {
 	return &DownloadSettingsObject{}, nil
 }
@@ -273,3 +326,20 @@ func (c *DummyClient) ListEntitiesTypes() (EntitiesTypeList, error) {
 func (c *DummyClient) ListEntities(_ string) ([]string, error) {
 	return make([]string, 0), nil
 }
+
+// entityTypeSelector extracts the entity type of entity selectors, e.g. `type("HOST")`
+var entityTypeSelector = regexp.MustCompile(`type\("?([A-Za-z_]+)"?\)`)
+
+// ListEntityIds returns a single placeholder ID for the given entity selector. The ID is derived from the selector,
+// so the same selector always returns the same ID.
+func (c *DummyClient) ListEntityIds(entitySelector string) ([]string, error) {
+	entityType := "ENTITY"
+	if match := entityTypeSelector.FindStringSubmatch(entitySelector); match != nil {
+		entityType = match[1]
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(entitySelector))
+
+	return []string{fmt.Sprintf("%s-%016X", entityType, h.Sum64())}, nil
+}
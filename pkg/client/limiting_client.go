@@ -66,6 +66,14 @@ func (l limitingClient) UpsertConfigByNonUniqueNameAndId(a api.Api, entityId str
 	return
 }
 
+func (l limitingClient) UpdateConfigById(a api.Api, id string, name string, payload []byte) (entity api.DynatraceEntity, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		entity, err = l.client.UpdateConfigById(a, id, name, payload)
+	})
+
+	return
+}
+
 func (l limitingClient) DeleteConfigById(a api.Api, id string) (err error) {
 	l.limiter.ExecuteBlocking(func() {
 		err = l.client.DeleteConfigById(a, id)
@@ -98,6 +106,14 @@ func (l limitingClient) ListSchemas() (s SchemaList, err error) {
 	return
 }
 
+func (l limitingClient) GetSchemaById(schemaId string) (schema []byte, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		schema, err = l.client.GetSchemaById(schemaId)
+	})
+
+	return
+}
+
 func (l limitingClient) GetSettingById(objectId string) (o *DownloadSettingsObject, err error) {
 	l.limiter.ExecuteBlocking(func() {
 		o, err = l.client.GetSettingById(objectId)
@@ -136,3 +152,11 @@ func (l limitingClient) ListEntities(entityType string) (o []string, err error)
 
 	return
 }
+
+func (l limitingClient) ListEntityIds(entitySelector string) (o []string, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		o, err = l.client.ListEntityIds(entitySelector)
+	})
+
+	return
+}
@@ -40,6 +40,9 @@ type SettingsObject struct {
 	Content []byte
 	// OriginObjectId is the object id of the Settings object when it was downloaded from an environment
 	OriginObjectId string
+	// ExternalId overrides the external ID generated from SchemaId and Id if set, e.g. to restore an object to its
+	// previous state. An empty override sends no external ID.
+	ExternalId *string
 }
 
 type settingsRequest struct {
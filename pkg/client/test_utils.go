@@ -46,6 +46,11 @@ var testRetrySettings = rest.RetrySettings{
 	},
 }
 
+// testRetryPolicy retries transient errors like the rest.DefaultRetryPolicy, but without waiting in between
+var testRetryPolicy = rest.RetryPolicy{
+	MaxRetries: rest.DefaultRetryPolicy.MaxRetries,
+}
+
 type integrationTestResources struct {
 	basePath   string
 	urlMapping map[string]string
@@ -0,0 +1,187 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+)
+
+// ErrValidateOnly is returned for operations which cannot be validated by a client in validate-only mode, see
+// WithValidateOnly
+var ErrValidateOnly = errors.New("operation is not possible in validate-only mode")
+
+// WithValidateOnly configures the DynatraceClient to only validate configs instead of creating, updating or deleting
+// them. Settings objects are sent with validateOnly=true, and classic configs are sent to the validator endpoint of
+// their API. Configs of APIs without validator endpoint are not validated. Read operations are executed as usual.
+func WithValidateOnly() func(*DynatraceClient) {
+	return func(d *DynatraceClient) {
+		d.validateOnly = true
+	}
+}
+
+// ConstraintViolation is a single violation reported by a validation endpoint
+type ConstraintViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned by a DynatraceClient in validate-only mode if the environment rejects a config
+type ValidationError struct {
+	StatusCode int
+	// Message is the error message reported by the environment, or the response body if it contains no message
+	Message    string
+	Violations []ConstraintViolation
+}
+
+func (e ValidationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("validation failed (HTTP %d): %s", e.StatusCode, e.Message))
+	for _, v := range e.Violations {
+		if v.Path == "" {
+			sb.WriteString(fmt.Sprintf("\n\t- %s", v.Message))
+		} else {
+			sb.WriteString(fmt.Sprintf("\n\t- %s: %s", v.Path, v.Message))
+		}
+	}
+	return sb.String()
+}
+
+type errorResponse struct {
+	Error *struct {
+		Message              string                `json:"message"`
+		ConstraintViolations []ConstraintViolation `json:"constraintViolations"`
+	} `json:"error"`
+}
+
+// newValidationError parses the error of a failed validation. Classic validator endpoints return a single error
+// object, the settings API returns an array of errors, one for each sent object.
+func newValidationError(resp rest.Response) ValidationError {
+	result := ValidationError{StatusCode: resp.StatusCode, Message: string(resp.Body)}
+
+	var errs []errorResponse
+	var single errorResponse
+	if err := json.Unmarshal(resp.Body, &single); err == nil {
+		errs = []errorResponse{single}
+	} else if err := json.Unmarshal(resp.Body, &errs); err != nil {
+		return result
+	}
+
+	var messages []string
+	for _, e := range errs {
+		if e.Error == nil {
+			continue
+		}
+		messages = append(messages, e.Error.Message)
+		result.Violations = append(result.Violations, e.Error.ConstraintViolations...)
+	}
+
+	if len(messages) > 0 {
+		result.Message = strings.Join(messages, ", ")
+	}
+	return result
+}
+
+// validateConfig sends the payload to the validator endpoint of the given API. If objectId is empty and the API
+// identifies objects by name, the ID of an existing object with the given name is looked up to validate the payload
+// as update of it. The returned entity uses the ID of the existing object, or a random ID for new objects.
+func (d *DynatraceClient) validateConfig(theApi api.Api, objectName string, objectId string, payload []byte) (api.DynatraceEntity, error) {
+	if theApi.GetId() == "extension" {
+		log.Debug("\tExtensions can not be validated, skipping validation of %s", objectName)
+		return api.DynatraceEntity{Id: objectName, Name: objectName}, nil
+	}
+
+	fullUrl := theApi.GetUrl(d.environmentUrl)
+
+	if objectId == "" && !theApi.IsSingleConfigurationApi() {
+		existingObjectId, err := getObjectIdIfAlreadyExists(d.client, theApi, fullUrl, objectName, d.token, d.retrySettings)
+		if err != nil {
+			return api.DynatraceEntity{}, err
+		}
+		objectId = existingObjectId
+	}
+
+	// validating an update of an object which does not exist yet fails with 404, in which case it is validated as new
+	// object. APIs without validator endpoint always respond with 404 or 405.
+	validatorUrls := []string{joinUrl(fullUrl, "validator")}
+	if objectId != "" {
+		validatorUrls = append([]string{joinUrl(joinUrl(fullUrl, objectId), "validator")}, validatorUrls...)
+	}
+
+	validated := false
+	for _, validatorUrl := range validatorUrls {
+		resp, err := rest.PostIdempotent(d.client, validatorUrl, payload, d.token)
+		if err != nil {
+			return api.DynatraceEntity{}, fmt.Errorf("failed to validate DT object %s: %w", objectName, err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+			continue
+		}
+
+		if !success(resp) {
+			return api.DynatraceEntity{}, newValidationError(resp)
+		}
+
+		validated = true
+		break
+	}
+
+	if validated {
+		log.Debug("\tValidated object %s", objectName)
+	} else {
+		log.Debug("\tAPI %s provides no validator endpoint, skipping validation of %s", theApi.GetId(), objectName)
+	}
+
+	if objectId == "" {
+		objectId = uuid.NewString()
+	}
+
+	return api.DynatraceEntity{Id: objectId, Name: objectName}, nil
+}
+
+// validateSettings sends the settings object to the settings API with validateOnly=true
+func (d *DynatraceClient) validateSettings(obj SettingsObject) (api.DynatraceEntity, error) {
+	externalId := idutils.GenerateExternalID(obj.SchemaId, obj.Id)
+	payload, err := buildPostRequestPayload(obj, externalId)
+	if err != nil {
+		return api.DynatraceEntity{}, fmt.Errorf("failed to build settings object for validation: %w", err)
+	}
+
+	resp, err := rest.PostIdempotent(d.client, d.environmentUrl+pathSettingsObjects+"?validateOnly=true", payload, d.token)
+	if err != nil {
+		return api.DynatraceEntity{}, fmt.Errorf("failed to validate settings object: %w", err)
+	}
+
+	if !success(resp) {
+		return api.DynatraceEntity{}, newValidationError(resp)
+	}
+
+	log.Debug("\tValidated settings object %s (%s)", obj.Id, obj.SchemaId)
+
+	objectId := obj.OriginObjectId
+	if objectId == "" {
+		objectId = uuid.NewString()
+	}
+	return api.DynatraceEntity{Id: objectId, Name: objectId}, nil
+}
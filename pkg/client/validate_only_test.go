@@ -0,0 +1,168 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"gotest.tools/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newValidateOnlyTestServer returns a server recording all requests, which responds with the response registered
+// for the method and path of a request, or 404 if none is registered
+func newValidateOnlyTestServer(t *testing.T, responses map[string]string, statusCodes map[string]int) (*httptest.Server, *[]string) {
+	var requests []string
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		key := req.Method + " " + req.URL.Path
+		if req.URL.RawQuery != "" {
+			key += "?" + req.URL.RawQuery
+		}
+		requests = append(requests, key)
+
+		status, found := statusCodes[key]
+		if !found {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rw.WriteHeader(status)
+		_, _ = rw.Write([]byte(responses[key]))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+var validateOnlyTestApi = api.NewStandardApi("mock-api", "/mock-api", false, "", false)
+
+func newValidateOnlyTestClient(t *testing.T, server *httptest.Server) *DynatraceClient {
+	client, err := NewDynatraceClient(server.URL, "abc", WithHTTPClient(server.Client()), WithRetrySettings(testRetrySettings), WithValidateOnly())
+	assert.NilError(t, err)
+	return client
+}
+
+func TestValidateOnly_UpsertSettings(t *testing.T) {
+	server, requests := newValidateOnlyTestServer(t,
+		map[string]string{"POST /api/v2/settings/objects?validateOnly=true": `[{"code": 200}]`},
+		map[string]int{"POST /api/v2/settings/objects?validateOnly=true": http.StatusOK})
+
+	client := newValidateOnlyTestClient(t, server)
+
+	entity, err := client.UpsertSettings(SettingsObject{Id: "profile", SchemaId: "builtin:alerting.profile", Scope: "environment", Content: []byte(`{"name": "profile"}`)})
+	assert.NilError(t, err)
+	assert.Assert(t, entity.Id != "", "a placeholder ID should be returned")
+	assert.DeepEqual(t, *requests, []string{"POST /api/v2/settings/objects?validateOnly=true"})
+}
+
+func TestValidateOnly_UpsertSettingsReturnsViolations(t *testing.T) {
+	server, _ := newValidateOnlyTestServer(t,
+		map[string]string{"POST /api/v2/settings/objects?validateOnly=true": `[{"code": 400, "error": {"code": 400, "message": "Constraints violated.", "constraintViolations": [{"path": "name", "message": "must not be empty"}]}}]`},
+		map[string]int{"POST /api/v2/settings/objects?validateOnly=true": http.StatusBadRequest})
+
+	client := newValidateOnlyTestClient(t, server)
+
+	_, err := client.UpsertSettings(SettingsObject{Id: "profile", SchemaId: "builtin:alerting.profile", Scope: "environment", Content: []byte(`{"name": ""}`)})
+
+	var validationErr ValidationError
+	assert.Assert(t, errors.As(err, &validationErr))
+	assert.DeepEqual(t, validationErr, ValidationError{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Constraints violated.",
+		Violations: []ConstraintViolation{{Path: "name", Message: "must not be empty"}},
+	})
+}
+
+func TestValidateOnly_UpsertConfigByName(t *testing.T) {
+	t.Run("new config is validated as new object", func(t *testing.T) {
+		server, requests := newValidateOnlyTestServer(t,
+			map[string]string{"GET /mock-api": `{"values": []}`},
+			map[string]int{"GET /mock-api": http.StatusOK, "POST /mock-api/validator": http.StatusNoContent})
+
+		client := newValidateOnlyTestClient(t, server)
+
+		entity, err := client.UpsertConfigByName(validateOnlyTestApi, "name", []byte(`{}`))
+		assert.NilError(t, err)
+		assert.Equal(t, entity.Name, "name")
+		assert.Assert(t, entity.Id != "", "a placeholder ID should be returned")
+		assert.DeepEqual(t, *requests, []string{"GET /mock-api", "POST /mock-api/validator"})
+	})
+
+	t.Run("existing config is validated as update", func(t *testing.T) {
+		server, requests := newValidateOnlyTestServer(t,
+			map[string]string{"GET /mock-api": `{"values": [{"id": "existing-id", "name": "name"}]}`},
+			map[string]int{"GET /mock-api": http.StatusOK, "POST /mock-api/existing-id/validator": http.StatusNoContent})
+
+		client := newValidateOnlyTestClient(t, server)
+
+		entity, err := client.UpsertConfigByName(validateOnlyTestApi, "name", []byte(`{}`))
+		assert.NilError(t, err)
+		assert.Equal(t, entity.Id, "existing-id")
+		assert.DeepEqual(t, *requests, []string{"GET /mock-api", "POST /mock-api/existing-id/validator"})
+	})
+
+	t.Run("invalid config returns violations", func(t *testing.T) {
+		server, _ := newValidateOnlyTestServer(t,
+			map[string]string{
+				"GET /mock-api":            `{"values": []}`,
+				"POST /mock-api/validator": `{"error": {"code": 400, "message": "Constraints violated.", "constraintViolations": [{"path": "rules[0]", "message": "invalid rule"}]}}`,
+			},
+			map[string]int{"GET /mock-api": http.StatusOK, "POST /mock-api/validator": http.StatusBadRequest})
+
+		client := newValidateOnlyTestClient(t, server)
+
+		_, err := client.UpsertConfigByName(validateOnlyTestApi, "name", []byte(`{}`))
+		assert.Error(t, err, "validation failed (HTTP 400): Constraints violated.\n\t- rules[0]: invalid rule")
+	})
+
+	t.Run("config of API without validator is not validated", func(t *testing.T) {
+		server, requests := newValidateOnlyTestServer(t,
+			map[string]string{"GET /mock-api": `{"values": []}`},
+			map[string]int{"GET /mock-api": http.StatusOK})
+
+		client := newValidateOnlyTestClient(t, server)
+
+		_, err := client.UpsertConfigByName(validateOnlyTestApi, "name", []byte(`{}`))
+		assert.NilError(t, err)
+		assert.DeepEqual(t, *requests, []string{"GET /mock-api", "POST /mock-api/validator"})
+	})
+}
+
+func TestValidateOnly_UpsertConfigByNonUniqueNameAndId(t *testing.T) {
+	server, requests := newValidateOnlyTestServer(t, nil,
+		map[string]int{"POST /mock-api/validator": http.StatusNoContent})
+
+	client := newValidateOnlyTestClient(t, server)
+
+	entity, err := client.UpsertConfigByNonUniqueNameAndId(mockApiNotSingle, "new-id", "name", []byte(`{}`))
+	assert.NilError(t, err)
+	assert.Equal(t, entity.Id, "new-id")
+	// the object does not exist yet, and is validated as new object
+	assert.DeepEqual(t, *requests, []string{"POST /mock-api/new-id/validator", "POST /mock-api/validator"})
+}
+
+func TestValidateOnly_DeletesAreNotPossible(t *testing.T) {
+	server, requests := newValidateOnlyTestServer(t, nil, nil)
+
+	client := newValidateOnlyTestClient(t, server)
+
+	assert.Assert(t, errors.Is(client.DeleteConfigById(mockApiNotSingle, "id"), ErrValidateOnly))
+	assert.Assert(t, errors.Is(client.DeleteSettings("id"), ErrValidateOnly))
+	assert.Equal(t, len(*requests), 0)
+}
@@ -20,10 +20,15 @@ import (
 	configErrors "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
 	compoundParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/compound"
+	configLookupParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/configlookup"
+	entityLookupParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/entitylookup"
 	envParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/environment"
+	fileParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/file"
 	listParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/list"
 	refParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	secretParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/secret"
 	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	variableParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/variable"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
 )
 
@@ -91,10 +96,14 @@ type Config struct {
 
 	// OriginObjectId is the DT object ID of the object when it was downloaded from an environment
 	OriginObjectId string
+
+	// DependsOn holds the coordinates of configs which need to be deployed before this config, even though this
+	// config does not reference them in any parameter
+	DependsOn []coordinate.Coordinate
 }
 
 func (c *Config) Render(properties map[string]interface{}) (string, error) {
-	renderedConfig, err := template.Render(c.Template, properties)
+	renderedConfig, err := template.Render(c.Template, properties, template.Context{Environment: c.Environment, Group: c.Group})
 	if err != nil {
 		return "", err
 	}
@@ -122,13 +131,19 @@ func (c *Config) Render(properties map[string]interface{}) (string, error) {
 
 // DefaultParameterParsers map defining a set of default parsers which can be used to load configurations
 var DefaultParameterParsers = map[string]parameter.ParameterSerDe{
-	refParam.ReferenceParameterType:           refParam.ReferenceParameterSerde,
-	valueParam.ValueParameterType:             valueParam.ValueParameterSerde,
-	envParam.EnvironmentVariableParameterType: envParam.EnvironmentVariableParameterSerde,
-	compoundParam.CompoundParameterType:       compoundParam.CompoundParameterSerde,
-	listParam.ListParameterType:               listParam.ListParameterSerde,
+	refParam.ReferenceParameterType:             refParam.ReferenceParameterSerde,
+	valueParam.ValueParameterType:               valueParam.ValueParameterSerde,
+	envParam.EnvironmentVariableParameterType:   envParam.EnvironmentVariableParameterSerde,
+	compoundParam.CompoundParameterType:         compoundParam.CompoundParameterSerde,
+	listParam.ListParameterType:                 listParam.ListParameterSerde,
+	fileParam.FileParameterType:                 fileParam.FileParameterSerde,
+	secretParam.SecretParameterType:             secretParam.SecretParameterSerde,
+	entityLookupParam.EntityLookupParameterType: entityLookupParam.EntityLookupParameterSerde,
+	configLookupParam.ConfigLookupParameterType: configLookupParam.ConfigLookupParameterSerde,
+	variableParam.VariableParameterType:         variableParam.VariableParameterSerde,
 }
 
+// References returns the coordinates of all configs referenced by the parameters of this config
 func (c *Config) References() []coordinate.Coordinate {
 
 	refs := make([]coordinate.Coordinate, 0)
@@ -141,3 +156,9 @@ func (c *Config) References() []coordinate.Coordinate {
 
 	return refs
 }
+
+// Dependencies returns the coordinates of all configs which need to be deployed before this config. These are all
+// configs referenced by parameters, as well as all configs explicitly defined in DependsOn.
+func (c *Config) Dependencies() []coordinate.Coordinate {
+	return append(c.References(), c.DependsOn...)
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/secret"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
 	"path/filepath"
 	"strconv"
@@ -47,6 +48,10 @@ type LoaderContext struct {
 	Environments    []manifest.EnvironmentDefinition
 	KnownApis       map[string]struct{}
 	ParametersSerDe map[string]parameter.ParameterSerDe
+	// Definitions holds the definitions of all configs which can be extended by the loaded configs
+	Definitions Definitions
+	// SecretKey loads the key used to decrypt secret parameters
+	SecretKey *secret.KeyLoader
 }
 
 // LoadConfigs will search a given path for configuration yamls and parses them.
@@ -87,8 +92,26 @@ func LoadConfigs(fs afero.Fs, context *LoaderContext) (result []Config, errors [
 
 type ConfigLoaderContext struct {
 	*LoaderContext
-	Folder string
-	Path   string
+	Fs        afero.Fs
+	Folder    string
+	Path      string
+	Variables parameter.VariableLookup
+}
+
+// environmentVariables maps environment names to the variables defined for them in the manifest
+type environmentVariables map[string]map[string]string
+
+func newEnvironmentVariables(environments []manifest.EnvironmentDefinition) environmentVariables {
+	variables := make(environmentVariables, len(environments))
+	for _, env := range environments {
+		variables[env.Name] = env.Variables
+	}
+	return variables
+}
+
+func (v environmentVariables) LookupVariable(environment string, name string) (string, bool) {
+	value, found := v[environment][name]
+	return value, found
 }
 
 type SingleConfigLoadContext struct {
@@ -171,43 +194,38 @@ func (e DefinitionParserError) Error() string {
 }
 
 func parseConfigs(fs afero.Fs, context *LoaderContext, filePath string) (configs []Config, errors []error) {
-	data, err := afero.ReadFile(fs, filePath)
-	folder := filepath.Dir(filePath)
-
+	definition, err := loadDefinitionFile(fs, filePath)
 	if err != nil {
 		return nil, []error{err}
 	}
 
-	definition := topLevelDefinition{}
-
-	err = yaml.UnmarshalStrict(data, &definition)
-
-	if err != nil {
-		if strings.Contains(err.Error(), fmt.Sprintf("field config not found in type %s", getTopLevelDefinitionYamlTypeName())) {
-			return nil, []error{
-				fmt.Errorf("config '%s' is not valid v2 configuration - you may be loading v1 configs, please 'convert' to v2:\n%w", filePath, err),
-			}
-		}
-
-		return nil, []error{
-			fmt.Errorf("failed to load config '%s':\n%w", filePath, err),
-		}
-	}
-
-	if len(definition.Configs) == 0 {
-		return nil, []error{
-			fmt.Errorf("no configurations found in file '%s'", filePath),
-		}
-	}
+	folder := filepath.Dir(filePath)
 
 	configLoaderContext := &ConfigLoaderContext{
 		LoaderContext: context,
+		Fs:            fs,
 		Folder:        folder,
 		Path:          filePath,
+		Variables:     newEnvironmentVariables(context.Environments),
 	}
 
 	for _, config := range definition.Configs {
-		result, definitionErrors := parseDefinition(fs, configLoaderContext, config.Id, config)
+		var result []Config
+		var definitionErrors []error
+
+		if config.Extends != "" {
+			config, err = resolveExtends(configLoaderContext, config)
+			if err != nil {
+				errors = append(errors, err)
+				continue
+			}
+		}
+
+		if config.ForEach != nil {
+			result, definitionErrors = parseForEachDefinition(fs, configLoaderContext, config)
+		} else {
+			result, definitionErrors = parseDefinition(fs, configLoaderContext, config.Id, config)
+		}
 
 		if definitionErrors != nil {
 			errors = append(errors, definitionErrors...)
@@ -224,6 +242,32 @@ func parseConfigs(fs afero.Fs, context *LoaderContext, filePath string) (configs
 	return configs, nil
 }
 
+// loadDefinitionFile reads and unmarshalls the config definitions of the given file
+func loadDefinitionFile(fs afero.Fs, filePath string) (topLevelDefinition, error) {
+	data, err := afero.ReadFile(fs, filePath)
+	if err != nil {
+		return topLevelDefinition{}, err
+	}
+
+	definition := topLevelDefinition{}
+
+	err = yaml.UnmarshalStrict(data, &definition)
+
+	if err != nil {
+		if strings.Contains(err.Error(), fmt.Sprintf("field config not found in type %s", getTopLevelDefinitionYamlTypeName())) {
+			return topLevelDefinition{}, fmt.Errorf("config '%s' is not valid v2 configuration - you may be loading v1 configs, please 'convert' to v2:\n%w", filePath, err)
+		}
+
+		return topLevelDefinition{}, fmt.Errorf("failed to load config '%s':\n%w", filePath, err)
+	}
+
+	if len(definition.Configs) == 0 {
+		return topLevelDefinition{}, fmt.Errorf("no configurations found in file '%s'", filePath)
+	}
+
+	return definition, nil
+}
+
 // parseDefinition parses a single config entry
 func parseDefinition(
 	fs afero.Fs,
@@ -244,6 +288,11 @@ func parseDefinition(
 		Type:                definition.Type.GetApiType(),
 	}
 
+	dependsOn, dependsOnErrors := parseDependsOn(singleConfigContext, configId, definition.DependsOn)
+	if dependsOnErrors != nil {
+		return nil, dependsOnErrors
+	}
+
 	groupOverrideMap := toGroupOverrideMap(definition.GroupOverrides)
 	environmentOverrideMap := toEnvironmentOverrideMap(definition.EnvironmentOverrides)
 
@@ -256,6 +305,7 @@ func parseDefinition(
 			continue
 		}
 
+		result.DependsOn = dependsOn
 		results = append(results, result)
 	}
 
@@ -266,6 +316,88 @@ func parseDefinition(
 	return results, nil
 }
 
+// parseDependsOn parses the coordinates of the configs a config explicitly depends on. Each entry is either
+//   - a config id of the same project and type,
+//   - a list of 1 to 3 elements, like short references without the property: [configId], [type, configId] or
+//     [project, type, configId],
+//   - or a map containing the keys `project`, `configType` and `configId`. Project and type default to the ones of
+//     the config.
+func parseDependsOn(context *SingleConfigLoadContext, configId string, dependsOn []interface{}) ([]coordinate.Coordinate, []error) {
+	if len(dependsOn) == 0 {
+		return nil, nil
+	}
+
+	self := coordinate.Coordinate{Project: context.ProjectId, Type: context.Type, ConfigId: configId}
+	result := make([]coordinate.Coordinate, 0, len(dependsOn))
+	var errors []error
+
+	for i, entry := range dependsOn {
+		c := self
+
+		switch v := entry.(type) {
+		case string:
+			c.ConfigId = v
+		case []interface{}:
+			switch len(v) {
+			case 1:
+				c.ConfigId = toString(v[0])
+			case 2:
+				c.Type, c.ConfigId = toString(v[0]), toString(v[1])
+			case 3:
+				c.Project, c.Type, c.ConfigId = toString(v[0]), toString(v[1]), toString(v[2])
+			default:
+				errors = append(errors, newDefinitionParserError(configId, context,
+					fmt.Sprintf("dependsOn entry %d must have between 1 and 3 elements. you provided `%d`", i, len(v))))
+				continue
+			}
+		case map[interface{}]interface{}:
+			m := maps.ToStringMap(v)
+			for key := range m {
+				if key != "project" && key != "configType" && key != "configId" {
+					errors = append(errors, newDefinitionParserError(configId, context,
+						fmt.Sprintf("dependsOn entry %d contains unknown key `%s`", i, key)))
+				}
+			}
+			if p, found := m["project"]; found {
+				c.Project = toString(p)
+			}
+			if t, found := m["configType"]; found {
+				c.Type = toString(t)
+			}
+			id, found := m["configId"]
+			if !found {
+				errors = append(errors, newDefinitionParserError(configId, context,
+					fmt.Sprintf("dependsOn entry %d is missing key `configId`", i)))
+				continue
+			}
+			c.ConfigId = toString(id)
+		default:
+			errors = append(errors, newDefinitionParserError(configId, context,
+				fmt.Sprintf("dependsOn entry %d must be a config id, a list or a map, but is `%v`", i, entry)))
+			continue
+		}
+
+		if c.Project == "" || c.Type == "" || c.ConfigId == "" {
+			errors = append(errors, newDefinitionParserError(configId, context,
+				fmt.Sprintf("dependsOn entry %d (`%s`) must not contain empty values", i, c)))
+			continue
+		}
+
+		if c == self {
+			errors = append(errors, newDefinitionParserError(configId, context, "config must not depend on itself"))
+			continue
+		}
+
+		result = append(result, c)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return result, nil
+}
+
 func toEnvironmentOverrideMap(environments []environmentOverride) map[string]environmentOverride {
 	result := make(map[string]environmentOverride)
 
@@ -350,12 +482,14 @@ func getConfigFromDefinition(
 		}
 	}
 
-	template, err := template.LoadTemplate(fs, filepath.Join(context.Folder, definition.Template))
+	configTemplate, err := template.LoadTemplate(fs, filepath.Join(context.Folder, definition.Template))
 
 	var errors []error
 
 	if err != nil {
 		errors = append(errors, newDetailedDefinitionParserError(configId, context, environment, fmt.Sprintf("error while loading template: `%s`", err)))
+	} else if _, err := template.ParseTemplate(configTemplate.Id(), configTemplate.Content()); err != nil {
+		errors = append(errors, newDetailedDefinitionParserError(configId, context, environment, fmt.Sprintf("invalid template: `%s`", err)))
 	}
 
 	parameters, parameterErrors := parseParametersAndReferences(context, environment, configId,
@@ -407,7 +541,7 @@ func getConfigFromDefinition(
 	}
 
 	return Config{
-		Template: template,
+		Template: configTemplate,
 		Coordinate: coordinate.Coordinate{
 			Project:  context.ProjectId,
 			Type:     context.Type,
@@ -529,6 +663,11 @@ func validateParameterName(context *SingleConfigLoadContext, environment manifes
 func parseParameter(context *SingleConfigLoadContext, environment manifest.EnvironmentDefinition,
 	configId string, name string, param interface{}) (parameter.Parameter, error) {
 
+	folder := context.Folder
+	if inherited, ok := param.(inheritedFileParameter); ok {
+		param, folder = inherited.value, inherited.folder
+	}
+
 	if val, ok := param.([]interface{}); ok {
 		ref, err := arrayToReferenceParameter(context, environment, configId, name, val)
 
@@ -554,6 +693,10 @@ func parseParameter(context *SingleConfigLoadContext, environment manifest.Envir
 			},
 			ParameterName: name,
 			Value:         maps.ToStringMap(val),
+			Fs:            context.Fs,
+			Folder:        folder,
+			Variables:     context.Variables,
+			SecretKey:     context.SecretKey,
 		})
 	}
 
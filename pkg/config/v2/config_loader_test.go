@@ -640,12 +640,255 @@ configs:
 			nil,
 			[]string{ScopeParameter},
 		},
+		{
+			"dependsOn is parsed in all supported forms",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+  type:
+    api: some-api
+  dependsOn:
+  - other-profile
+  - [request-attributes, attribute]
+  - [other-project, request-attributes, attribute]
+  - configType: calculated-metrics-service
+    configId: metric`,
+			[]Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "some-api",
+						ConfigId: "profile",
+					},
+					Type: Type{
+						Api: "some-api",
+					},
+					Parameters: Parameters{
+						"name": &value.ValueParameter{Value: "Star Trek Service"},
+					},
+					Environment: "env name",
+					Group:       "default",
+					DependsOn: []coordinate.Coordinate{
+						{Project: "project", Type: "some-api", ConfigId: "other-profile"},
+						{Project: "project", Type: "request-attributes", ConfigId: "attribute"},
+						{Project: "other-project", Type: "request-attributes", ConfigId: "attribute"},
+						{Project: "project", Type: "calculated-metrics-service", ConfigId: "metric"},
+					},
+				},
+			},
+			nil,
+		},
+		{
+			"reports error for invalid dependsOn entries",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+  type:
+    api: some-api
+  dependsOn:
+  - profile
+  - [a, b, c, d]
+  - configType: some-api`,
+			nil,
+			[]string{"config must not depend on itself", "must have between 1 and 3 elements", "missing key `configId`"},
+		},
+		{
+			"reports error for unknown template functions",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: unknown-function.json
+  type:
+    api: some-api`,
+			nil,
+			[]string{`invalid template: ` + "`" + `template: unknown-function.json:1: function "camelCase" not defined`},
+		},
+		{
+			"forEach generates a config per inline item",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: monitor
+  forEach:
+    idProperty: site
+    items:
+    - site: shop
+      url: https://shop.example.com
+    - site: blog
+      url: https://blog.example.com
+  config:
+    name: Monitor
+    template: profile.json
+  type:
+    api: some-api`,
+			[]Config{
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "monitor-shop"},
+					Type:       Type{Api: "some-api"},
+					Parameters: Parameters{
+						"name": &value.ValueParameter{Value: "Monitor"},
+						"site": &value.ValueParameter{Value: "shop"},
+						"url":  &value.ValueParameter{Value: "https://shop.example.com"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "monitor-blog"},
+					Type:       Type{Api: "some-api"},
+					Parameters: Parameters{
+						"name": &value.ValueParameter{Value: "Monitor"},
+						"site": &value.ValueParameter{Value: "blog"},
+						"url":  &value.ValueParameter{Value: "https://blog.example.com"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+			nil,
+		},
+		{
+			"forEach loads items from CSV file and uses item name as config name",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: monitor
+  forEach:
+    file: sites.csv
+  config:
+    template: profile.json
+  type:
+    api: some-api`,
+			[]Config{
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "monitor-0"},
+					Type:       Type{Api: "some-api"},
+					Parameters: Parameters{
+						"name": &value.ValueParameter{Value: "Shop"},
+						"url":  &value.ValueParameter{Value: "https://shop.example.com"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+			nil,
+		},
+		{
+			"forEach uses values of list parameter as items",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: monitor
+  forEach:
+    parameter: urls
+  config:
+    name: Monitor
+    template: profile.json
+    parameters:
+      urls:
+        type: list
+        values: [https://shop.example.com]
+  type:
+    api: some-api`,
+			[]Config{
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "monitor-0"},
+					Type:       Type{Api: "some-api"},
+					Parameters: Parameters{
+						"name": &value.ValueParameter{Value: "Monitor"},
+						"item": &value.ValueParameter{Value: "https://shop.example.com"},
+						"urls": &list.ListParameter{Values: []value.ValueParameter{{Value: "https://shop.example.com"}}},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+			nil,
+		},
+		{
+			"forEach reports errors pointing to the originating item",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: monitor
+  forEach:
+    - url: https://shop.example.com
+    - url: https://blog.example.com
+      other: value
+  config:
+    name: Monitor
+    template: profile.json
+    parameters:
+      other: value
+  type:
+    api: some-api`,
+			nil,
+			[]string{"forEach item 1 of config `monitor` in `test-file.yaml`: item property `other` collides with parameter of the same name"},
+		},
+		{
+			"forEach reports duplicate generated config ids",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: monitor
+  forEach:
+    idProperty: site
+    items:
+    - site: shop
+    - site: shop
+  config:
+    name: Monitor
+    template: profile.json
+  type:
+    api: some-api`,
+			nil,
+			[]string{"forEach items 0 and 1 both generate the config id `monitor-shop`"},
+		},
+		{
+			"forEach requires exactly one source of items",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: monitor
+  forEach:
+    file: sites.csv
+    parameter: urls
+  config:
+    name: Monitor
+    template: profile.json
+  type:
+    api: some-api`,
+			nil,
+			[]string{"forEach requires exactly one of `items`, `file` or `parameter`"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			testFs := afero.NewMemMapFs()
 			_ = afero.WriteFile(testFs, tt.filePathOnDisk, []byte(tt.fileContentOnDisk), 0644)
 			_ = afero.WriteFile(testFs, "profile.json", []byte("{}"), 0644)
+			_ = afero.WriteFile(testFs, "unknown-function.json", []byte(`{"name": "{{ camelCase .name }}"}`), 0644)
+			_ = afero.WriteFile(testFs, "sites.csv", []byte("name,url\nShop,https://shop.example.com\n"), 0644)
 
 			gotConfigs, gotErrors := parseConfigs(testFs, testLoaderContext, tt.filePathArgument)
 			if len(tt.wantErrorsContain) != 0 {
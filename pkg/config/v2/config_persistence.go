@@ -41,10 +41,38 @@ type topLevelConfigDefinition struct {
 	Id                   string                `yaml:"id"`
 	Config               configDefinition      `yaml:"config"`
 	Type                 typeDefinition        `yaml:"type"`
+	DependsOn            []interface{}         `yaml:"dependsOn,omitempty"`
+	ForEach              *forEachDefinition    `yaml:"forEach,omitempty"`
+	Extends              string                `yaml:"extends,omitempty"`
 	GroupOverrides       []groupOverride       `yaml:"groupOverrides,omitempty"`
 	EnvironmentOverrides []environmentOverride `yaml:"environmentOverrides,omitempty"`
 }
 
+// forEachDefinition generates one config per item. Exactly one source of items must be set.
+type forEachDefinition struct {
+	// Items are defined inline
+	Items []interface{} `yaml:"items,omitempty"`
+	// File is the path of a YAML or CSV file holding the items, relative to the config file
+	File string `yaml:"file,omitempty"`
+	// Parameter is the name of a parameter of type list of the config, whose values are the items
+	Parameter string `yaml:"parameter,omitempty"`
+	// IdProperty is the item property the ids of the generated configs are derived from. If it is not set, the
+	// index of the item is used.
+	IdProperty string `yaml:"idProperty,omitempty"`
+}
+
+// UnmarshalYAML allows to define the items of a forEachDefinition as plain list
+func (d *forEachDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var items []interface{}
+	if err := unmarshal(&items); err == nil {
+		d.Items = items
+		return nil
+	}
+
+	type plain forEachDefinition
+	return unmarshal((*plain)(d))
+}
+
 type topLevelDefinition struct {
 	Configs []topLevelConfigDefinition `yaml:"configs"`
 }
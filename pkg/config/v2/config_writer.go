@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	fileParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/file"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
 	"github.com/spf13/afero"
@@ -255,11 +256,26 @@ func toTopLevelConfigDefinition(context *serializerContext, configs []Config) (t
 		Id:                   context.config.ConfigId,
 		Config:               config,
 		Type:                 ct,
+		DependsOn:            toDependsOnDefinition(configs[0].DependsOn),
 		GroupOverrides:       groupOverrideConfigs,
 		EnvironmentOverrides: environmentOverrideConfigs,
 	}, templates, nil
 }
 
+// toDependsOnDefinition converts the coordinates a config depends on to their long list form. Since dependsOn is
+// defined per config and not per environment, all configs of the same coordinate share the same dependencies.
+func toDependsOnDefinition(dependsOn []coordinate.Coordinate) []interface{} {
+	if len(dependsOn) == 0 {
+		return nil
+	}
+
+	result := make([]interface{}, 0, len(dependsOn))
+	for _, c := range dependsOn {
+		result = append(result, []interface{}{c.Project, c.Type, c.ConfigId})
+	}
+	return result
+}
+
 func extractConfigType(context *serializerContext, config Config) (typeDefinition, error) {
 	if config.Type.IsSettings() {
 		return getConfigTypeSettings(config, context)
@@ -515,7 +531,14 @@ func toConfigDefinitions(context *serializerContext, configs []Config) ([]extend
 			continue
 		}
 
+		referencedFiles, err := extractReferencedFiles(context, c.Parameters)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Coordinate, err))
+			continue
+		}
+
 		templates = append(templates, templ)
+		templates = append(templates, referencedFiles...)
 
 		result = append(result, extendedConfigDefinition{
 			configDefinition: definition,
@@ -611,6 +634,26 @@ func extractTemplate(context *detailedSerializerContext, config Config) (string,
 	return "", configTemplate{}, errors.New("unknown template type")
 }
 
+// extractReferencedFiles returns the files referenced by file parameters, so that they are written next to the config
+func extractReferencedFiles(context *serializerContext, parameters Parameters) ([]configTemplate, error) {
+	var result []configTemplate
+
+	for name, param := range parameters {
+		if f, ok := param.(*fileParam.FileParameter); ok {
+			if err := fileParam.ValidatePath(filepath.FromSlash(f.Path)); err != nil {
+				return nil, fmt.Errorf("file parameter `%s`: %w", name, err)
+			}
+
+			result = append(result, configTemplate{
+				templatePath: filepath.Join(context.configFolder, filepath.FromSlash(f.Path)),
+				content:      f.Content,
+			})
+		}
+	}
+
+	return result, nil
+}
+
 func convertParameters(context *detailedSerializerContext, parameters Parameters) (map[string]configParameter, []error) {
 	var errs []error
 	result := make(map[string]configParameter)
@@ -22,6 +22,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/testutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	envParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/environment"
+	fileParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/file"
 	refParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
 	"github.com/spf13/afero"
@@ -914,6 +915,59 @@ func TestWriteConfigs(t *testing.T) {
 				"project/schemaid/a.json",
 			},
 		},
+		{
+			name: "File parameters and dependsOn",
+			configs: []Config{
+				{
+					Template: template.CreateTemplateFromString("project/dashboard/a.json", ""),
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "dashboard",
+						ConfigId: "configId",
+					},
+					Type: Type{
+						Api: "dashboard",
+					},
+					Parameters: map[string]parameter.Parameter{
+						NameParameter: &value.ValueParameter{Value: "name"},
+						"markdown":    fileParam.New("tiles/markdown.md", "# Title", true),
+					},
+					DependsOn: []coordinate.Coordinate{
+						{Project: "other", Type: "auto-tag", ConfigId: "tag"},
+					},
+				},
+			},
+			expectedConfigs: map[string]topLevelDefinition{
+				"dashboard": {
+					Configs: []topLevelConfigDefinition{
+						{
+							Id: "configId",
+							Config: configDefinition{
+								Name: "name",
+								Parameters: map[string]configParameter{
+									"markdown": map[any]any{
+										"type": "file",
+										"path": "tiles/markdown.md",
+									},
+								},
+								Template: "a.json",
+								Skip:     false,
+							},
+							Type: typeDefinition{
+								Api: "dashboard",
+							},
+							DependsOn: []interface{}{
+								[]interface{}{"other", "auto-tag", "tag"},
+							},
+						},
+					},
+				},
+			},
+			expectedTemplatePaths: []string{
+				"project/dashboard/a.json",
+				"project/dashboard/tiles/markdown.md",
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -954,3 +1008,49 @@ func TestWriteConfigs(t *testing.T) {
 	}
 
 }
+
+func TestWriteConfigsRejectsFileParametersOutsideOfConfigFolder(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"absolute path", "/etc/passwd"},
+		{"path outside of folder", "../../outside.md"},
+		{"cleaned path outside of folder", "tiles/../../../outside.md"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := testutils.TempFs(t)
+
+			errs := WriteConfigs(&WriterContext{
+				Fs:              fs,
+				OutputFolder:    "test",
+				ProjectFolder:   "project",
+				ParametersSerde: DefaultParameterParsers,
+			}, []Config{
+				{
+					Template: template.CreateTemplateFromString("project/dashboard/a.json", ""),
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "dashboard",
+						ConfigId: "configId",
+					},
+					Type: Type{
+						Api: "dashboard",
+					},
+					Parameters: map[string]parameter.Parameter{
+						NameParameter: &value.ValueParameter{Value: "name"},
+						"markdown":    fileParam.New(tc.path, "# Title", true),
+					},
+				},
+			})
+			assert.Equal(t, len(errs), 1, "Writing configs should produce an error")
+			assert.ErrorContains(t, errs[0], "file parameter `markdown`")
+
+			found, err := afero.Exists(fs, "outside.md")
+			assert.NilError(t, err)
+			assert.Equal(t, found, false, "file outside of the config folder must not be written")
+		})
+	}
+}
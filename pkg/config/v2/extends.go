@@ -0,0 +1,292 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	fileParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/file"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"strings"
+)
+
+// Definition is a config definition as written in a config file, before it is parsed for any environment
+type Definition struct {
+	Coordinate coordinate.Coordinate
+	// Folder is the folder of the config file the definition is loaded from
+	Folder string
+	// Extends is the coordinate of the config definition this definition extends. It is nil if the definition does
+	// not extend any other definition.
+	Extends *coordinate.Coordinate
+
+	definition topLevelConfigDefinition
+}
+
+// Definitions maps config coordinates to their definition
+type Definitions map[coordinate.Coordinate]Definition
+
+// LoadDefinitions loads the config definitions of all config files in the given path, without parsing them. They
+// are used to resolve `extends` of configs in other files and projects.
+func LoadDefinitions(fs afero.Fs, projectId string, path string) ([]Definition, []error) {
+	filesInFolder, err := afero.ReadDir(fs, path)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var result []Definition
+	var errs []error
+
+	for _, file := range filesInFolder {
+		if file.IsDir() || !files.IsYamlFileExtension(file.Name()) {
+			continue
+		}
+
+		filePath := filepath.Join(path, file.Name())
+
+		definition, err := loadDefinitionFile(fs, filePath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, d := range definition.Configs {
+			c := coordinate.Coordinate{
+				Project:  projectId,
+				Type:     d.Type.GetApiType(),
+				ConfigId: d.Id,
+			}
+
+			var extends *coordinate.Coordinate
+			if d.Extends != "" {
+				parent, err := parseExtends(c, d.Extends)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("config `%s` in `%s`: %w", c, filePath, err))
+					continue
+				}
+				extends = &parent
+			}
+
+			result = append(result, Definition{
+				Coordinate: c,
+				Folder:     filepath.Dir(filePath),
+				Extends:    extends,
+				definition: d,
+			})
+		}
+	}
+
+	return result, errs
+}
+
+// parseExtends parses the coordinate of the config extended by the config with the given coordinate. It is either a
+// config id of the same project and type, or a full coordinate `project:type:configId`.
+func parseExtends(self coordinate.Coordinate, extends string) (coordinate.Coordinate, error) {
+	parts := strings.Split(extends, ":")
+
+	result := self
+	switch {
+	case len(parts) == 1:
+		result.ConfigId = extends
+	case len(parts) >= 3:
+		// settings schemas contain colons, therefore only the first and last part are split off
+		result.Project = parts[0]
+		result.Type = strings.Join(parts[1:len(parts)-1], ":")
+		result.ConfigId = parts[len(parts)-1]
+	default:
+		return coordinate.Coordinate{}, fmt.Errorf("`extends` must be a config id or a coordinate `project:type:configId`, but is `%s`", extends)
+	}
+
+	if result.Project == "" || result.Type == "" || result.ConfigId == "" {
+		return coordinate.Coordinate{}, fmt.Errorf("`extends` (`%s`) must not contain empty values", extends)
+	}
+
+	if result == self {
+		return coordinate.Coordinate{}, fmt.Errorf("config must not extend itself")
+	}
+
+	return result, nil
+}
+
+// resolveExtends merges the given definition with the chain of definitions it extends. Each definition inherits
+// name, template, skip and parameters of the definition it extends, as well as its group and environment overrides,
+// its dependsOn entries and its forEach definition.
+// The precedence of overrides is kept: environment overrides take precedence over group overrides, which take
+// precedence over the base config. On each of these levels, values of the extending definition take precedence.
+func resolveExtends(context *ConfigLoaderContext, definition topLevelConfigDefinition) (topLevelConfigDefinition, error) {
+	singleConfigContext := &SingleConfigLoadContext{
+		ConfigLoaderContext: context,
+		Type:                definition.Type.GetApiType(),
+	}
+
+	self := coordinate.Coordinate{
+		Project:  context.ProjectId,
+		Type:     singleConfigContext.Type,
+		ConfigId: definition.Id,
+	}
+
+	parentCoordinate, err := parseExtends(self, definition.Extends)
+	if err != nil {
+		return topLevelConfigDefinition{}, newDefinitionParserError(definition.Id, singleConfigContext, err.Error())
+	}
+
+	visited := map[coordinate.Coordinate]struct{}{self: {}}
+	result := definition
+
+	for {
+		if _, found := visited[parentCoordinate]; found {
+			return topLevelConfigDefinition{}, newDefinitionParserError(definition.Id, singleConfigContext,
+				fmt.Sprintf("`extends` of config contains a cycle at `%s`", parentCoordinate))
+		}
+		visited[parentCoordinate] = struct{}{}
+
+		parent, found := context.Definitions[parentCoordinate]
+		if !found {
+			return topLevelConfigDefinition{}, newDefinitionParserError(definition.Id, singleConfigContext,
+				fmt.Sprintf("config extends unknown config `%s`", parentCoordinate))
+		}
+
+		result = mergeDefinitions(rebasePaths(parent.definition, parent.Folder, context.Folder), result)
+
+		if parent.Extends == nil {
+			break
+		}
+		parentCoordinate = *parent.Extends
+	}
+
+	result.Extends = ""
+	return result, nil
+}
+
+// inheritedFileParameter is a file parameter inherited from a definition in another folder. Its path stays relative
+// to the folder of that definition, as file parameters must not point outside the folder they are defined in.
+type inheritedFileParameter struct {
+	value  map[interface{}]interface{}
+	folder string
+}
+
+// rebasePaths returns a copy of the given definition, with all template and forEach file paths relative to the
+// folder `to` instead of the folder `from`. File parameters keep their path and are marked to be relative to `from`.
+func rebasePaths(definition topLevelConfigDefinition, from string, to string) topLevelConfigDefinition {
+	if from == to {
+		return definition
+	}
+
+	rebasePath := func(path string) string {
+		if rel, err := filepath.Rel(to, filepath.Join(from, filepath.FromSlash(path))); err == nil {
+			return filepath.ToSlash(rel)
+		}
+		return path
+	}
+
+	rebase := func(d configDefinition) configDefinition {
+		if d.Template != "" {
+			d.Template = rebasePath(d.Template)
+		}
+
+		parameters := make(map[string]configParameter, len(d.Parameters))
+		for name, param := range d.Parameters {
+			if m, ok := param.(map[interface{}]interface{}); ok && toString(m["type"]) == fileParam.FileParameterType {
+				param = inheritedFileParameter{value: m, folder: from}
+			}
+			parameters[name] = param
+		}
+		d.Parameters = parameters
+
+		return d
+	}
+
+	result := definition
+	result.Config = rebase(definition.Config)
+
+	if definition.ForEach != nil && definition.ForEach.File != "" {
+		forEach := *definition.ForEach
+		forEach.File = rebasePath(forEach.File)
+		result.ForEach = &forEach
+	}
+
+	result.GroupOverrides = make([]groupOverride, len(definition.GroupOverrides))
+	for i, o := range definition.GroupOverrides {
+		result.GroupOverrides[i] = groupOverride{Group: o.Group, Override: rebase(o.Override)}
+	}
+
+	result.EnvironmentOverrides = make([]environmentOverride, len(definition.EnvironmentOverrides))
+	for i, o := range definition.EnvironmentOverrides {
+		result.EnvironmentOverrides[i] = environmentOverride{Environment: o.Environment, Override: rebase(o.Override)}
+	}
+
+	return result
+}
+
+// mergeDefinitions returns the definition extending the given parent definition. The child inherits the forEach
+// definition of the parent, if it does not define one itself. The dependsOn entries of both are combined; like short
+// references in parameters, entries of the parent are relative to the child.
+func mergeDefinitions(parent topLevelConfigDefinition, child topLevelConfigDefinition) topLevelConfigDefinition {
+	result := child
+	result.Config = mergeConfigDefinitions(parent.Config, child.Config)
+	result.Config.OriginObjectId = child.Config.OriginObjectId
+
+	if result.ForEach == nil {
+		result.ForEach = parent.ForEach
+	}
+
+	result.DependsOn = append(append([]interface{}{}, child.DependsOn...), parent.DependsOn...)
+	if len(result.DependsOn) == 0 {
+		result.DependsOn = nil
+	}
+
+	result.GroupOverrides = nil
+	groupIndices := make(map[string]int)
+	for _, o := range append(append([]groupOverride{}, parent.GroupOverrides...), child.GroupOverrides...) {
+		if i, found := groupIndices[o.Group]; found {
+			result.GroupOverrides[i].Override = mergeConfigDefinitions(result.GroupOverrides[i].Override, o.Override)
+			continue
+		}
+		groupIndices[o.Group] = len(result.GroupOverrides)
+		result.GroupOverrides = append(result.GroupOverrides, groupOverride{Group: o.Group, Override: mergeConfigDefinitions(configDefinition{}, o.Override)})
+	}
+
+	result.EnvironmentOverrides = nil
+	environmentIndices := make(map[string]int)
+	for _, o := range append(append([]environmentOverride{}, parent.EnvironmentOverrides...), child.EnvironmentOverrides...) {
+		if i, found := environmentIndices[o.Environment]; found {
+			result.EnvironmentOverrides[i].Override = mergeConfigDefinitions(result.EnvironmentOverrides[i].Override, o.Override)
+			continue
+		}
+		environmentIndices[o.Environment] = len(result.EnvironmentOverrides)
+		result.EnvironmentOverrides = append(result.EnvironmentOverrides, environmentOverride{Environment: o.Environment, Override: mergeConfigDefinitions(configDefinition{}, o.Override)})
+	}
+
+	return result
+}
+
+// mergeConfigDefinitions returns a new config definition containing all values of base, overridden by all values
+// set in override
+func mergeConfigDefinitions(base configDefinition, override configDefinition) configDefinition {
+	result := base
+	result.Parameters = make(map[string]configParameter, len(base.Parameters)+len(override.Parameters))
+	for name, param := range base.Parameters {
+		result.Parameters[name] = param
+	}
+
+	applyOverrides(&result, override)
+
+	if override.OriginObjectId != "" {
+		result.OriginObjectId = override.OriginObjectId
+	}
+
+	return result
+}
@@ -0,0 +1,84 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package v2
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestParseExtends(t *testing.T) {
+	self := coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "dash"}
+
+	tests := []struct {
+		name          string
+		extends       string
+		want          coordinate.Coordinate
+		wantErrorText string
+	}{
+		{
+			"local config id",
+			"other",
+			coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "other"},
+			"",
+		},
+		{
+			"full coordinate",
+			"other-project:alerting-profile:profile",
+			coordinate.Coordinate{Project: "other-project", Type: "alerting-profile", ConfigId: "profile"},
+			"",
+		},
+		{
+			"full coordinate of settings schema containing colons",
+			"other-project:builtin:alerting.profile:profile",
+			coordinate.Coordinate{Project: "other-project", Type: "builtin:alerting.profile", ConfigId: "profile"},
+			"",
+		},
+		{
+			"two parts are ambiguous",
+			"dashboard:other",
+			coordinate.Coordinate{},
+			"must be a config id or a coordinate",
+		},
+		{
+			"empty values",
+			"project::other",
+			coordinate.Coordinate{},
+			"must not contain empty values",
+		},
+		{
+			"self",
+			"dash",
+			coordinate.Coordinate{},
+			"must not extend itself",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExtends(self, tt.extends)
+			if tt.wantErrorText != "" {
+				assert.ErrorContains(t, err, tt.wantErrorText)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
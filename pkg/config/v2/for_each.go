@@ -0,0 +1,308 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	configErrors "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/errors"
+	listParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/list"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ForEachItemParameter is the name of the parameter holding the value of a forEach item which is not a map
+const ForEachItemParameter = "item"
+
+// forEachItem is a single item of a forEach definition. Each property of the item is exposed as parameter of the
+// config generated for it.
+type forEachItem struct {
+	// index of the item in the list of items
+	index int
+	// configId is the id of the config generated for the item
+	configId string
+	// properties of the item
+	properties map[string]interface{}
+}
+
+// ForEachItemError is returned for errors of a config generated by a forEach definition. It points to the item the
+// config was generated for.
+type ForEachItemError struct {
+	Location           coordinate.Coordinate
+	EnvironmentDetails configErrors.EnvironmentDetails
+	Path               string
+	// ConfigId is the id of the config defining forEach
+	ConfigId string
+	// Item is the index of the item the failing config was generated for
+	Item int
+	Err  error
+}
+
+func newForEachItemError(context *SingleConfigLoadContext, configId string, item forEachItem, err error) ForEachItemError {
+	result := ForEachItemError{
+		Location: coordinate.Coordinate{
+			Project:  context.ProjectId,
+			Type:     context.Type,
+			ConfigId: item.configId,
+		},
+		Path:     context.Path,
+		ConfigId: configId,
+		Item:     item.index,
+		Err:      err,
+	}
+
+	var detailedErr configErrors.DetailedConfigError
+	if errors.As(err, &detailedErr) {
+		result.EnvironmentDetails = detailedErr.LocationDetails()
+	}
+
+	return result
+}
+
+func (e ForEachItemError) Coordinates() coordinate.Coordinate {
+	return e.Location
+}
+
+func (e ForEachItemError) LocationDetails() configErrors.EnvironmentDetails {
+	return e.EnvironmentDetails
+}
+
+func (e ForEachItemError) Unwrap() error {
+	return e.Err
+}
+
+func (e ForEachItemError) Error() string {
+	return fmt.Sprintf("forEach item %d of config `%s` in `%s`: %s", e.Item, e.ConfigId, e.Path, e.Err)
+}
+
+var _ configErrors.DetailedConfigError = (*ForEachItemError)(nil)
+
+// parseForEachDefinition generates a config definition per item of the forEach definition of the given config, and
+// parses all of them. The ids of the generated configs are the id of the config, followed by a dash and the value of
+// the `idProperty` of the item, or the index of the item if no `idProperty` is defined.
+func parseForEachDefinition(fs afero.Fs, context *ConfigLoaderContext, definition topLevelConfigDefinition) ([]Config, []error) {
+	singleConfigContext := &SingleConfigLoadContext{
+		ConfigLoaderContext: context,
+		Type:                definition.Type.GetApiType(),
+	}
+
+	items, err := loadForEachItems(fs, context, definition)
+	if err != nil {
+		return nil, []error{newDefinitionParserError(definition.Id, singleConfigContext, err.Error())}
+	}
+
+	var configs []Config
+	var errs []error
+
+	for _, item := range items {
+		itemDefinition, err := definitionForItem(definition, item)
+		if err != nil {
+			errs = append(errs, newForEachItemError(singleConfigContext, definition.Id, item, err))
+			continue
+		}
+
+		result, definitionErrors := parseDefinition(fs, context, item.configId, itemDefinition)
+		for _, e := range definitionErrors {
+			errs = append(errs, newForEachItemError(singleConfigContext, definition.Id, item, e))
+		}
+
+		configs = append(configs, result...)
+	}
+
+	if errs != nil {
+		return nil, errs
+	}
+
+	return configs, nil
+}
+
+// definitionForItem returns a copy of the given definition for the given item. The properties of the item are added
+// to the parameters of the config as value parameters. The property `name` is used as name of the config, if the
+// config does not define one.
+func definitionForItem(definition topLevelConfigDefinition, item forEachItem) (topLevelConfigDefinition, error) {
+	parameters := make(map[string]configParameter, len(definition.Config.Parameters)+len(item.properties))
+	for name, param := range definition.Config.Parameters {
+		parameters[name] = param
+	}
+
+	result := definition
+
+	for name, v := range item.properties {
+		param := map[interface{}]interface{}{"type": valueParam.ValueParameterType, "value": v}
+
+		if name == NameParameter {
+			if definition.Config.Name != nil {
+				return topLevelConfigDefinition{}, errors.New("item property `name` collides with the name of the config")
+			}
+			result.Config.Name = param
+			continue
+		}
+
+		if _, found := parameters[name]; found {
+			return topLevelConfigDefinition{}, fmt.Errorf("item property `%s` collides with parameter of the same name", name)
+		}
+		parameters[name] = param
+	}
+
+	result.Id = item.configId
+	result.ForEach = nil
+	result.Config.Parameters = parameters
+
+	return result, nil
+}
+
+// loadForEachItems loads the items of the forEach definition of the given config from its source
+func loadForEachItems(fs afero.Fs, context *ConfigLoaderContext, definition topLevelConfigDefinition) ([]forEachItem, error) {
+	forEach := definition.ForEach
+
+	sources := 0
+	for _, isSet := range []bool{forEach.Items != nil, forEach.File != "", forEach.Parameter != ""} {
+		if isSet {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, errors.New("forEach requires exactly one of `items`, `file` or `parameter`")
+	}
+
+	var rawItems []interface{}
+	var err error
+
+	switch {
+	case forEach.File != "":
+		rawItems, err = readForEachFile(fs, filepath.Join(context.Folder, filepath.FromSlash(forEach.File)))
+	case forEach.Parameter != "":
+		rawItems, err = listParameterValues(definition.Config.Parameters, forEach.Parameter)
+	default:
+		rawItems = forEach.Items
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]forEachItem, 0, len(rawItems))
+	generatedIds := make(map[string]int, len(rawItems))
+
+	for i, raw := range rawItems {
+		var properties map[string]interface{}
+
+		switch v := raw.(type) {
+		case map[interface{}]interface{}:
+			properties = maps.ToStringMap(v)
+		case map[string]interface{}:
+			properties = v
+		case []interface{}:
+			return nil, fmt.Errorf("forEach item %d must be a map or a single value, but is a list", i)
+		default:
+			properties = map[string]interface{}{ForEachItemParameter: v}
+		}
+
+		suffix := strconv.Itoa(i)
+		if forEach.IdProperty != "" {
+			v, found := properties[forEach.IdProperty]
+			if !found || toString(v) == "" {
+				return nil, fmt.Errorf("forEach item %d has no value for property `%s`", i, forEach.IdProperty)
+			}
+			suffix = toString(v)
+		}
+
+		configId := definition.Id + "-" + suffix
+		if other, found := generatedIds[configId]; found {
+			return nil, fmt.Errorf("forEach items %d and %d both generate the config id `%s`", other, i, configId)
+		}
+		generatedIds[configId] = i
+
+		items = append(items, forEachItem{
+			index:      i,
+			configId:   configId,
+			properties: properties,
+		})
+	}
+
+	return items, nil
+}
+
+// readForEachFile reads the items of a forEach definition from a YAML or CSV file. A YAML file contains a list of
+// items. The first line of a CSV file holds the names of the properties, each following line is an item.
+func readForEachFile(fs afero.Fs, path string) ([]interface{}, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read forEach file: %w", err)
+	}
+
+	if files.IsYamlFileExtension(path) {
+		var items []interface{}
+		if err := yaml.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("forEach file `%s` must contain a list of items: %w", path, err)
+		}
+		return items, nil
+	}
+
+	if strings.ToLower(filepath.Ext(path)) != ".csv" {
+		return nil, fmt.Errorf("forEach file `%s` must be a YAML or CSV file", path)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse forEach file `%s`: %w", path, err)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("forEach file `%s` is missing the header line", path)
+	}
+
+	header := records[0]
+	items := make([]interface{}, 0, len(records)-1)
+
+	for _, record := range records[1:] {
+		item := make(map[string]interface{}, len(header))
+		for i, name := range header {
+			item[name] = record[i]
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// listParameterValues returns the values of the parameter of type list with the given name
+func listParameterValues(parameters map[string]configParameter, name string) ([]interface{}, error) {
+	param, found := parameters[name]
+	if !found {
+		return nil, fmt.Errorf("forEach parameter `%s` is not defined", name)
+	}
+
+	m, ok := param.(map[interface{}]interface{})
+	if !ok || toString(m["type"]) != listParam.ListParameterType {
+		return nil, fmt.Errorf("forEach parameter `%s` must be of type `%s`", name, listParam.ListParameterType)
+	}
+
+	values, ok := m["values"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("forEach parameter `%s` must define a list of `values`", name)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,88 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package v2
+
+import (
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestReadForEachFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		content       string
+		want          []interface{}
+		wantErrorText string
+	}{
+		{
+			"reads list of items from YAML",
+			"items.yaml",
+			"- site: shop\n- blog\n",
+			[]interface{}{map[interface{}]interface{}{"site": "shop"}, "blog"},
+			"",
+		},
+		{
+			"reads items from CSV",
+			"items.csv",
+			"site,url\nshop,https://shop.example.com\nblog,https://blog.example.com\n",
+			[]interface{}{
+				map[string]interface{}{"site": "shop", "url": "https://shop.example.com"},
+				map[string]interface{}{"site": "blog", "url": "https://blog.example.com"},
+			},
+			"",
+		},
+		{
+			"fails for YAML not containing a list",
+			"items.yaml",
+			"site: shop\n",
+			nil,
+			"must contain a list of items",
+		},
+		{
+			"fails for CSV with inconsistent number of fields",
+			"items.csv",
+			"site,url\nshop\n",
+			nil,
+			"failed to parse forEach file",
+		},
+		{
+			"fails for unsupported file types",
+			"items.json",
+			"[]",
+			nil,
+			"must be a YAML or CSV file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			_ = afero.WriteFile(fs, tt.path, []byte(tt.content), 0644)
+
+			got, err := readForEachFile(fs, tt.path)
+			if tt.wantErrorText != "" {
+				assert.ErrorContains(t, err, tt.wantErrorText)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
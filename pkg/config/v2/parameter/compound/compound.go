@@ -71,8 +71,15 @@ func (p *CompoundParameter) ResolveValue(context parameter.ResolveContext) (inte
 		compoundData[param.Property] = context.ResolvedParameterValues[param.Property]
 	}
 
+	// the format is shared by parallel deployments, so the functions are bound to the context on a copy
+	format, err := p.format.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("error resolving compound value: %w", err)
+	}
+	format.Funcs(template.Funcs(template.Context{Environment: context.Environment, Group: context.Group}))
+
 	out := bytes.Buffer{}
-	err := p.format.Execute(&out, compoundData)
+	err = format.Execute(&out, compoundData)
 
 	if err != nil {
 		return nil, fmt.Errorf("error resolving compound value: %w", err)
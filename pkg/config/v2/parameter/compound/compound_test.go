@@ -124,6 +124,23 @@ func TestResolveValue(t *testing.T) {
 	assert.Equal(t, "Hello World!", strings.ToString(result))
 }
 
+func TestResolveValueWithFunctions(t *testing.T) {
+	testFormat := `{{ .name | upper }}{{ if isEnvironment "prod" }} (production){{ end }}`
+	context := parameter.ResolveContext{
+		Environment: "prod",
+		ResolvedParameterValues: parameter.Properties{
+			"name": "Hansi",
+		},
+	}
+	compoundParameter, err := New("testName", testFormat, []parameter.ParameterReference{{Property: "name"}})
+	assert.NilError(t, err)
+
+	result, err := compoundParameter.ResolveValue(context)
+	assert.NilError(t, err)
+
+	assert.Equal(t, "HANSI (production)", strings.ToString(result))
+}
+
 func TestResolveComplexValue(t *testing.T) {
 	testFormat := "{{ .person.name }} is {{ .person.age }} years old"
 	context := parameter.ResolveContext{
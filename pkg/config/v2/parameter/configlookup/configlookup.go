@@ -0,0 +1,277 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configlookup
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"sort"
+	"strings"
+)
+
+// ConfigLookupParameterType specifies the type of the parameter used in config files
+const ConfigLookupParameterType = "configLookup"
+
+var ConfigLookupParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeConfigLookupParameter,
+	Deserializer: parseConfigLookupParameter,
+}
+
+// ConfigLookupParameter resolves to the ID of an existing config in the environment the config is deployed to.
+// This allows to reference objects not managed by monaco, e.g. a management zone created by another team.
+//
+// Classic configs are looked up by Api and Name. Settings objects are looked up by Schema and Filter, and
+// optionally Scope.
+type ConfigLookupParameter struct {
+	// Api is the ID of the classic config API to look up the config in
+	Api string
+
+	// Name of the classic config to look up
+	Name string
+
+	// Schema is the ID of the settings schema to look up the settings object in
+	Schema string
+
+	// Scope is the optional scope of the settings object to look up
+	Scope string
+
+	// Filter maps fields of the settings value to the value they need to have. Nested fields are separated by dots,
+	// e.g. `rules.enabled`.
+	Filter map[string]interface{}
+}
+
+// NewConfig returns a parameter looking up the classic config of the given api with the given name
+func NewConfig(api string, name string) *ConfigLookupParameter {
+	return &ConfigLookupParameter{
+		Api:  api,
+		Name: name,
+	}
+}
+
+// NewSetting returns a parameter looking up the settings object of the given schema and scope matching the filter
+func NewSetting(schema string, scope string, filter map[string]interface{}) *ConfigLookupParameter {
+	return &ConfigLookupParameter{
+		Schema: schema,
+		Scope:  scope,
+		Filter: filter,
+	}
+}
+
+// this forces the compiler to check if ConfigLookupParameter is of type Parameter
+var _ parameter.Parameter = (*ConfigLookupParameter)(nil)
+
+func (p *ConfigLookupParameter) GetType() string {
+	return ConfigLookupParameterType
+}
+
+func (p *ConfigLookupParameter) GetReferences() []parameter.ParameterReference {
+	// config lookup parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *ConfigLookupParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	if context.ConfigLookup == nil {
+		return nil, parameter.NewParameterResolveValueError(context, "configs can only be looked up while deploying to an environment")
+	}
+
+	if p.Api != "" {
+		return p.resolveConfig(context)
+	}
+	return p.resolveSetting(context)
+}
+
+func (p *ConfigLookupParameter) resolveConfig(context parameter.ResolveContext) (interface{}, error) {
+	id, found, err := context.ConfigLookup.LookupConfigId(p.Api, p.Name)
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to look up `%s` config named `%s`: %s", p.Api, p.Name, err))
+	}
+
+	if !found {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("no `%s` config named `%s` exists", p.Api, p.Name))
+	}
+
+	return id, nil
+}
+
+func (p *ConfigLookupParameter) resolveSetting(context parameter.ResolveContext) (interface{}, error) {
+	ids, err := context.ConfigLookup.LookupSettingIds(p.Schema, p.Scope, p.matches)
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to look up `%s` settings matching %s: %s", p.Schema, p.describeFilter(), err))
+	}
+
+	switch len(ids) {
+	case 0:
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("no `%s` settings object matches %s", p.Schema, p.describeFilter()))
+	case 1:
+		return ids[0], nil
+	default:
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("%d `%s` settings objects match %s, but exactly one was expected: %s", len(ids), p.Schema, p.describeFilter(), strings.Join(ids, ", ")))
+	}
+}
+
+// matches returns whether all filtered fields of the given settings value have the expected values
+func (p *ConfigLookupParameter) matches(value []byte) bool {
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(value, &parsed); err != nil {
+		return false
+	}
+
+	for path, expected := range p.Filter {
+		actual, found := lookupField(parsed, strings.Split(path, "."))
+		if !found || fmt.Sprint(actual) != fmt.Sprint(expected) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func lookupField(value map[string]interface{}, path []string) (interface{}, bool) {
+	field, found := value[path[0]]
+	if !found || len(path) == 1 {
+		return field, found
+	}
+
+	nested, isMap := field.(map[string]interface{})
+	if !isMap {
+		return nil, false
+	}
+	return lookupField(nested, path[1:])
+}
+
+// describeFilter returns a readable, stable representation of the filter and scope used in error messages
+func (p *ConfigLookupParameter) describeFilter() string {
+	conditions := make([]string, 0, len(p.Filter)+1)
+	for path, expected := range p.Filter {
+		conditions = append(conditions, fmt.Sprintf("%s=%v", path, expected))
+	}
+	sort.Strings(conditions)
+
+	if p.Scope != "" {
+		conditions = append(conditions, fmt.Sprintf("scope %s", p.Scope))
+	}
+
+	return "`" + strings.Join(conditions, ", ") + "`"
+}
+
+// parseConfigLookupParameter parses a ConfigLookupParameter from a given context. it requires either `api` and
+// `name`, or `schema` and `filter` to be set. For settings, `name` can be used as shorthand for a filter on the
+// `name` field, and `scope` is optional.
+func parseConfigLookupParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	api, err := parameter.OptionalString(context, "api")
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := parameter.OptionalString(context, "schema")
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := parameter.OptionalString(context, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := parameter.OptionalString(context, "scope")
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := parseFilter(context)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case api != "" && schema != "":
+		return nil, parameter.NewParameterParserError(context, "only one of `api` and `schema` can be set")
+	case api != "":
+		if name == "" {
+			return nil, parameter.NewParameterParserError(context, "missing property `name`")
+		}
+		if scope != "" || filter != nil {
+			return nil, parameter.NewParameterParserError(context, "properties `scope` and `filter` can only be used with `schema`")
+		}
+		return NewConfig(api, name), nil
+	case schema != "":
+		if name != "" {
+			if _, found := filter["name"]; found {
+				return nil, parameter.NewParameterParserError(context, "`name` cannot be set both as property and in `filter`")
+			}
+			if filter == nil {
+				filter = map[string]interface{}{}
+			}
+			filter["name"] = name
+		}
+		if len(filter) == 0 {
+			return nil, parameter.NewParameterParserError(context, "missing property `filter` or `name`")
+		}
+		return NewSetting(schema, scope, filter), nil
+	default:
+		return nil, parameter.NewParameterParserError(context, "missing property `api` or `schema`")
+	}
+}
+
+func parseFilter(context parameter.ParameterParserContext) (map[string]interface{}, error) {
+	val, ok := context.Value["filter"]
+	if !ok {
+		return nil, nil
+	}
+
+	filterMap, isMap := val.(map[interface{}]interface{})
+	if !isMap {
+		return nil, parameter.NewParameterParserError(context, "malformed property `filter` - expected map")
+	}
+
+	filter := maps.ToStringMap(filterMap)
+	for k, v := range filter {
+		switch v.(type) {
+		case string, bool, int, float64:
+		default:
+			return nil, parameter.NewParameterParserError(context, fmt.Sprintf("malformed filter value `%v` of field `%v` - expected string, number or boolean", v, k))
+		}
+	}
+
+	return filter, nil
+}
+
+func writeConfigLookupParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	lookupParam, ok := context.Parameter.(*ConfigLookupParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `ConfigLookupParameter`")
+	}
+
+	result := make(map[string]interface{})
+
+	if lookupParam.Api != "" {
+		result["api"] = lookupParam.Api
+		result["name"] = lookupParam.Name
+		return result, nil
+	}
+
+	result["schema"] = lookupParam.Schema
+
+	if lookupParam.Scope != "" {
+		result["scope"] = lookupParam.Scope
+	}
+
+	result["filter"] = lookupParam.Filter
+
+	return result, nil
+}
@@ -0,0 +1,210 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package configlookup
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"gotest.tools/assert"
+	"testing"
+)
+
+// staticLookup finds configs in a fixed set of configs and settings values
+type staticLookup struct {
+	configIds map[string]string
+	settings  map[string]string
+	err       error
+}
+
+func (l staticLookup) LookupConfigId(api string, name string) (string, bool, error) {
+	id, found := l.configIds[api+"/"+name]
+	return id, found, l.err
+}
+
+func (l staticLookup) LookupSettingIds(_ string, _ string, filter func(value []byte) bool) ([]string, error) {
+	var ids []string
+	for id, value := range l.settings {
+		if filter([]byte(value)) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, l.err
+}
+
+func TestParseConfigLookupParameter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    map[string]interface{}
+		expected *ConfigLookupParameter
+	}{
+		{
+			"classic config",
+			map[string]interface{}{"api": "management-zone", "name": "Team A"},
+			NewConfig("management-zone", "Team A"),
+		},
+		{
+			"settings with filter",
+			map[string]interface{}{
+				"schema": "builtin:alerting.profile",
+				"scope":  "environment",
+				"filter": map[interface{}]interface{}{"name": "Team A", "enabled": true},
+			},
+			NewSetting("builtin:alerting.profile", "environment", map[string]interface{}{"name": "Team A", "enabled": true}),
+		},
+		{
+			"settings with name",
+			map[string]interface{}{"schema": "builtin:alerting.profile", "name": "Team A"},
+			NewSetting("builtin:alerting.profile", "", map[string]interface{}{"name": "Team A"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param, err := parseConfigLookupParameter(parameter.ParameterParserContext{Value: tt.value})
+			assert.NilError(t, err)
+			assert.Equal(t, param.GetType(), "configLookup")
+			assert.DeepEqual(t, param, tt.expected)
+		})
+	}
+}
+
+func TestParseConfigLookupParameterErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         map[string]interface{}
+		expectedError string
+	}{
+		{
+			"neither api nor schema",
+			map[string]interface{}{"name": "Team A"},
+			"missing property `api` or `schema`",
+		},
+		{
+			"api and schema",
+			map[string]interface{}{"api": "management-zone", "schema": "builtin:alerting.profile", "name": "Team A"},
+			"only one of `api` and `schema` can be set",
+		},
+		{
+			"api without name",
+			map[string]interface{}{"api": "management-zone"},
+			"missing property `name`",
+		},
+		{
+			"api with filter",
+			map[string]interface{}{"api": "management-zone", "name": "Team A", "filter": map[interface{}]interface{}{"a": "b"}},
+			"can only be used with `schema`",
+		},
+		{
+			"schema without filter",
+			map[string]interface{}{"schema": "builtin:alerting.profile"},
+			"missing property `filter` or `name`",
+		},
+		{
+			"malformed filter",
+			map[string]interface{}{"schema": "builtin:alerting.profile", "filter": "name"},
+			"malformed property `filter`",
+		},
+		{
+			"non-scalar filter value",
+			map[string]interface{}{"schema": "builtin:alerting.profile", "filter": map[interface{}]interface{}{"rules": []interface{}{"a"}}},
+			"malformed filter value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseConfigLookupParameter(parameter.ParameterParserContext{Value: tt.value})
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestResolveValue(t *testing.T) {
+	lookup := staticLookup{
+		configIds: map[string]string{"management-zone/Team A": "1234"},
+		settings: map[string]string{
+			"object-a": `{"name": "Team A", "enabled": true, "rule": {"severity": 3}}`,
+			"object-b": `{"name": "Team B", "enabled": true, "rule": {"severity": 3}}`,
+			"object-c": `{"name": "Team C", "enabled": false}`,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		param         *ConfigLookupParameter
+		expected      string
+		expectedError string
+	}{
+		{"classic config", NewConfig("management-zone", "Team A"), "1234", ""},
+		{"missing classic config", NewConfig("management-zone", "Team B"), "", "no `management-zone` config named `Team B` exists"},
+		{"setting", NewSetting("schema", "", map[string]interface{}{"name": "Team A"}), "object-a", ""},
+		{"setting by boolean", NewSetting("schema", "", map[string]interface{}{"enabled": false}), "object-c", ""},
+		{"setting by nested number", NewSetting("schema", "", map[string]interface{}{"name": "Team B", "rule.severity": 3}), "object-b", ""},
+		{"missing setting", NewSetting("schema", "tenant", map[string]interface{}{"name": "Team D"}), "", "no `schema` settings object matches `name=Team D, scope tenant`"},
+		{"multiple settings", NewSetting("schema", "", map[string]interface{}{"enabled": true}), "", "2 `schema` settings objects match `enabled=true`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.param.ResolveValue(parameter.ResolveContext{ConfigLookup: lookup})
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, result, tt.expected)
+		})
+	}
+}
+
+func TestResolveValueFailsIfLookupFails(t *testing.T) {
+	_, err := NewConfig("management-zone", "Team A").ResolveValue(parameter.ResolveContext{ConfigLookup: staticLookup{err: errors.New("unauthorized")}})
+	assert.ErrorContains(t, err, "failed to look up `management-zone` config named `Team A`: unauthorized")
+}
+
+func TestResolveValueFailsWithoutEnvironment(t *testing.T) {
+	_, err := NewConfig("management-zone", "Team A").ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, "only be looked up while deploying")
+}
+
+func TestWriteConfigLookupParameter(t *testing.T) {
+	tests := []struct {
+		name     string
+		param    *ConfigLookupParameter
+		expected map[string]interface{}
+	}{
+		{
+			"classic config",
+			NewConfig("management-zone", "Team A"),
+			map[string]interface{}{"api": "management-zone", "name": "Team A"},
+		},
+		{
+			"setting",
+			NewSetting("builtin:alerting.profile", "environment", map[string]interface{}{"name": "Team A"}),
+			map[string]interface{}{"schema": "builtin:alerting.profile", "scope": "environment", "filter": map[string]interface{}{"name": "Team A"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := writeConfigLookupParameter(parameter.ParameterWriterContext{Parameter: tt.param})
+			assert.NilError(t, err)
+			assert.DeepEqual(t, result, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,263 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitylookup
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"strings"
+)
+
+// EntityLookupParameterType specifies the type of the parameter used in config files
+const EntityLookupParameterType = "entityLookup"
+
+var EntityLookupParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeEntityLookupParameter,
+	Deserializer: parseEntityLookupParameter,
+}
+
+// MatchMode defines how an EntityLookupParameter behaves if it does not match exactly one entity
+type MatchMode string
+
+const (
+	// MatchModeFail fails the resolution of the parameter
+	MatchModeFail MatchMode = "fail"
+	// MatchModeEmpty resolves to an empty ID. Only valid if no entity matches.
+	MatchModeEmpty MatchMode = "empty"
+	// MatchModeFirst resolves to the first matching ID. Only valid if multiple entities match.
+	MatchModeFirst MatchMode = "first"
+)
+
+// EntityLookupParameter resolves to the IDs of the Dynatrace entities matching a selector. The lookup is done
+// at deploy time in the environment the config is deployed to, so the same config can reference e.g. hosts
+// whose IDs differ between environments.
+type EntityLookupParameter struct {
+	// EntityType is the type of the entities to look up, e.g. HOST
+	EntityType string
+
+	// Name is the optional exact name of the entity to look up
+	Name string
+
+	// Tags are optional tags all looked up entities need to have, e.g. `env:prod`
+	Tags []string
+
+	// List defines whether the parameter resolves to a JSON list of all matching IDs, instead of a single ID
+	List bool
+
+	// OnNoMatch defines the behavior if no entity matches. Either MatchModeFail or MatchModeEmpty.
+	OnNoMatch MatchMode
+
+	// OnMultipleMatches defines the behavior if multiple entities match a parameter resolving to a single ID.
+	// Either MatchModeFail or MatchModeFirst.
+	OnMultipleMatches MatchMode
+}
+
+func New(entityType string, name string, tags []string) *EntityLookupParameter {
+	return &EntityLookupParameter{
+		EntityType:        entityType,
+		Name:              name,
+		Tags:              tags,
+		OnNoMatch:         MatchModeFail,
+		OnMultipleMatches: MatchModeFail,
+	}
+}
+
+// this forces the compiler to check if EntityLookupParameter is of type Parameter
+var _ parameter.Parameter = (*EntityLookupParameter)(nil)
+
+func (p *EntityLookupParameter) GetType() string {
+	return EntityLookupParameterType
+}
+
+func (p *EntityLookupParameter) GetReferences() []parameter.ParameterReference {
+	// entity lookup parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+// EntitySelector returns the entity selector used to look up the entities, e.g.
+// `type("HOST"),entityName.equals("my-host"),tag("env:prod")`
+func (p *EntityLookupParameter) EntitySelector() string {
+	selector := []string{fmt.Sprintf("type(%s)", quote(p.EntityType))}
+
+	if p.Name != "" {
+		selector = append(selector, fmt.Sprintf("entityName.equals(%s)", quote(p.Name)))
+	}
+
+	for _, tag := range p.Tags {
+		selector = append(selector, fmt.Sprintf("tag(%s)", quote(tag)))
+	}
+
+	return strings.Join(selector, ",")
+}
+
+// quote quotes the given value for entity selectors. Quotes and tildes need to be escaped with a tilde.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, "~", "~~")
+	s = strings.ReplaceAll(s, `"`, `~"`)
+	return `"` + s + `"`
+}
+
+func (p *EntityLookupParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	if context.EntityLookup == nil {
+		return nil, parameter.NewParameterResolveValueError(context, "entities can only be looked up while deploying to an environment")
+	}
+
+	selector := p.EntitySelector()
+
+	ids, err := context.EntityLookup.LookupEntityIds(selector)
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to look up entities matching `%s`: %s", selector, err))
+	}
+
+	if len(ids) == 0 && p.OnNoMatch != MatchModeEmpty {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("no entity matches `%s`", selector))
+	}
+
+	if p.List {
+		quoted := make([]string, len(ids))
+		for i, id := range ids {
+			quoted[i] = fmt.Sprintf(`"%s"`, id)
+		}
+		return fmt.Sprintf("[ %s ]", strings.Join(quoted, ",")), nil
+	}
+
+	switch {
+	case len(ids) == 0:
+		return "", nil
+	case len(ids) > 1 && p.OnMultipleMatches != MatchModeFirst:
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("%d entities match `%s`, but exactly one was expected: %s", len(ids), selector, strings.Join(ids, ", ")))
+	default:
+		return ids[0], nil
+	}
+}
+
+// parseEntityLookupParameter parses an EntityLookupParameter from a given context. it requires an `entityType`
+// field to be set. `name`, `tags`, `list`, `onNoMatch` and `onMultipleMatches` are optional.
+func parseEntityLookupParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	entityType, err := parameter.OptionalString(context, "entityType")
+	if err != nil {
+		return nil, err
+	}
+	if entityType == "" {
+		return nil, parameter.NewParameterParserError(context, "missing property `entityType`")
+	}
+
+	name, err := parameter.OptionalString(context, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := parseTags(context)
+	if err != nil {
+		return nil, err
+	}
+
+	p := New(entityType, name, tags)
+
+	if val, ok := context.Value["list"]; ok {
+		b, isBool := val.(bool)
+		if !isBool {
+			return nil, parameter.NewParameterParserError(context, fmt.Sprintf("property `list` must be a boolean, but is `%v`", val))
+		}
+		p.List = b
+	}
+
+	if p.OnNoMatch, err = parseMatchMode(context, "onNoMatch", MatchModeEmpty); err != nil {
+		return nil, err
+	}
+
+	if p.OnMultipleMatches, err = parseMatchMode(context, "onMultipleMatches", MatchModeFirst); err != nil {
+		return nil, err
+	}
+
+	if p.List && p.OnMultipleMatches != MatchModeFail {
+		return nil, parameter.NewParameterParserError(context, "property `onMultipleMatches` cannot be used together with `list`")
+	}
+
+	return p, nil
+}
+
+func parseTags(context parameter.ParameterParserContext) ([]string, error) {
+	val, ok := context.Value["tags"]
+	if !ok {
+		return nil, nil
+	}
+
+	list, isList := val.([]interface{})
+	if !isList {
+		return nil, parameter.NewParameterParserError(context, "malformed property `tags` - expected list")
+	}
+
+	tags := make([]string, len(list))
+	for i, t := range list {
+		tag, isString := t.(string)
+		if !isString || tag == "" {
+			return nil, parameter.NewParameterParserError(context, fmt.Sprintf("malformed tag `%v` at index %d - expected non-empty string", t, i))
+		}
+		tags[i] = tag
+	}
+
+	return tags, nil
+}
+
+// parseMatchMode parses the given property as MatchMode. Besides MatchModeFail, which is the default, only the
+// given alternative is allowed.
+func parseMatchMode(context parameter.ParameterParserContext, property string, alternative MatchMode) (MatchMode, error) {
+	val, ok := context.Value[property]
+	if !ok {
+		return MatchModeFail, nil
+	}
+
+	switch mode := MatchMode(fmt.Sprint(val)); mode {
+	case MatchModeFail, alternative:
+		return mode, nil
+	default:
+		return "", parameter.NewParameterParserError(context, fmt.Sprintf("invalid value `%v` for property `%s` - expected `%s` or `%s`", val, property, MatchModeFail, alternative))
+	}
+}
+
+func writeEntityLookupParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	lookupParam, ok := context.Parameter.(*EntityLookupParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `EntityLookupParameter`")
+	}
+
+	result := make(map[string]interface{})
+
+	result["entityType"] = lookupParam.EntityType
+
+	if lookupParam.Name != "" {
+		result["name"] = lookupParam.Name
+	}
+
+	if len(lookupParam.Tags) > 0 {
+		result["tags"] = lookupParam.Tags
+	}
+
+	if lookupParam.List {
+		result["list"] = true
+	}
+
+	if lookupParam.OnNoMatch != MatchModeFail {
+		result["onNoMatch"] = string(lookupParam.OnNoMatch)
+	}
+
+	if lookupParam.OnMultipleMatches != MatchModeFail {
+		result["onMultipleMatches"] = string(lookupParam.OnMultipleMatches)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,189 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package entitylookup
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"gotest.tools/assert"
+	"testing"
+)
+
+type staticLookup struct {
+	ids       []string
+	err       error
+	selectors []string
+}
+
+func (l *staticLookup) LookupEntityIds(entitySelector string) ([]string, error) {
+	l.selectors = append(l.selectors, entitySelector)
+	return l.ids, l.err
+}
+
+func TestParseEntityLookupParameter(t *testing.T) {
+	param, err := parseEntityLookupParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{
+			"entityType":        "HOST",
+			"name":              "my-host",
+			"tags":              []interface{}{"env:prod", "team"},
+			"onNoMatch":         "empty",
+			"onMultipleMatches": "first",
+		},
+	})
+	assert.NilError(t, err)
+
+	lookupParam, ok := param.(*EntityLookupParameter)
+	assert.Assert(t, ok, "parsed parameter should be entity lookup parameter")
+	assert.Equal(t, lookupParam.GetType(), "entityLookup")
+	assert.DeepEqual(t, lookupParam, &EntityLookupParameter{
+		EntityType:        "HOST",
+		Name:              "my-host",
+		Tags:              []string{"env:prod", "team"},
+		OnNoMatch:         MatchModeEmpty,
+		OnMultipleMatches: MatchModeFirst,
+	})
+}
+
+func TestParseEntityLookupParameterDefaults(t *testing.T) {
+	param, err := parseEntityLookupParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{
+			"entityType": "SERVICE",
+		},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, param, New("SERVICE", "", nil))
+}
+
+func TestParseEntityLookupParameterErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         map[string]interface{}
+		expectedError string
+	}{
+		{
+			"missing entity type",
+			map[string]interface{}{"name": "my-host"},
+			"missing property `entityType`",
+		},
+		{
+			"malformed tags",
+			map[string]interface{}{"entityType": "HOST", "tags": "env:prod"},
+			"malformed property `tags`",
+		},
+		{
+			"malformed list",
+			map[string]interface{}{"entityType": "HOST", "list": "yes"},
+			"property `list` must be a boolean",
+		},
+		{
+			"invalid onNoMatch",
+			map[string]interface{}{"entityType": "HOST", "onNoMatch": "first"},
+			"invalid value `first` for property `onNoMatch`",
+		},
+		{
+			"onMultipleMatches with list",
+			map[string]interface{}{"entityType": "HOST", "list": true, "onMultipleMatches": "first"},
+			"cannot be used together with `list`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseEntityLookupParameter(parameter.ParameterParserContext{Value: tt.value})
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestEntitySelector(t *testing.T) {
+	p := New("HOST", `my "special" host~`, []string{"env:prod", "team"})
+	assert.Equal(t, p.EntitySelector(), `type("HOST"),entityName.equals("my ~"special~" host~~"),tag("env:prod"),tag("team")`)
+}
+
+func TestResolveValue(t *testing.T) {
+	list := New("HOST", "", nil)
+	list.List = true
+
+	emptyOnNoMatch := New("HOST", "", nil)
+	emptyOnNoMatch.OnNoMatch = MatchModeEmpty
+
+	firstOnMultipleMatches := New("HOST", "", nil)
+	firstOnMultipleMatches.OnMultipleMatches = MatchModeFirst
+
+	emptyList := New("HOST", "", nil)
+	emptyList.List = true
+	emptyList.OnNoMatch = MatchModeEmpty
+
+	tests := []struct {
+		name          string
+		param         *EntityLookupParameter
+		ids           []string
+		expected      interface{}
+		expectedError string
+	}{
+		{"single match", New("HOST", "", nil), []string{"HOST-1"}, "HOST-1", ""},
+		{"no match fails", New("HOST", "", nil), []string{}, nil, "no entity matches"},
+		{"no match resolves to empty", emptyOnNoMatch, []string{}, "", ""},
+		{"multiple matches fail", New("HOST", "", nil), []string{"HOST-1", "HOST-2"}, nil, "2 entities match"},
+		{"multiple matches resolve to first", firstOnMultipleMatches, []string{"HOST-1", "HOST-2"}, "HOST-1", ""},
+		{"list", list, []string{"HOST-1", "HOST-2"}, `[ "HOST-1","HOST-2" ]`, ""},
+		{"empty list fails", list, []string{}, nil, "no entity matches"},
+		{"empty list", emptyList, []string{}, "[  ]", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookup := &staticLookup{ids: tt.ids}
+
+			result, err := tt.param.ResolveValue(parameter.ResolveContext{EntityLookup: lookup})
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, result, tt.expected)
+			assert.DeepEqual(t, lookup.selectors, []string{`type("HOST")`})
+		})
+	}
+}
+
+func TestResolveValueFailsIfLookupFails(t *testing.T) {
+	_, err := New("HOST", "", nil).ResolveValue(parameter.ResolveContext{EntityLookup: &staticLookup{err: errors.New("unauthorized")}})
+	assert.ErrorContains(t, err, "failed to look up entities matching `type(\"HOST\")`: unauthorized")
+}
+
+func TestResolveValueFailsWithoutEnvironment(t *testing.T) {
+	_, err := New("HOST", "", nil).ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, "only be looked up while deploying")
+}
+
+func TestWriteEntityLookupParameter(t *testing.T) {
+	p := New("HOST", "my-host", []string{"env:prod"})
+	p.List = true
+	p.OnNoMatch = MatchModeEmpty
+
+	result, err := writeEntityLookupParameter(parameter.ParameterWriterContext{Parameter: p})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, map[string]interface{}{
+		"entityType": "HOST",
+		"name":       "my-host",
+		"tags":       []string{"env:prod"},
+		"list":       true,
+		"onNoMatch":  "empty",
+	})
+}
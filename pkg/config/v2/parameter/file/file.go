@@ -0,0 +1,150 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/spf13/afero"
+	"path/filepath"
+)
+
+// FileParameterType specifies the type of the parameter used in config files
+const FileParameterType = "file"
+
+var FileParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeFileParameter,
+	Deserializer: parseFileParameter,
+}
+
+// FileParameter defines a parameter whose value is the content of a file. This allows to keep large text blobs,
+// like markdown or JavaScript, in separate files instead of inlining them in JSON templates or YAML values.
+type FileParameter struct {
+	// Path of the file, relative to the folder of the config file defining the parameter
+	Path string
+
+	// Escape defines whether the content is escaped to be used within a JSON string.
+	// It is set by default, and can be disabled to insert e.g. JSON snippets.
+	Escape bool
+
+	// Content of the file. It is read when the parameter is parsed.
+	Content string
+
+	// Folder is the folder Path is relative to. It is only set for parameters loaded from config files.
+	Folder string
+}
+
+func New(path string, content string, escape bool) *FileParameter {
+	return &FileParameter{
+		Path:    path,
+		Escape:  escape,
+		Content: content,
+	}
+}
+
+// this forces the compiler to check if FileParameter is of type Parameter
+var _ parameter.Parameter = (*FileParameter)(nil)
+
+func (p *FileParameter) GetType() string {
+	return FileParameterType
+}
+
+func (p *FileParameter) GetReferences() []parameter.ParameterReference {
+	// file parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *FileParameter) ResolveValue(_ parameter.ResolveContext) (interface{}, error) {
+	if !p.Escape {
+		return p.Content, nil
+	}
+
+	return template.EscapeSpecialCharactersInValue(p.Content, template.FullStringEscapeFunction)
+}
+
+// ValidatePath checks that the given path is relative and does not leave the folder it is relative to once cleaned.
+// This prevents file parameters from reading or writing files outside the config folder.
+func ValidatePath(path string) error {
+	if filepath.IsAbs(path) {
+		return fmt.Errorf("path `%s` must be relative to the config folder", filepath.ToSlash(path))
+	}
+
+	if !filepath.IsLocal(path) {
+		return fmt.Errorf("path `%s` must not point outside of the config folder", filepath.ToSlash(path))
+	}
+
+	return nil
+}
+
+// parseFileParameter parses a FileParameter from a given context and reads the referenced file.
+// it requires a `path` field to be set. `escape` is an optional field, which defaults to true.
+func parseFileParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	path, ok := context.Value["path"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `path`")
+	}
+
+	relativePath := filepath.FromSlash(strings.ToString(path))
+	if relativePath == "" {
+		return nil, parameter.NewParameterParserError(context, "property `path` must not be empty")
+	}
+
+	if err := ValidatePath(relativePath); err != nil {
+		return nil, parameter.NewParameterParserError(context, err.Error())
+	}
+
+	escape := true
+	if val, ok := context.Value["escape"]; ok {
+		b, isBool := val.(bool)
+		if !isBool {
+			return nil, parameter.NewParameterParserError(context, fmt.Sprintf("property `escape` must be a boolean, but is `%v`", val))
+		}
+		escape = b
+	}
+
+	if context.Fs == nil {
+		return nil, parameter.NewParameterParserError(context, "no filesystem to read the file from. this is most likely a bug")
+	}
+
+	content, err := afero.ReadFile(context.Fs, filepath.Join(context.Folder, relativePath))
+	if err != nil {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("failed to read file `%s`: %s", relativePath, err))
+	}
+
+	result := New(filepath.ToSlash(relativePath), string(content), escape)
+	result.Folder = context.Folder
+
+	return result, nil
+}
+
+func writeFileParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	fileParam, ok := context.Parameter.(*FileParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `FileParameter`")
+	}
+
+	result := make(map[string]interface{})
+
+	result["path"] = fileParam.Path
+
+	if !fileParam.Escape {
+		result["escape"] = false
+	}
+
+	return result, nil
+}
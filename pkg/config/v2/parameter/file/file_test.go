@@ -0,0 +1,107 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package file
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func newTestParserContext(t *testing.T, value map[string]interface{}) parameter.ParameterParserContext {
+	fs := afero.NewMemMapFs()
+	assert.NilError(t, afero.WriteFile(fs, "project/dashboard/tiles/markdown.md", []byte("# Title\n\"quoted\""), 0644))
+
+	return parameter.ParameterParserContext{
+		ParameterName: "markdown",
+		Value:         value,
+		Fs:            fs,
+		Folder:        "project/dashboard",
+	}
+}
+
+func TestParseFileParameter(t *testing.T) {
+	param, err := parseFileParameter(newTestParserContext(t, map[string]interface{}{
+		"path": "tiles/markdown.md",
+	}))
+	assert.NilError(t, err)
+
+	fileParam, ok := param.(*FileParameter)
+	assert.Assert(t, ok, "parsed parameter should be file parameter")
+	assert.Equal(t, fileParam.GetType(), "file")
+	assert.Equal(t, fileParam.Path, "tiles/markdown.md")
+	assert.Equal(t, fileParam.Content, "# Title\n\"quoted\"")
+	assert.Assert(t, fileParam.Escape, "file parameter should be escaped by default")
+}
+
+func TestParseFileParameterWithoutEscaping(t *testing.T) {
+	param, err := parseFileParameter(newTestParserContext(t, map[string]interface{}{
+		"path":   "tiles/markdown.md",
+		"escape": false,
+	}))
+	assert.NilError(t, err)
+	assert.Assert(t, !param.(*FileParameter).Escape, "file parameter should not be escaped")
+}
+
+func TestParseFileParameterErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value map[string]interface{}
+		want  string
+	}{
+		{"missing path", map[string]interface{}{}, "missing property `path`"},
+		{"empty path", map[string]interface{}{"path": ""}, "must not be empty"},
+		{"invalid escape", map[string]interface{}{"path": "tiles/markdown.md", "escape": "yes"}, "must be a boolean"},
+		{"missing file", map[string]interface{}{"path": "missing.md"}, "failed to read file `missing.md`"},
+		{"absolute path", map[string]interface{}{"path": "/project/dashboard/tiles/markdown.md"}, "must be relative to the config folder"},
+		{"path outside of folder", map[string]interface{}{"path": "../dashboard/tiles/markdown.md"}, "must not point outside of the config folder"},
+		{"cleaned path outside of folder", map[string]interface{}{"path": "tiles/../../dashboard/tiles/markdown.md"}, "must not point outside of the config folder"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFileParameter(newTestParserContext(t, tt.value))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestResolveValue(t *testing.T) {
+	escaped, err := New("file.md", "line 1\n\"line 2\"", true).ResolveValue(parameter.ResolveContext{})
+	assert.NilError(t, err)
+	assert.Equal(t, escaped, `line 1\n\"line 2\"`)
+
+	raw, err := New("file.json", `{"key": "value"}`, false).ResolveValue(parameter.ResolveContext{})
+	assert.NilError(t, err)
+	assert.Equal(t, raw, `{"key": "value"}`)
+}
+
+func TestGetReferences(t *testing.T) {
+	refs := New("file.md", "", true).GetReferences()
+	assert.Equal(t, len(refs), 0, "file parameter should not have references")
+}
+
+func TestWriteFileParameter(t *testing.T) {
+	result, err := writeFileParameter(parameter.ParameterWriterContext{Parameter: New("tiles/markdown.md", "content", true)})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, map[string]interface{}{"path": "tiles/markdown.md"})
+
+	result, err = writeFileParameter(parameter.ParameterWriterContext{Parameter: New("snippet.json", "{}", false)})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, map[string]interface{}{"path": "snippet.json", "escape": false})
+}
@@ -16,6 +16,8 @@ package parameter
 
 import (
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/secret"
+	"github.com/spf13/afero"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/errors"
@@ -66,6 +68,29 @@ type ResolveContext struct {
 
 	// resolved values of the current config
 	ResolvedParameterValues Properties
+
+	// EntityLookup finds Dynatrace entities in the environment the config is deployed to.
+	// it is not set if the config is resolved without access to an environment.
+	EntityLookup EntityLookup
+
+	// ConfigLookup finds existing configs in the environment the config is deployed to.
+	// it is not set if the config is resolved without access to an environment.
+	ConfigLookup ConfigLookup
+}
+
+// EntityLookup finds the IDs of Dynatrace entities matching an entity selector
+type EntityLookup interface {
+	LookupEntityIds(entitySelector string) ([]string, error)
+}
+
+// ConfigLookup finds the IDs of existing configs, including configs not managed by monaco
+type ConfigLookup interface {
+	// LookupConfigId returns the ID of the config of the given api with the given name
+	LookupConfigId(api string, name string) (id string, found bool, err error)
+
+	// LookupSettingIds returns the object IDs of all settings objects of the given schema whose value matches the
+	// given filter. If scope is not empty, only objects of this scope are returned.
+	LookupSettingIds(schemaId string, scope string, filter func(value []byte) bool) ([]string, error)
 }
 
 type Parameter interface {
@@ -102,6 +127,23 @@ type ParameterParserContext struct {
 	ParameterName string
 	// current value to parse
 	Value map[string]interface{}
+	// Fs is the filesystem the config is loaded from. it is used by parameters referencing files.
+	Fs afero.Fs
+	// Folder is the folder of the config file the parameter is defined in. relative paths in parameters are
+	// resolved relative to it.
+	Folder string
+	// Variables are the variables defined in the manifest for environments and environment groups
+	Variables VariableLookup
+	// SecretKey loads the key used to decrypt secret parameters. It is shared by all parameters of a load, so that
+	// the key is only loaded once.
+	SecretKey *secret.KeyLoader
+}
+
+// VariableLookup finds the variables defined in the manifest for environments and environment groups
+type VariableLookup interface {
+	// LookupVariable returns the value of the variable with the given name for the given environment. Variables of
+	// the environment take precedence over variables of its group.
+	LookupVariable(environment string, name string) (string, bool)
 }
 
 type ParameterParserError struct {
@@ -136,6 +178,22 @@ func NewParameterParserError(context ParameterParserContext, reason string) erro
 	}
 }
 
+// OptionalString returns the string value of the given property of the parameter to parse, or an empty string if the
+// property is not set. An error is returned if the property is set, but not a string.
+func OptionalString(context ParameterParserContext, property string) (string, error) {
+	val, ok := context.Value[property]
+	if !ok {
+		return "", nil
+	}
+
+	s, isString := val.(string)
+	if !isString {
+		return "", NewParameterParserError(context, fmt.Sprintf("property `%s` must be a string, but is `%v`", property, val))
+	}
+
+	return s, nil
+}
+
 type ParameterWriterError struct {
 	// config the error happened in
 	Location           coordinate.Coordinate
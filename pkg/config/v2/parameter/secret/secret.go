@@ -0,0 +1,122 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/secret"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+)
+
+// SecretParameterType specifies the type of the parameter used in config files
+const SecretParameterType = "secret"
+
+var SecretParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeSecretParameter,
+	Deserializer: parseSecretParameter,
+}
+
+// SecretParameter defines a parameter whose value is stored encrypted in the config file, see package
+// internal/secret. The value is decrypted when the parameter is resolved, and the decrypted value is redacted
+// from all logs.
+type SecretParameter struct {
+	// EncryptedValue is the value as stored in the config file
+	EncryptedValue string
+
+	// key loads the key to decrypt the value. It is only set for parameters loaded from config files.
+	key *secret.KeyLoader
+}
+
+func New(encryptedValue string) *SecretParameter {
+	return &SecretParameter{
+		EncryptedValue: encryptedValue,
+	}
+}
+
+// this forces the compiler to check if SecretParameter is of type Parameter
+var _ parameter.Parameter = (*SecretParameter)(nil)
+
+func (p *SecretParameter) GetType() string {
+	return SecretParameterType
+}
+
+func (p *SecretParameter) GetReferences() []parameter.ParameterReference {
+	// secret parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *SecretParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	if p.key == nil {
+		return nil, parameter.NewParameterResolveValueError(context, "no key to decrypt the secret. this is most likely a bug")
+	}
+
+	key, err := p.key.Load()
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to load secret key: %s", err))
+	}
+
+	decrypted, err := secret.Decrypt(key, p.EncryptedValue)
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to decrypt secret: %s", err))
+	}
+
+	escaped, err := template.EscapeSpecialCharactersInValue(string(decrypted), template.FullStringEscapeFunction)
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, "failed to escape secret")
+	}
+
+	// the escaped value is the one ending up in payloads and request logs
+	log.RegisterSecret(string(decrypted), escaped.(string))
+
+	return escaped, nil
+}
+
+// parseSecretParameter parses a SecretParameter from a given context. it requires a `value` field to be set.
+// the value is not decrypted while parsing, so that configs can be loaded without the key.
+func parseSecretParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	value, ok := context.Value["value"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `value`")
+	}
+
+	encrypted := strings.ToString(value)
+	if encrypted == "" {
+		return nil, parameter.NewParameterParserError(context, "property `value` must not be empty")
+	}
+
+	if context.SecretKey == nil {
+		return nil, parameter.NewParameterParserError(context, "no secret key loader. this is most likely a bug")
+	}
+
+	result := New(encrypted)
+	result.key = context.SecretKey
+
+	return result, nil
+}
+
+func writeSecretParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	secretParam, ok := context.Parameter.(*SecretParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `SecretParameter`")
+	}
+
+	return map[string]interface{}{
+		"value": secretParam.EncryptedValue,
+	}, nil
+}
@@ -0,0 +1,126 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package secret
+
+import (
+	"encoding/base64"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/secret"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"testing"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestSecretParameter(encryptedValue string) *SecretParameter {
+	p := New(encryptedValue)
+	p.key = secret.NewKeyLoader(afero.NewMemMapFs())
+	return p
+}
+
+func TestParseSecretParameter(t *testing.T) {
+	param, err := parseSecretParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{
+			"value": "encrypted",
+		},
+		SecretKey: secret.NewKeyLoader(afero.NewMemMapFs()),
+	})
+	assert.NilError(t, err)
+
+	secretParam, ok := param.(*SecretParameter)
+	assert.Assert(t, ok, "parsed parameter should be secret parameter")
+	assert.Equal(t, secretParam.GetType(), "secret")
+	assert.Equal(t, secretParam.EncryptedValue, "encrypted")
+}
+
+func TestParseSecretParameterMissingValue(t *testing.T) {
+	_, err := parseSecretParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{},
+	})
+	assert.ErrorContains(t, err, "missing property `value`")
+}
+
+func TestResolveValue(t *testing.T) {
+	t.Setenv(secret.EnvKeySecretKey, base64.StdEncoding.EncodeToString(testKey))
+
+	encrypted, err := secret.Encrypt(testKey, []byte("line1\n\"token\""))
+	assert.NilError(t, err)
+
+	resolved, err := newTestSecretParameter(encrypted).ResolveValue(parameter.ResolveContext{})
+	assert.NilError(t, err)
+	assert.Equal(t, resolved, `line1\n\"token\"`)
+
+	assert.Equal(t, log.Redact(`{"token": "line1\n\"token\""}`), `{"token": "***"}`)
+}
+
+func TestResolveValueFailsWithoutKey(t *testing.T) {
+	t.Setenv(secret.EnvKeySecretKey, "")
+
+	_, err := newTestSecretParameter("encrypted").ResolveValue(parameter.ResolveContext{ParameterName: "token"})
+	assert.ErrorContains(t, err, "failed to load secret key")
+}
+
+func TestResolveValueFailsWithWrongKey(t *testing.T) {
+	t.Setenv(secret.EnvKeySecretKey, base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210")))
+
+	encrypted, err := secret.Encrypt(testKey, []byte("token"))
+	assert.NilError(t, err)
+
+	_, err = newTestSecretParameter(encrypted).ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, "failed to decrypt secret")
+}
+
+func TestResolveValueReadsKeyFileFromFilesystemOnce(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NilError(t, afero.WriteFile(fs, "key", []byte(base64.StdEncoding.EncodeToString(testKey)), 0600))
+	t.Setenv(secret.EnvKeySecretKeyFile, "key")
+
+	encrypted, err := secret.Encrypt(testKey, []byte("token"))
+	assert.NilError(t, err)
+
+	keyLoader := secret.NewKeyLoader(fs)
+	first, err := parseSecretParameter(parameter.ParameterParserContext{Value: map[string]interface{}{"value": encrypted}, SecretKey: keyLoader})
+	assert.NilError(t, err)
+	second, err := parseSecretParameter(parameter.ParameterParserContext{Value: map[string]interface{}{"value": encrypted}, SecretKey: keyLoader})
+	assert.NilError(t, err)
+
+	resolved, err := first.ResolveValue(parameter.ResolveContext{})
+	assert.NilError(t, err)
+	assert.Equal(t, resolved, "token")
+
+	// the key is not read again, so removing the file does not affect further resolutions
+	assert.NilError(t, fs.Remove("key"))
+
+	resolved, err = second.ResolveValue(parameter.ResolveContext{})
+	assert.NilError(t, err)
+	assert.Equal(t, resolved, "token")
+}
+
+func TestParseSecretParameterWithoutKeyLoader(t *testing.T) {
+	_, err := parseSecretParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{"value": "encrypted"},
+	})
+	assert.ErrorContains(t, err, "no secret key loader")
+}
+
+func TestWriteSecretParameter(t *testing.T) {
+	result, err := writeSecretParameter(parameter.ParameterWriterContext{Parameter: New("encrypted")})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, map[string]interface{}{"value": "encrypted"})
+}
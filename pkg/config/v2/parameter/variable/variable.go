@@ -0,0 +1,100 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+)
+
+// VariableParameterType specifies the type of the parameter used in config files
+const VariableParameterType = "variable"
+
+var VariableParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeVariableParameter,
+	Deserializer: parseVariableParameter,
+}
+
+// VariableParameter resolves to a variable defined in the manifest for the environment the config is deployed to,
+// or for its environment group. This allows to define per-environment values, like a tenant short-name, once in
+// the manifest instead of overriding them in every config.
+type VariableParameter struct {
+	// Name of the variable
+	Name string
+
+	// variables are the variables defined in the manifest
+	variables parameter.VariableLookup
+}
+
+func New(name string, variables parameter.VariableLookup) *VariableParameter {
+	return &VariableParameter{
+		Name:      name,
+		variables: variables,
+	}
+}
+
+// this forces the compiler to check if VariableParameter is of type Parameter
+var _ parameter.Parameter = (*VariableParameter)(nil)
+
+func (p *VariableParameter) GetType() string {
+	return VariableParameterType
+}
+
+func (p *VariableParameter) GetReferences() []parameter.ParameterReference {
+	// variable parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *VariableParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	if p.variables == nil {
+		return nil, parameter.NewParameterResolveValueError(context, "no manifest variables available. this is most likely a bug")
+	}
+
+	value, found := p.variables.LookupVariable(context.Environment, p.Name)
+	if !found {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("variable `%s` is neither defined for environment `%s` nor for its group `%s`", p.Name, context.Environment, context.Group))
+	}
+
+	return template.EscapeSpecialCharactersInValue(value, template.FullStringEscapeFunction)
+}
+
+// parseVariableParameter parses a VariableParameter from a given context. it requires a `name` field to be set.
+func parseVariableParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	name, ok := context.Value["name"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `name`")
+	}
+
+	variableName := strings.ToString(name)
+	if variableName == "" {
+		return nil, parameter.NewParameterParserError(context, "property `name` must not be empty")
+	}
+
+	return New(variableName, context.Variables), nil
+}
+
+func writeVariableParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	variableParam, ok := context.Parameter.(*VariableParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `VariableParameter`")
+	}
+
+	return map[string]interface{}{
+		"name": variableParam.Name,
+	}, nil
+}
@@ -0,0 +1,79 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package variable
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"gotest.tools/assert"
+	"testing"
+)
+
+type staticVariables map[string]map[string]string
+
+func (v staticVariables) LookupVariable(environment string, name string) (string, bool) {
+	value, found := v[environment][name]
+	return value, found
+}
+
+var testVariables = staticVariables{
+	"prod": {"tenant": "prod", "message": "line1\n\"line2\""},
+}
+
+func TestParseVariableParameter(t *testing.T) {
+	param, err := parseVariableParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{
+			"name": "tenant",
+		},
+		Variables: testVariables,
+	})
+	assert.NilError(t, err)
+
+	variableParam, ok := param.(*VariableParameter)
+	assert.Assert(t, ok, "parsed parameter should be variable parameter")
+	assert.Equal(t, variableParam.GetType(), "variable")
+	assert.Equal(t, variableParam.Name, "tenant")
+}
+
+func TestParseVariableParameterMissingName(t *testing.T) {
+	_, err := parseVariableParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{},
+	})
+	assert.ErrorContains(t, err, "missing property `name`")
+}
+
+func TestResolveValue(t *testing.T) {
+	result, err := New("tenant", testVariables).ResolveValue(parameter.ResolveContext{Environment: "prod", Group: "production"})
+	assert.NilError(t, err)
+	assert.Equal(t, result, "prod")
+}
+
+func TestResolveValueEscapesValue(t *testing.T) {
+	result, err := New("message", testVariables).ResolveValue(parameter.ResolveContext{Environment: "prod", Group: "production"})
+	assert.NilError(t, err)
+	assert.Equal(t, result, `line1\n\"line2\"`)
+}
+
+func TestResolveValueFailsOnUndefinedVariable(t *testing.T) {
+	_, err := New("tenant", testVariables).ResolveValue(parameter.ResolveContext{Environment: "dev", Group: "development"})
+	assert.ErrorContains(t, err, "variable `tenant` is neither defined for environment `dev` nor for its group `development`")
+}
+
+func TestWriteVariableParameter(t *testing.T) {
+	result, err := writeVariableParameter(parameter.ParameterWriterContext{Parameter: New("tenant", testVariables)})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, map[string]interface{}{"name": "tenant"})
+}
@@ -21,6 +21,7 @@ package v2
 import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/compound"
 	envParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/environment"
+	fileParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/file"
 	listParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/list"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
 	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
@@ -60,4 +61,5 @@ func TestParametersAreLoadedAsExpected(t *testing.T) {
 	assert.Equal(t, cfg.Parameters["compound_value"].GetType(), compound.CompoundParameterType)
 	assert.Equal(t, cfg.Parameters["empty_compound"].GetType(), compound.CompoundParameterType)
 	assert.Equal(t, cfg.Parameters["compound_on_compound"].GetType(), compound.CompoundParameterType)
+	assert.Equal(t, cfg.Parameters["file_value"].GetType(), fileParam.FileParameterType)
 }
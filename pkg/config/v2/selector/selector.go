@@ -0,0 +1,212 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package selector allows to select a subset of configs to work with, e.g. by their API, schema or coordinate.
+package selector
+
+import (
+	"errors"
+	"fmt"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"path"
+	"strings"
+)
+
+// Selector matches configs. A selector is either a glob matched against the coordinate of a config
+// (`project:type:configId`, e.g. `my-project:alerting-profile:*`), or a glob matched against a single property of
+// the config, using the form `<property>=<glob>`. Supported properties are `project`, `api`, `schema` and `configId`.
+// Globs use the syntax of path.Match.
+type Selector struct {
+	// raw is the selector as given by the user
+	raw      string
+	property string
+	pattern  string
+}
+
+const (
+	propertyCoordinate = "coordinate"
+	propertyProject    = "project"
+	propertyApi        = "api"
+	propertySchema     = "schema"
+	propertyConfigId   = "configId"
+)
+
+var properties = []string{propertyProject, propertyApi, propertySchema, propertyConfigId}
+
+// Parse parses a single selector
+func Parse(s string) (Selector, error) {
+	property, pattern := propertyCoordinate, s
+
+	if key, value, found := strings.Cut(s, "="); found {
+		if !isKnownProperty(key) {
+			return Selector{}, fmt.Errorf("invalid selector %q: unknown property %q, expected one of %s", s, key, strings.Join(properties, ", "))
+		}
+		property, pattern = key, value
+	}
+
+	if pattern == "" {
+		return Selector{}, fmt.Errorf("invalid selector %q: pattern must not be empty", s)
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return Selector{}, fmt.Errorf("invalid selector %q: %w", s, err)
+	}
+
+	return Selector{raw: s, property: property, pattern: pattern}, nil
+}
+
+// ParseAll parses all given selectors
+func ParseAll(selectors []string) ([]Selector, error) {
+	result := make([]Selector, 0, len(selectors))
+	var errs []error
+
+	for _, s := range selectors {
+		sel, err := Parse(s)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result = append(result, sel)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return result, nil
+}
+
+func isKnownProperty(p string) bool {
+	for _, known := range properties {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Selector) String() string {
+	return s.raw
+}
+
+// Matches returns whether the given config is matched by the selector
+func (s Selector) Matches(c config.Config) bool {
+	switch s.property {
+	case propertyProject:
+		return s.match(c.Coordinate.Project)
+	case propertyApi:
+		return c.Type.Api != "" && s.match(c.Type.Api)
+	case propertySchema:
+		return c.Type.SchemaId != "" && s.match(c.Type.SchemaId)
+	case propertyConfigId:
+		return s.match(c.Coordinate.ConfigId)
+	default:
+		return s.match(c.Coordinate.String())
+	}
+}
+
+func (s Selector) match(value string) bool {
+	matched, _ := path.Match(s.pattern, value) // the pattern is validated by Parse
+	return matched
+}
+
+// matchesAny returns the first of the given selectors matching the config
+func matchesAny(selectors []Selector, c config.Config) (Selector, bool) {
+	for _, s := range selectors {
+		if s.Matches(c) {
+			return s, true
+		}
+	}
+	return Selector{}, false
+}
+
+// ExcludedDependencyError is returned by Select if a selected config depends on an excluded config
+type ExcludedDependencyError struct {
+	Config     coordinate.Coordinate
+	Dependency coordinate.Coordinate
+	Selector   Selector
+}
+
+func (e ExcludedDependencyError) Error() string {
+	return fmt.Sprintf("config %s depends on %s, which is excluded by selector %q", e.Config, e.Dependency, e.Selector)
+}
+
+// Select returns the configs matched by any of the only selectors, but none of the exclude selectors. If no only
+// selectors are given, all configs not excluded are selected. All configs the selected configs depend on are
+// selected as well. If any of these dependencies is excluded, an ExcludedDependencyError is returned for it.
+//
+// The order of the given configs is kept, so that sorted configs stay sorted.
+func Select(configs []config.Config, only []Selector, exclude []Selector) ([]config.Config, []error) {
+	if len(only) == 0 && len(exclude) == 0 {
+		return configs, nil
+	}
+
+	byCoordinate := make(map[coordinate.Coordinate]config.Config, len(configs))
+	for _, c := range configs {
+		byCoordinate[c.Coordinate] = c
+	}
+
+	selected := make(map[coordinate.Coordinate]struct{})
+	var toCheck []config.Config
+
+	for _, c := range configs {
+		if len(only) > 0 {
+			if _, found := matchesAny(only, c); !found {
+				continue
+			}
+		}
+		if _, excluded := matchesAny(exclude, c); excluded {
+			continue
+		}
+		selected[c.Coordinate] = struct{}{}
+		toCheck = append(toCheck, c)
+	}
+
+	var errs []error
+	for len(toCheck) > 0 {
+		current := toCheck[0]
+		toCheck = toCheck[1:]
+
+		for _, ref := range current.Dependencies() {
+			dependency, found := byCoordinate[ref]
+			if !found {
+				// references to configs not in the given configs are reported during sorting
+				continue
+			}
+			if _, alreadySelected := selected[ref]; alreadySelected {
+				continue
+			}
+			if sel, excluded := matchesAny(exclude, dependency); excluded {
+				errs = append(errs, ExcludedDependencyError{Config: current.Coordinate, Dependency: ref, Selector: sel})
+				continue
+			}
+			selected[ref] = struct{}{}
+			toCheck = append(toCheck, dependency)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	result := make([]config.Config, 0, len(selected))
+	for _, c := range configs {
+		if _, found := selected[c.Coordinate]; found {
+			result = append(result, c)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,165 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newConfig(project, api, configId string, references ...coordinate.Coordinate) config.Config {
+	c := config.Config{
+		Coordinate: coordinate.Coordinate{Project: project, Type: api, ConfigId: configId},
+		Type:       config.Type{Api: api},
+		Parameters: config.Parameters{},
+	}
+	for i, ref := range references {
+		c.Parameters[string(rune('a'+i))] = reference.NewWithCoordinate(ref, "id")
+	}
+	return c
+}
+
+func newSetting(project, schema, configId string) config.Config {
+	return config.Config{
+		Coordinate: coordinate.Coordinate{Project: project, Type: schema, ConfigId: configId},
+		Type:       config.Type{SchemaId: schema, SchemaVersion: "1.0"},
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		selector string
+		wantErr  bool
+	}{
+		{"project:alerting-profile:*", false},
+		{"*", false},
+		{"api=alerting-profile", false},
+		{"schema=builtin:alerting.*", false},
+		{"configId=my-*", false},
+		{"project=infra", false},
+		{"unknown=value", true},
+		{"api=", true},
+		{"", true},
+		{"project:[", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			_, err := Parse(tt.selector)
+			assert.Equal(t, tt.wantErr, err != nil, "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestParseAllReportsAllErrors(t *testing.T) {
+	_, err := ParseAll([]string{"api=ok", "foo=bar", "project:["})
+	assert.ErrorContains(t, err, `"foo=bar"`)
+	assert.ErrorContains(t, err, `"project:["`)
+}
+
+func TestMatches(t *testing.T) {
+	profile := newConfig("infra", "alerting-profile", "profile")
+	setting := newSetting("infra", "builtin:alerting.profile", "setting")
+
+	tests := []struct {
+		selector string
+		config   config.Config
+		want     bool
+	}{
+		{"infra:alerting-profile:profile", profile, true},
+		{"infra:alerting-profile:*", profile, true},
+		{"*:dashboard:*", profile, false},
+		{"infra:builtin:alerting.profile:*", setting, true},
+		{"api=alerting-*", profile, true},
+		{"api=alerting-*", setting, false},
+		{"schema=builtin:alerting.*", setting, true},
+		{"schema=*", profile, false},
+		{"configId=prof*", profile, true},
+		{"project=other", profile, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector+" "+tt.config.Coordinate.String(), func(t *testing.T) {
+			s, err := Parse(tt.selector)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, s.Matches(tt.config))
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	zone := newConfig("infra", "management-zone", "zone")
+	profile := newConfig("infra", "alerting-profile", "profile", zone.Coordinate)
+	notification := newConfig("infra", "notification", "notification", profile.Coordinate)
+	dashboard := newConfig("infra", "dashboard", "dashboard")
+	configs := []config.Config{zone, profile, notification, dashboard}
+
+	tests := []struct {
+		name    string
+		only    []string
+		exclude []string
+		want    []config.Config
+		wantErr bool
+	}{
+		{
+			name: "selects all configs without selectors",
+			want: configs,
+		},
+		{
+			name: "selects only matching configs",
+			only: []string{"api=dashboard"},
+			want: []config.Config{dashboard},
+		},
+		{
+			name: "includes dependencies of selected configs in sorted order",
+			only: []string{"*:notification:*"},
+			want: []config.Config{zone, profile, notification},
+		},
+		{
+			name:    "excludes matching configs",
+			exclude: []string{"api=dashboard", "configId=notification"},
+			want:    []config.Config{zone, profile},
+		},
+		{
+			name:    "fails if a dependency is excluded",
+			only:    []string{"api=alerting-profile"},
+			exclude: []string{"api=management-zone"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			only, err := ParseAll(tt.only)
+			assert.NoError(t, err)
+			exclude, err := ParseAll(tt.exclude)
+			assert.NoError(t, err)
+
+			got, errs := Select(configs, only, exclude)
+			if tt.wantErr {
+				assert.Len(t, errs, 1)
+				assert.IsType(t, ExcludedDependencyError{}, errs[0])
+				return
+			}
+			assert.Empty(t, errs)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"sort"
+	"text/template/parse"
+)
+
+// ReferencedFields returns the names of all parameters referenced by the given template, e.g. `name` for
+// `{{ .name }}`, `{{ .name.sub }}`, `{{ $.name }}` or `{{ index . "name" }}`. Fields accessed within `range` and
+// `with` blocks are ignored, as they do not refer to parameters.
+func ReferencedFields(template Template) ([]string, error) {
+	parsed, err := ParseTemplate(template.Id(), template.Content())
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]struct{})
+	for _, t := range parsed.Templates() {
+		if t.Tree != nil {
+			collectFields(t.Tree.Root, true, fields)
+		}
+	}
+
+	result := make([]string, 0, len(fields))
+	for f := range fields {
+		result = append(result, f)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// collectFields adds the fields referenced by the given node to fields. Fields accessed via dot are only collected if
+// dot refers to the parameters, which is given by isRoot.
+func collectFields(node parse.Node, isRoot bool, fields map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectFields(child, isRoot, fields)
+		}
+	case *parse.ActionNode:
+		collectFields(n.Pipe, isRoot, fields)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectFields(cmd, isRoot, fields)
+		}
+	case *parse.CommandNode:
+		if isRoot && isIndexOfDot(n) {
+			fields[n.Args[2].(*parse.StringNode).Text] = struct{}{}
+		}
+		for _, arg := range n.Args {
+			collectFields(arg, isRoot, fields)
+		}
+	case *parse.FieldNode:
+		if isRoot && len(n.Ident) > 0 {
+			fields[n.Ident[0]] = struct{}{}
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			fields[n.Ident[1]] = struct{}{}
+		}
+	case *parse.ChainNode:
+		collectFields(n.Node, isRoot, fields)
+	case *parse.IfNode:
+		collectFields(n.Pipe, isRoot, fields)
+		collectFields(n.List, isRoot, fields)
+		collectFields(n.ElseList, isRoot, fields)
+	case *parse.RangeNode:
+		collectFields(n.Pipe, isRoot, fields)
+		collectFields(n.List, false, fields)
+		collectFields(n.ElseList, isRoot, fields)
+	case *parse.WithNode:
+		collectFields(n.Pipe, isRoot, fields)
+		collectFields(n.List, false, fields)
+		collectFields(n.ElseList, isRoot, fields)
+	case *parse.TemplateNode:
+		collectFields(n.Pipe, isRoot, fields)
+	}
+}
+
+// isIndexOfDot returns whether the given command is `index . "name"`
+func isIndexOfDot(n *parse.CommandNode) bool {
+	if len(n.Args) < 3 {
+		return false
+	}
+
+	identifier, ok := n.Args[0].(*parse.IdentifierNode)
+	if !ok || identifier.Ident != "index" {
+		return false
+	}
+
+	if _, ok := n.Args[1].(*parse.DotNode); !ok {
+		return false
+	}
+
+	_, ok = n.Args[2].(*parse.StringNode)
+	return ok
+}
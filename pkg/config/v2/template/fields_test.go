@@ -0,0 +1,69 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestReferencedFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			"plain fields",
+			`{"name": "{{ .name }}", "zone": "{{ .managementZoneId }}", "again": "{{ .name }}"}`,
+			[]string{"managementZoneId", "name"},
+		},
+		{
+			"nested fields, root variables and index",
+			`{"a": "{{ .a.nested }}", "b": "{{ $.b }}", "c": "{{ index . "c" }}"}`,
+			[]string{"a", "b", "c"},
+		},
+		{
+			"fields in function calls and conditions",
+			`{"a": "{{ upper .a }}", "b": {{ if .b }}"{{ default "x" .c }}"{{ else }}"{{ .d }}"{{ end }}}`,
+			[]string{"a", "b", "c", "d"},
+		},
+		{
+			"fields within range and with do not refer to parameters",
+			`[{{ range .items }}"{{ .value }}-{{ $.e }}"{{ end }}]{{ with .f }}{{ .ignored }}{{ else }}{{ .g }}{{ end }}`,
+			[]string{"e", "f", "g", "items"},
+		},
+		{
+			"no fields",
+			`{"static": true}`,
+			[]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReferencedFields(NewDownloadTemplate("id", "name", tt.content))
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestReferencedFields_FailsForInvalidTemplate(t *testing.T) {
+	_, err := ReferencedFields(NewDownloadTemplate("id", "name", `{{ .name `))
+	assert.ErrorContains(t, err, "unclosed action")
+}
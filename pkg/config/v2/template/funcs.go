@@ -0,0 +1,310 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
+)
+
+// Context describes the config a template is rendered for. It is used by template functions depending on the
+// environment or group the config is deployed to.
+type Context struct {
+	Environment string
+	Group       string
+}
+
+// Funcs returns the functions available in templates rendered in the given context.
+//
+// Parameter values are escaped to be placed inside JSON strings. String functions therefore unescape their inputs
+// and escape their results, so that their output is always safe to be placed inside JSON strings. toJson returns
+// complete JSON values, which are placed outside of JSON strings. indent is the only function returning its input
+// unescaped, and must only be applied to values which already are valid JSON, e.g. unescaped file parameters.
+func Funcs(context Context) templ.FuncMap {
+	return templ.FuncMap{
+		"toJson":     toJson,
+		"upper":      stringFunc(strings.ToUpper),
+		"lower":      stringFunc(strings.ToLower),
+		"trim":       stringFunc(strings.TrimSpace),
+		"trimPrefix": func(prefix string, s interface{}) string { return mapString(s, trimPrefix(prefix)) },
+		"trimSuffix": func(suffix string, s interface{}) string { return mapString(s, trimSuffix(suffix)) },
+		"replace":    func(old, replacement string, s interface{}) string { return mapString(s, replace(old, replacement)) },
+		"join":       join,
+		"default":    defaultValue,
+		"add":        arithmetic(func(a, b int64) (int64, error) { return a + b, nil }, func(a, b float64) float64 { return a + b }),
+		"sub":        arithmetic(func(a, b int64) (int64, error) { return a - b, nil }, func(a, b float64) float64 { return a - b }),
+		"mul":        arithmetic(func(a, b int64) (int64, error) { return a * b, nil }, func(a, b float64) float64 { return a * b }),
+		"div":        arithmetic(divInt, func(a, b float64) float64 { return a / b }),
+		"mod":        arithmetic(modInt, math.Mod),
+		"indent":     indent,
+		"environment": func() string {
+			return escape(context.Environment)
+		},
+		"group": func() string {
+			return escape(context.Group)
+		},
+		"isEnvironment": func(names ...string) bool {
+			return contains(names, context.Environment)
+		},
+		"isGroup": func(names ...string) bool {
+			return contains(names, context.Group)
+		},
+	}
+}
+
+// unescape returns the raw content of a string escaped to be placed inside a JSON string, as parameter values are.
+// Strings which are not validly escaped are returned as they are.
+func unescape(s string) string {
+	var raw string
+	if err := json.Unmarshal([]byte(`"`+s+`"`), &raw); err != nil {
+		return s
+	}
+	return raw
+}
+
+// escape escapes the given raw string to be placed inside a JSON string
+func escape(s string) string {
+	b, _ := json.Marshal(s) // marshalling a string never fails
+	return string(b[1 : len(b)-1])
+}
+
+// toString returns the raw string content of the given template value
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return unescape(s)
+	default:
+		return fmt.Sprint(s)
+	}
+}
+
+func mapString(v interface{}, f func(string) string) string {
+	return escape(f(toString(v)))
+}
+
+func stringFunc(f func(string) string) func(interface{}) string {
+	return func(v interface{}) string {
+		return mapString(v, f)
+	}
+}
+
+func trimPrefix(prefix string) func(string) string {
+	return func(s string) string { return strings.TrimPrefix(s, prefix) }
+}
+
+func trimSuffix(suffix string) func(string) string {
+	return func(s string) string { return strings.TrimSuffix(s, suffix) }
+}
+
+func replace(old, replacement string) func(string) string {
+	return func(s string) string { return strings.ReplaceAll(s, old, replacement) }
+}
+
+// toJson returns the JSON encoding of the given value. Strings are unescaped before encoding, so that e.g.
+// `{{ toJson .name }}` results in the same JSON string as `"{{ .name }}"`.
+func toJson(v interface{}) (string, error) {
+	b, err := json.Marshal(unescapeValue(v))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode value as JSON: %w", err)
+	}
+	return string(b), nil
+}
+
+// unescapeValue unescapes all strings of the given value, walking recursively into maps and lists
+func unescapeValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		return unescape(value)
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, e := range value {
+			result[i] = unescapeValue(e)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, e := range value {
+			result[k] = unescapeValue(e)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, e := range value {
+			result[fmt.Sprint(k)] = unescapeValue(e)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// join joins the elements of the given list with the given separator. Besides lists, the string form of list
+// parameters, e.g. `[ "a","b" ]`, is supported.
+func join(separator string, list interface{}) (string, error) {
+	if s, isString := list.(string); isString {
+		var parsed []interface{}
+		if err := json.Unmarshal([]byte(s), &parsed); err != nil {
+			return "", fmt.Errorf("cannot join `%s`: not a list", s)
+		}
+		list = parsed
+	}
+
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return "", fmt.Errorf("cannot join value of type %T: not a list", list)
+	}
+
+	elements := make([]string, value.Len())
+	for i := range elements {
+		elements[i] = toString(value.Index(i).Interface())
+	}
+
+	return escape(strings.Join(elements, separator)), nil
+}
+
+// defaultValue returns the given value, or the default if the value is empty
+func defaultValue(def interface{}, value interface{}) interface{} {
+	if isEmpty(value) {
+		value = def
+	}
+
+	if s, isString := value.(string); isString {
+		return escape(unescape(s))
+	}
+	return value
+}
+
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	default:
+		return false
+	}
+}
+
+var errDivisionByZero = errors.New("division by zero")
+
+func divInt(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, errDivisionByZero
+	}
+	return a / b, nil
+}
+
+func modInt(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, errDivisionByZero
+	}
+	return a % b, nil
+}
+
+// arithmetic returns a function applying the given operations to two numbers. Integers are only calculated as
+// floating point numbers if any of the operands is not an integer. Numeric strings are supported, as parameter
+// values are often strings.
+func arithmetic(intOp func(a, b int64) (int64, error), floatOp func(a, b float64) float64) func(a, b interface{}) (interface{}, error) {
+	return func(a, b interface{}) (interface{}, error) {
+		intA, aIsInt := toInt(a)
+		intB, bIsInt := toInt(b)
+		if aIsInt && bIsInt {
+			return intOp(intA, intB)
+		}
+
+		floatA, err := toFloat(a)
+		if err != nil {
+			return nil, err
+		}
+		floatB, err := toFloat(b)
+		if err != nil {
+			return nil, err
+		}
+
+		result := floatOp(floatA, floatB)
+		if math.IsInf(result, 0) || math.IsNaN(result) {
+			return nil, fmt.Errorf("result of %v and %v is not a number", a, b)
+		}
+		return result, nil
+	}
+}
+
+func toInt(v interface{}) (int64, bool) {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return int64(value.Uint()), true
+	case reflect.String:
+		i, err := strconv.ParseInt(strings.TrimSpace(value.String()), 10, 64)
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toFloat(v interface{}) (float64, error) {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	case reflect.String:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value.String()), 64)
+		if err != nil {
+			return 0, fmt.Errorf("`%s` is not a number", value.String())
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("value of type %T is not a number", v)
+	}
+}
+
+// indent indents all lines but the first of the given string by the given number of spaces. This allows to place
+// multi-line JSON snippets, e.g. of file parameters, at an indented position. Escaped strings contain no line
+// breaks, so they are not modified.
+//
+// Unlike the other string functions, indent neither unescapes its input nor escapes its result, as this would turn
+// JSON snippets into strings. It must therefore only be applied to values which already are valid JSON at the
+// position they are placed, e.g. file parameters with `escape: false`, or values returned by toJson.
+func indent(spaces int, s string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+strings.Repeat(" ", spaces))
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"encoding/json"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestRenderWithFunctions(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		properties map[string]interface{}
+		want       string
+	}{
+		{"toJson string", `{{ toJson .v }}`, map[string]interface{}{"v": `a \"quoted\"\nvalue`}, `"a \"quoted\"\nvalue"`},
+		{"toJson map", `{{ toJson .v }}`, map[string]interface{}{"v": map[string]interface{}{"a": []interface{}{1, "b\\n"}}}, `{"a":[1,"b\n"]}`},
+		{"toJson bool", `{{ toJson .v }}`, map[string]interface{}{"v": true}, `true`},
+		{"upper", `{{ upper .v }}`, map[string]interface{}{"v": `a\nbé`}, `A\nBÉ`},
+		{"lower", `{{ .v | lower }}`, map[string]interface{}{"v": "ABC"}, `abc`},
+		{"trim", `{{ trim .v }}`, map[string]interface{}{"v": "  a b  "}, `a b`},
+		{"trimPrefix", `{{ .v | trimPrefix "team-" }}`, map[string]interface{}{"v": "team-a"}, `a`},
+		{"trimSuffix", `{{ .v | trimSuffix "-prod" }}`, map[string]interface{}{"v": "a-prod"}, `a`},
+		{"replace", `{{ .v | replace "-" "\"" }}`, map[string]interface{}{"v": "a-b"}, `a\"b`},
+		{"join list", `{{ join ", " .v }}`, map[string]interface{}{"v": []interface{}{"a", 1}}, `a, 1`},
+		{"join list parameter", `{{ join "," .v }}`, map[string]interface{}{"v": `[ "a","b" ]`}, `a,b`},
+		{"default on empty", `{{ .v | default "x\"y" }}`, map[string]interface{}{"v": ""}, `x\"y`},
+		{"default on value", `{{ .v | default "x" }}`, map[string]interface{}{"v": "value"}, `value`},
+		{"add", `{{ add .v 2 }}`, map[string]interface{}{"v": 40}, `42`},
+		{"add numeric string", `{{ add .v 2 }}`, map[string]interface{}{"v": "40"}, `42`},
+		{"sub", `{{ sub 50 .v }}`, map[string]interface{}{"v": 8}, `42`},
+		{"mul float", `{{ mul .v 2 }}`, map[string]interface{}{"v": 1.25}, `2.5`},
+		{"div", `{{ div .v 2 }}`, map[string]interface{}{"v": 85}, `42`},
+		{"mod", `{{ mod .v 5 }}`, map[string]interface{}{"v": 12}, `2`},
+		{"indent", `{{ indent 2 .v }}`, map[string]interface{}{"v": "{\n\"a\": 1\n}"}, "{\n  \"a\": 1\n  }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(CreateTemplateFromString("test", tt.template), tt.properties, Context{})
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestRenderWithFunctionsProducesValidJson(t *testing.T) {
+	properties := map[string]interface{}{
+		"name": `a "quoted" name\\with\nnewline`,
+		"tags": map[string]interface{}{"team": `a\tb`},
+	}
+	templ := `{"name": "{{ upper .name }}", "id": "{{ .name | replace "a" "\\" }}", "tags": {{ toJson .tags }}}`
+
+	got, err := Render(CreateTemplateFromString("test", templ), properties, Context{})
+	assert.NilError(t, err)
+	assert.Assert(t, json.Valid([]byte(got)), "rendered template is not valid JSON: %s", got)
+}
+
+func TestRenderWithEnvironmentFunctions(t *testing.T) {
+	templ := `{{ if isEnvironment "prod" "staging" }}{{ environment }}{{ else }}other{{ end }}-{{ if isGroup "production" }}{{ group }}{{ end }}`
+
+	got, err := Render(CreateTemplateFromString("test", templ), nil, Context{Environment: "prod", Group: "production"})
+	assert.NilError(t, err)
+	assert.Equal(t, got, "prod-production")
+
+	got, err = Render(CreateTemplateFromString("test", templ), nil, Context{Environment: "dev", Group: "development"})
+	assert.NilError(t, err)
+	assert.Equal(t, got, "other-")
+}
+
+func TestRenderFailsOnInvalidArithmetic(t *testing.T) {
+	_, err := Render(CreateTemplateFromString("test", `{{ div 1 0 }}`), nil, Context{})
+	assert.ErrorContains(t, err, "division by zero")
+
+	_, err = Render(CreateTemplateFromString("test", `{{ add "a" 1 }}`), nil, Context{})
+	assert.ErrorContains(t, err, "`a` is not a number")
+}
+
+func TestParseTemplateFailsOnUnknownFunction(t *testing.T) {
+	_, err := ParseTemplate("test", `{"name": "{{ camelCase .name }}"}`)
+	assert.ErrorContains(t, err, `function "camelCase" not defined`)
+}
@@ -21,9 +21,10 @@ import (
 )
 
 // Render tries to render a given template with the given properties and returns the
-// resulting string. if any error occurs during rendering, an error is returned.
-func Render(template Template, properties map[string]interface{}) (string, error) {
-	parsedTemplate, err := ParseTemplate(template.Id(), template.Content())
+// resulting string. the context is used by template functions, see Funcs.
+// if any error occurs during rendering, an error is returned.
+func Render(template Template, properties map[string]interface{}, context Context) (string, error) {
+	parsedTemplate, err := parseTemplate(template.Id(), template.Content(), context)
 
 	if err != nil {
 		return "", fmt.Errorf("failure trying to render template %s: %w", template.Name(), err)
@@ -40,7 +41,12 @@ func Render(template Template, properties map[string]interface{}) (string, error
 }
 
 // ParseTemplate creates go Template with the given id from the given string content
-// in any error occurs creating the template, an erro is returned
+// in any error occurs creating the template, an erro is returned.
+// the template functions are not bound to a context. use Funcs to rebind them before executing the template.
 func ParseTemplate(id, content string) (*templ.Template, error) {
-	return templ.New(id).Option("missingkey=error").Parse(content)
+	return parseTemplate(id, content, Context{})
+}
+
+func parseTemplate(id, content string, context Context) (*templ.Template, error) {
+	return templ.New(id).Option("missingkey=error").Funcs(Funcs(context)).Parse(content)
 }
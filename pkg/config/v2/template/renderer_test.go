@@ -19,7 +19,6 @@
 package template
 
 import (
-	"reflect"
 	"testing"
 	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
 )
@@ -83,8 +82,15 @@ func TestParseTemplate(t *testing.T) {
 				t.Errorf("ParseTemplate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ParseTemplate() got = %v, want %v", got, tt.want)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("ParseTemplate() got = %v, want nil", got)
+				}
+				return
+			}
+			// templates hold their function map, which cannot be compared, so the parsed trees are compared
+			if got.Name() != tt.want.Name() || got.Tree.Root.String() != tt.want.Tree.Root.String() {
+				t.Errorf("ParseTemplate() got = %v, want %v", got.Tree.Root, tt.want.Tree.Root)
 			}
 		})
 	}
@@ -181,7 +187,7 @@ func TestRender(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := Render(tt.givenTemplate, tt.givenProperties)
+			got, err := Render(tt.givenTemplate, tt.givenProperties, Context{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Render() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,194 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delete
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/google/uuid"
+	"sort"
+	"strings"
+)
+
+// PruneObject is a Dynatrace object created by monaco, which is not defined by any config anymore
+type PruneObject struct {
+	// Type is the API ID of a classic config, or the schema ID of a settings object
+	Type string
+	// ObjectId is the ID of the Dynatrace object
+	ObjectId string
+	// Name is the name of a classic config. It is empty for settings objects.
+	Name string
+	// IsSettings is true if the object is a settings 2.0 object
+	IsSettings bool
+}
+
+func (o PruneObject) String() string {
+	if o.Name == "" {
+		return fmt.Sprintf("%s (%s)", o.ObjectId, o.Type)
+	}
+	return fmt.Sprintf("%s %q (%s)", o.ObjectId, o.Name, o.Type)
+}
+
+// FindObjectsToPrune searches all Dynatrace objects that were created by monaco, but are not defined by any of the
+// given configs.
+//
+// Settings objects are identified by their external ID, which monaco generates using idutils.GenerateExternalID.
+// Classic configs of APIs without unique names are identified by their ID, which monaco generates using
+// idutils.GenerateUuidFromConfigId. Configs of other classic APIs can not be attributed to monaco and are never pruned.
+//
+// The given configs need to contain all configs of an environment, including skipped ones and the ones of projects
+// that are not deployed, as all objects not matching any of them are returned.
+func FindObjectsToPrune(c client.Client, apis api.ApiMap, configs []config.Config) ([]PruneObject, []error) {
+	knownExternalIds := make(map[string]struct{})
+	knownUuids := make(map[string]map[string]struct{})
+
+	for _, conf := range configs {
+		switch {
+		case conf.Type.IsSettings():
+			knownExternalIds[idutils.GenerateExternalID(conf.Type.SchemaId, conf.Coordinate.ConfigId)] = struct{}{}
+		case conf.Type.IsEntities():
+			continue
+		default:
+			if _, found := knownUuids[conf.Coordinate.Type]; !found {
+				knownUuids[conf.Coordinate.Type] = make(map[string]struct{})
+			}
+			knownUuids[conf.Coordinate.Type][objectIdOfNonUniqueNameConfig(conf)] = struct{}{}
+		}
+	}
+
+	settingsObjects, errs := findSettingsToPrune(c, knownExternalIds)
+	classicObjects, classicErrs := findClassicConfigsToPrune(c, apis, knownUuids)
+
+	return append(settingsObjects, classicObjects...), append(errs, classicErrs...)
+}
+
+// objectIdOfNonUniqueNameConfig returns the ID monaco deploys configs of APIs without unique names to
+func objectIdOfNonUniqueNameConfig(conf config.Config) string {
+	configId := conf.Coordinate.ConfigId
+	if idutils.IsUuid(configId) || idutils.IsMeId(configId) {
+		return configId
+	}
+	return idutils.GenerateUuidFromConfigId(conf.Coordinate.Project, configId)
+}
+
+func findSettingsToPrune(c client.SettingsClient, knownExternalIds map[string]struct{}) ([]PruneObject, []error) {
+	schemas, err := c.ListSchemas()
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to fetch settings schemas: %w", err)}
+	}
+
+	var result []PruneObject
+	var errs []error
+
+	for _, schema := range schemas {
+		objects, err := c.ListSettings(schema.SchemaId, client.ListSettingsOptions{
+			DiscardValue: true,
+			Filter: func(o client.DownloadSettingsObject) bool {
+				_, known := knownExternalIds[o.ExternalId]
+				return strings.HasPrefix(o.ExternalId, idutils.ExternalIDPrefix) && !known
+			},
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not fetch settings 2.0 objects with schema ID %s: %w", schema.SchemaId, err))
+			continue
+		}
+
+		for _, o := range objects {
+			result = append(result, PruneObject{
+				Type:       schema.SchemaId,
+				ObjectId:   o.ObjectId,
+				IsSettings: true,
+			})
+		}
+	}
+
+	return result, errs
+}
+
+func findClassicConfigsToPrune(c client.ConfigClient, apis api.ApiMap, knownUuids map[string]map[string]struct{}) ([]PruneObject, []error) {
+	apiIds := make([]string, 0, len(apis))
+	for id, a := range apis {
+		if a.IsNonUniqueNameApi() {
+			apiIds = append(apiIds, id)
+		}
+	}
+	sort.Strings(apiIds)
+
+	var result []PruneObject
+	var errs []error
+
+	for _, id := range apiIds {
+		values, err := c.ListConfigs(apis[id])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fetch existing configs of api `%v`: %w", id, err))
+			continue
+		}
+
+		for _, v := range values {
+			if _, known := knownUuids[id][v.Id]; known || !isGeneratedFromConfigId(v) {
+				continue
+			}
+
+			result = append(result, PruneObject{
+				Type:     id,
+				ObjectId: v.Id,
+				Name:     v.Name,
+			})
+		}
+	}
+
+	return result, errs
+}
+
+// isGeneratedFromConfigId checks whether the ID of the given value was generated by idutils.GenerateUuidFromConfigId.
+// Those are name based UUIDs (version 3), that - unlike the ones Dynatrace generates - are not based on the name of the
+// config (see idutils.GenerateUuidFromName).
+func isGeneratedFromConfigId(v api.Value) bool {
+	id, err := uuid.Parse(v.Id)
+	if err != nil || id.Version() != 3 {
+		return false
+	}
+	return v.Id != idutils.GenerateUuidFromName(v.Name)
+}
+
+// PruneObjects deletes the given objects
+func PruneObjects(c client.Client, apis api.ApiMap, objects []PruneObject) []error {
+	var errs []error
+
+	for _, o := range objects {
+		log.Debug("Deleting %s", o)
+
+		var err error
+		if o.IsSettings {
+			err = c.DeleteSettings(o.ObjectId)
+		} else if a, found := apis[o.Type]; found {
+			err = c.DeleteConfigById(a, o.ObjectId)
+		} else {
+			err = fmt.Errorf("unknown api `%s`", o.Type)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", o, err))
+		}
+	}
+
+	return errs
+}
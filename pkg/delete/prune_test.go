@@ -0,0 +1,118 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delete
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var nonUniqueNameApi = api.NewStandardApi("non-unique", "/api/non-unique", true, "", false)
+
+func TestFindObjectsToPrune(t *testing.T) {
+	configs := []config.Config{
+		{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "known"},
+			Type:       config.Type{SchemaId: "builtin:alerting.profile"},
+		},
+		{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: "non-unique", ConfigId: "known"},
+			Type:       config.Type{Api: "non-unique"},
+		},
+	}
+
+	knownUuid := idutils.GenerateUuidFromConfigId("project", "known")
+	removedUuid := idutils.GenerateUuidFromConfigId("project", "removed")
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas().Return(client.SchemaList{{SchemaId: "builtin:alerting.profile"}}, nil)
+	c.EXPECT().ListSettings("builtin:alerting.profile", gomock.Any()).DoAndReturn(func(_ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		all := []client.DownloadSettingsObject{
+			{ObjectId: "known-object", ExternalId: idutils.GenerateExternalID("builtin:alerting.profile", "known")},
+			{ObjectId: "removed-object", ExternalId: idutils.GenerateExternalID("builtin:alerting.profile", "removed")},
+			{ObjectId: "manual-object", ExternalId: ""},
+			{ObjectId: "foreign-object", ExternalId: "some-other-tool:id"},
+		}
+
+		var result []client.DownloadSettingsObject
+		for _, o := range all {
+			if opts.Filter(o) {
+				result = append(result, o)
+			}
+		}
+		return result, nil
+	})
+	c.EXPECT().ListConfigs(nonUniqueNameApi).Return([]api.Value{
+		{Id: knownUuid, Name: "known"},
+		{Id: removedUuid, Name: "removed"},
+		{Id: uuid.NewString(), Name: "created manually"},
+		{Id: idutils.GenerateUuidFromName("name based"), Name: "name based"},
+	}, nil)
+
+	apis := api.ApiMap{
+		"non-unique": nonUniqueNameApi,
+		"unique":     api.NewStandardApi("unique", "/api/unique", false, "", false),
+	}
+
+	objects, errs := FindObjectsToPrune(c, apis, configs)
+	assert.Empty(t, errs)
+	assert.Equal(t, []PruneObject{
+		{Type: "builtin:alerting.profile", ObjectId: "removed-object", IsSettings: true},
+		{Type: "non-unique", ObjectId: removedUuid, Name: "removed"},
+	}, objects)
+}
+
+func TestFindObjectsToPrune_ReturnsErrorIfSchemasCanNotBeListed(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas().Return(nil, assert.AnError)
+
+	_, errs := FindObjectsToPrune(c, api.ApiMap{}, nil)
+	assert.Len(t, errs, 1)
+}
+
+func TestPruneObjects(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().DeleteSettings("settings-object").Return(nil)
+	c.EXPECT().DeleteConfigById(nonUniqueNameApi, "config-object").Return(nil)
+
+	errs := PruneObjects(c, api.ApiMap{"non-unique": nonUniqueNameApi}, []PruneObject{
+		{Type: "builtin:alerting.profile", ObjectId: "settings-object", IsSettings: true},
+		{Type: "non-unique", ObjectId: "config-object", Name: "name"},
+	})
+	assert.Empty(t, errs)
+}
+
+func TestPruneObjects_ContinuesOnError(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().DeleteSettings("first").Return(assert.AnError)
+	c.EXPECT().DeleteSettings("second").Return(nil)
+
+	errs := PruneObjects(c, api.ApiMap{}, []PruneObject{
+		{Type: "builtin:alerting.profile", ObjectId: "first", IsSettings: true},
+		{Type: "builtin:alerting.profile", ObjectId: "second", IsSettings: true},
+	})
+	assert.Len(t, errs, 1)
+}
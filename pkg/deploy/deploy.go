@@ -15,19 +15,113 @@
 package deploy
 
 import (
+	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/json"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2/topologysort"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/state"
+	"sync"
+	"time"
 )
 
 // DeployConfigsOptions defines additional options used by DeployConfigs
 type DeployConfigsOptions struct {
 	ContinueOnErr bool
 	DryRun        bool
+	// Online is set if the client used for a dry-run is connected to the environment, e.g. in validate-only mode.
+	// Lookups then query the environment instead of resolving to placeholder IDs.
+	Online bool
+	// Parallel defines whether configs that do not depend on each other are deployed concurrently.
+	// The amount of parallel requests is not limited by DeployConfigs and needs to be limited by the given client,
+	// e.g. using client.LimitClientParallelRequests
+	Parallel bool
+	// State is the optional deployment state. If set, configs known by the state are updated directly by their
+	// object ID, and the state is updated with the result of each deployed config.
+	State *state.State
+	// Report is the optional recorder the outcome of each config is recorded to
+	Report *report.Recorder
+	// Rollback snapshots each object before it is modified, and restores all modified objects if the deployment
+	// fails. Objects created by the deployment are deleted. It has no effect during dry-runs.
+	Rollback bool
+	// SkipUnchanged reads the existing object of each config before deploying it, and skips the update if the
+	// object already equals the rendered config. It has no effect during dry-runs.
+	SkipUnchanged bool
+}
+
+// deployContext holds the optional state shared by the deployment of all configs of a DeployConfigs call
+type deployContext struct {
+	state *state.State
+	// journal records all modified objects if rollback is enabled
+	journal *rollbackJournal
+	// skipUnchanged is set if objects equal to the rendered config should not be updated
+	skipUnchanged bool
+	// lookup resolves lookup parameters. Its results are cached for the whole deployment.
+	lookup EnvironmentLookup
+	// live finds the existing objects of configs, if they are read. Its lists are cached for the whole deployment.
+	live *clientLookup
+}
+
+func newDeployContext(c client.Client, apis api.ApiMap, opts DeployConfigsOptions) deployContext {
+	ctx := deployContext{state: opts.State, lookup: NewEnvironmentLookup(c, apis)}
+	if opts.DryRun && !opts.Online {
+		ctx.lookup = dryRunEnvironmentLookup{ctx.lookup}
+	}
+	if opts.Rollback && !opts.DryRun {
+		ctx.journal = &rollbackJournal{state: opts.State, report: opts.Report}
+	}
+	ctx.skipUnchanged = opts.SkipUnchanged && !opts.DryRun
+	if ctx.readsExistingObjects() {
+		ctx.live = newClientLookup(c)
+	}
+	return ctx
+}
+
+// readsExistingObjects returns whether the existing object of each config needs to be read before deploying it
+func (ctx deployContext) readsExistingObjects() bool {
+	return ctx.journal != nil || ctx.skipUnchanged
+}
+
+// actionOf returns the action taken by deploying a config to the object with the given ID. If the existing objects
+// are not read, it is unknown whether the object was created or updated.
+func (ctx deployContext) actionOf(existing liveObject, existingFound bool, objectId string) report.Action {
+	switch {
+	case !ctx.readsExistingObjects():
+		return report.ActionDeployed
+	case existingFound && (existing.id == objectId || existing.id == ""): // objects of single configuration APIs have no ID
+		return report.ActionUpdated
+	default:
+		return report.ActionCreated
+	}
+}
+
+// serverManagedFields are fields of classic config API payloads which are set by Dynatrace and not compared
+var serverManagedFields = []string{"id", "metadata"}
+
+// differencesTo returns the differences between the rendered config and the existing object. If the update replaces
+// the whole object, as for classic configs, fields only present in the existing object are differences as well,
+// except for the serverManagedFields.
+func differencesTo(renderedConfig string, existing liveObject, replacesObject bool) ([]json.Difference, error) {
+	if replacesObject {
+		return json.DiffExact([]byte(renderedConfig), existing.payload, serverManagedFields...)
+	}
+	return json.Diff([]byte(renderedConfig), existing.payload)
+}
+
+// isUnchanged returns whether deploying the rendered config would not change the existing object, see differencesTo
+func isUnchanged(renderedConfig string, existing liveObject, replacesObject bool) bool {
+	differences, err := differencesTo(renderedConfig, existing, replacesObject)
+	if err != nil {
+		log.Debug("Failed to compare rendered config with existing object %s: %s", existing.id, err)
+		return false
+	}
+	return len(differences) == 0
 }
 
 // DeployConfigs deploys the given configs with the given apis via the given client
@@ -36,54 +130,179 @@ type DeployConfigsOptions struct {
 func DeployConfigs(client client.Client, apis api.ApiMap,
 	sortedConfigs []config.Config, opts DeployConfigsOptions) []error {
 
+	if opts.Parallel {
+		return deployConfigsInParallel(client, apis, sortedConfigs, opts)
+	}
+
 	entityMap := NewEntityMap(apis)
+	ctx := newDeployContext(client, apis, opts)
 	var errors []error
 
-	for _, c := range sortedConfigs {
+	for i, c := range sortedConfigs {
 		c := c // to avoid implicit memory aliasing (gosec G601)
 
-		if c.Skip {
-			log.Info("\tSkipping deployment of config %s", c.Coordinate)
+		deploymentErrors := deployAndRememberConfig(client, apis, entityMap, &c, opts, ctx)
+		if deploymentErrors != nil {
+			errors = append(errors, deploymentErrors...)
 
-			entityMap.PutResolved(c.Coordinate, parameter.ResolvedEntity{
-				EntityName: c.Coordinate.ConfigId,
-				Coordinate: c.Coordinate,
-				Properties: parameter.Properties{},
-				Skip:       true,
-			})
-			continue
+			if !opts.ContinueOnErr && !opts.DryRun {
+				recordNotRun(opts, sortedConfigs[i+1:])
+				return rollbackOnError(client, ctx, errors)
+			}
 		}
+	}
 
-		logAction, logVerb := getWordsForLogging(opts.DryRun)
-		log.Info("\t%s config %s", logAction, c.Coordinate)
+	return rollbackOnError(client, ctx, errors)
+}
+
+// rollbackOnError rolls back all objects modified by the deployment if any errors occurred and rollback is enabled.
+// Objects that could not be rolled back are added to the returned errors.
+func rollbackOnError(c client.Client, ctx deployContext, errors []error) []error {
+	if ctx.journal == nil || len(errors) == 0 {
+		return errors
+	}
+	return append(errors, ctx.journal.rollback(c)...)
+}
+
+// deployConfigsInParallel deploys the given configs level by level, as returned by topologysort.GroupConfigsByDependencyLevel.
+// All configs of a level are deployed concurrently, the next level is only started once all configs of the
+// previous level are done. If an error occurs and neither ContinueOnErr nor DryRun are set, no further
+// configs are started, but configs already in progress are finished.
+func deployConfigsInParallel(client client.Client, apis api.ApiMap,
+	sortedConfigs []config.Config, opts DeployConfigsOptions) []error {
+
+	entityMap := NewEntityMap(apis)
+	ctx := newDeployContext(client, apis, opts)
+	var errors []error
+	var errLock sync.Mutex
+
+	stopOnError := !opts.ContinueOnErr && !opts.DryRun
+
+	levels := topologysort.GroupConfigsByDependencyLevel(sortedConfigs)
+	for i, level := range levels {
+		wg := sync.WaitGroup{}
+		wg.Add(len(level))
+
+		for _, c := range level {
+			c := c // to avoid implicit memory aliasing (gosec G601)
+
+			go func() {
+				defer wg.Done()
 
-		var entity parameter.ResolvedEntity
-		var deploymentErrors []error
+				errLock.Lock()
+				stopped := stopOnError && len(errors) > 0
+				errLock.Unlock()
 
-		switch {
-		case c.Type.IsEntities():
-			log.Debug("Entities are not deployable, skipping entity type: %s", c.Type.EntitiesType)
-		case c.Type.IsSettings():
-			entity, deploymentErrors = deploySetting(client, entityMap, &c)
-		default:
-			entity, deploymentErrors = deployConfig(client, apis, entityMap, &c)
+				if stopped {
+					recordNotRun(opts, []config.Config{c})
+					return
+				}
+
+				deploymentErrors := deployAndRememberConfig(client, apis, entityMap, &c, opts, ctx)
+				if deploymentErrors != nil {
+					errLock.Lock()
+					errors = append(errors, deploymentErrors...)
+					errLock.Unlock()
+				}
+			}()
 		}
 
-		if deploymentErrors != nil {
-			for _, err := range deploymentErrors {
-				errors = append(errors, fmt.Errorf("failed to %s config %s: %w", logVerb, c.Coordinate, err))
-			}
+		wg.Wait()
 
-			if !opts.ContinueOnErr && !opts.DryRun {
-				return errors
+		if stopOnError && len(errors) > 0 {
+			for _, notRun := range levels[i+1:] {
+				recordNotRun(opts, notRun)
 			}
+			return rollbackOnError(client, ctx, errors)
 		}
-		entityMap.PutResolved(entity.Coordinate, entity)
 	}
 
+	return rollbackOnError(client, ctx, errors)
+}
+
+// deployAndRememberConfig deploys a single config and stores the resulting entity in the given entityMap.
+// Skipped configs are not deployed, but still remembered as skipped.
+func deployAndRememberConfig(client client.Client, apis api.ApiMap, entityMap *EntityMap, c *config.Config, opts DeployConfigsOptions, ctx deployContext) []error {
+	start := time.Now()
+
+	if c.Skip {
+		log.Info("\tSkipping deployment of config %s", c.Coordinate)
+		recordResult(opts, c, report.ActionSkipped, "", start, nil)
+
+		entityMap.PutResolved(c.Coordinate, parameter.ResolvedEntity{
+			EntityName: c.Coordinate.ConfigId,
+			Coordinate: c.Coordinate,
+			Properties: parameter.Properties{},
+			Skip:       true,
+		})
+		return nil
+	}
+
+	logAction, logVerb := getWordsForLogging(opts.DryRun)
+	log.Info("\t%s config %s", logAction, c.Coordinate)
+
+	var entity parameter.ResolvedEntity
+	var action report.Action
+	var deploymentErrors []error
+
+	switch {
+	case c.Type.IsEntities():
+		log.Debug("Entities are not deployable, skipping entity type: %s", c.Type.EntitiesType)
+		action = report.ActionSkipped
+	case c.Type.IsSettings():
+		entity, action, deploymentErrors = deploySetting(client, entityMap, c, ctx)
+	default:
+		entity, action, deploymentErrors = deployConfig(client, apis, entityMap, c, ctx)
+	}
+
+	if opts.DryRun && action != report.ActionSkipped {
+		action = report.ActionValidated
+	}
+
+	var errors []error
+	for _, err := range deploymentErrors {
+		errors = append(errors, fmt.Errorf("failed to %s config %s: %w", logVerb, c.Coordinate, err))
+	}
+
+	entityMap.PutResolved(entity.Coordinate, entity)
+
+	if len(deploymentErrors) > 0 {
+		action = report.ActionFailed
+	}
+	objectId, _ := entity.Properties[config.IdParameter].(string)
+	recordResult(opts, c, action, objectId, start, deploymentErrors)
+
 	return errors
 }
 
+// recordResult records the outcome of deploying the given config, if a report is requested
+func recordResult(opts DeployConfigsOptions, c *config.Config, action report.Action, objectId string, start time.Time, errs []error) {
+	if opts.Report == nil {
+		return
+	}
+
+	var reportErrors []report.Error
+	for _, err := range errs {
+		reportErrors = append(reportErrors, report.NewError(err))
+	}
+
+	opts.Report.Record(report.Record{
+		Environment: c.Environment,
+		Coordinate:  c.Coordinate,
+		Action:      action,
+		ObjectId:    objectId,
+		Duration:    time.Since(start),
+		Errors:      reportErrors,
+	})
+}
+
+// recordNotRun records the given configs as not run, if a report is requested
+func recordNotRun(opts DeployConfigsOptions, configs []config.Config) {
+	for i := range configs {
+		recordResult(opts, &configs[i], report.ActionNotRun, "", time.Now(), nil)
+	}
+}
+
 // getWordsForLogging returns fitting action and verb words to clearly tell a user if configuration is
 // deployed or validated when logging based on the dry-run boolean
 func getWordsForLogging(isDryRun bool) (action, verb string) {
@@ -93,48 +312,87 @@ func getWordsForLogging(isDryRun bool) (action, verb string) {
 	return "Deploying", "deploy"
 }
 
-func deployConfig(client client.ConfigClient, apis api.ApiMap, entityMap *EntityMap, conf *config.Config) (parameter.ResolvedEntity, []error) {
+func deployConfig(client client.ConfigClient, apis api.ApiMap, entityMap *EntityMap, conf *config.Config, ctx deployContext) (_ parameter.ResolvedEntity, _ report.Action, deploymentErrors []error) {
 
 	apiToDeploy := apis[conf.Coordinate.Type]
 	if apiToDeploy == nil {
-		return parameter.ResolvedEntity{}, []error{fmt.Errorf("unknown api `%s`. this is most likely a bug", conf.Type.Api)}
+		return parameter.ResolvedEntity{}, "", []error{fmt.Errorf("unknown api `%s`. this is most likely a bug", conf.Type.Api)}
 	}
 
-	properties, errors := resolveProperties(conf, entityMap.Resolved())
+	properties, errors := resolveProperties(conf, entityMap.Resolved(), ctx.lookup)
 	if len(errors) > 0 {
-		return parameter.ResolvedEntity{}, errors
+		return parameter.ResolvedEntity{}, "", errors
 	}
 
+	var nonUniqueNameApi bool
 	configName, err := extractConfigName(conf, properties)
 	if err != nil {
 		errors = append(errors, err)
 	} else {
-		if entityMap.Known(apiToDeploy.GetId(), configName) && !apiToDeploy.IsNonUniqueNameApi() {
+		// the name is reserved atomically, so that configs of the same name deployed in parallel can not both pass this check
+		nonUniqueNameApi = apiToDeploy.IsNonUniqueNameApi()
+		if !nonUniqueNameApi && !entityMap.ReserveName(apiToDeploy.GetId(), configName) {
 			errors = append(errors, newConfigDeployErr(conf, fmt.Sprintf("duplicated config name `%s`", configName)))
+		} else if !nonUniqueNameApi {
+			// release the reserved name if the config fails to deploy, so that it is not reported as duplicate later on
+			defer func() {
+				if len(deploymentErrors) > 0 {
+					entityMap.ReleaseName(apiToDeploy.GetId(), configName)
+				}
+			}()
 		}
 	}
 	if len(errors) > 0 {
-		return parameter.ResolvedEntity{}, errors
+		return parameter.ResolvedEntity{}, "", errors
 	}
 
 	renderedConfig, err := conf.Render(properties)
 	if err != nil {
-		return parameter.ResolvedEntity{}, []error{err}
+		return parameter.ResolvedEntity{}, "", []error{err}
 	}
 
 	if apiToDeploy.DeprecatedBy() != "" {
 		log.Warn("API for \"%s\" is deprecated! Please consider migrating to \"%s\"!", apiToDeploy.GetId(), apiToDeploy.DeprecatedBy())
 	}
 
+	var existing liveObject
+	var existingFound bool
+	if ctx.readsExistingObjects() {
+		existing, existingFound, err = findExistingConfig(ctx.live, apiToDeploy, conf, configName, ctx.state)
+		if err != nil {
+			return parameter.ResolvedEntity{}, "", []error{newConfigDeployErr(conf, fmt.Sprintf("failed to read existing object: %s", err))}
+		}
+	}
+
 	var entity api.DynatraceEntity
-	if apiToDeploy.IsNonUniqueNameApi() {
-		entity, err = upsertNonUniqueNameConfig(client, apiToDeploy, conf, configName, renderedConfig)
-	} else {
-		entity, err = client.UpsertConfigByName(apiToDeploy, configName, []byte(renderedConfig))
+	var action report.Action
+
+	switch {
+	case ctx.skipUnchanged && existingFound && isUnchanged(renderedConfig, existing, true):
+		log.Info("\tConfig %s is unchanged, skipping update", conf.Coordinate)
+		entity = api.DynatraceEntity{Id: existing.id, Name: configName}
+		action = report.ActionUnchanged
+	default:
+		if nonUniqueNameApi {
+			entity, err = upsertNonUniqueNameConfig(client, apiToDeploy, conf, configName, renderedConfig)
+		} else {
+			entity, err = upsertUniqueNameConfig(client, apiToDeploy, conf, configName, renderedConfig, ctx.state)
+		}
+
+		if err != nil {
+			return parameter.ResolvedEntity{}, "", []error{newConfigDeployErr(conf, err.Error())}
+		}
+
+		ctx.journal.recordDeployment(conf, apiToDeploy, configName, entity.Id, existing, existingFound)
+		action = ctx.actionOf(existing, existingFound, entity.Id)
 	}
 
-	if err != nil {
-		return parameter.ResolvedEntity{}, []error{newConfigDeployErr(conf, err.Error())}
+	if ctx.state != nil {
+		ctx.state.Put(conf.Environment, conf.Coordinate, state.Entry{
+			ObjectId: entity.Id,
+			Name:     configName,
+			Checksum: state.Checksum([]byte(renderedConfig)),
+		})
 	}
 
 	properties[config.IdParameter] = entity.Id
@@ -145,7 +403,54 @@ func deployConfig(client client.ConfigClient, apis api.ApiMap, entityMap *Entity
 		Coordinate: conf.Coordinate,
 		Properties: properties,
 		Skip:       false,
-	}, nil
+	}, action, nil
+}
+
+// upsertUniqueNameConfig upserts the config by its name. If the given state knows the object the config was last
+// deployed to, the object is updated directly by its ID instead. This also keeps the object if the config was renamed.
+func upsertUniqueNameConfig(configClient client.ConfigClient, apiToDeploy api.Api, conf *config.Config, configName string, renderedConfig string, deployState *state.State) (api.DynatraceEntity, error) {
+	known, found := knownObject(deployState, apiToDeploy, conf)
+	if !found {
+		return configClient.UpsertConfigByName(apiToDeploy, configName, []byte(renderedConfig))
+	}
+
+	if known.Name != configName {
+		log.Info("\tConfig %s was renamed from %q to %q, updating object %s", conf.Coordinate, known.Name, configName, known.ObjectId)
+	}
+
+	entity, err := configClient.UpdateConfigById(apiToDeploy, known.ObjectId, configName, []byte(renderedConfig))
+	if errors.Is(err, client.ErrConfigNotFound) {
+		log.Warn("\tObject %s of config %s known by the deployment state does not exist anymore, searching by name", known.ObjectId, conf.Coordinate)
+		return configClient.UpsertConfigByName(apiToDeploy, configName, []byte(renderedConfig))
+	}
+
+	return entity, err
+}
+
+// knownObject returns the state entry of the given config, if the config is deployed by its known object ID
+func knownObject(deployState *state.State, apiToDeploy api.Api, conf *config.Config) (state.Entry, bool) {
+	if deployState == nil || apiToDeploy.IsSingleConfigurationApi() || apiToDeploy.IsNonUniqueNameApi() || apiToDeploy.GetId() == "extension" {
+		return state.Entry{}, false
+	}
+
+	known, found := deployState.Get(conf.Environment, conf.Coordinate)
+	if !found || known.ObjectId == "" {
+		return state.Entry{}, false
+	}
+	return known, true
+}
+
+// findExistingConfig reads the object the given config is about to be deployed to
+func findExistingConfig(live *clientLookup, apiToDeploy api.Api, conf *config.Config, configName string, deployState *state.State) (liveObject, bool, error) {
+	if known, found := knownObject(deployState, apiToDeploy, conf); found {
+		existing, _, err := live.readConfig(apiToDeploy, known.ObjectId)
+		if err == nil {
+			return existing, true, nil
+		}
+		log.Debug("Failed to read object %s known by the deployment state, searching by name: %s", known.ObjectId, err)
+	}
+
+	return live.findConfig(apiToDeploy, conf, configName)
 }
 
 func upsertNonUniqueNameConfig(client client.ConfigClient, apiToDeploy api.Api, conf *config.Config, configName string, renderedConfig string) (api.DynatraceEntity, error) {
@@ -162,32 +467,62 @@ func upsertNonUniqueNameConfig(client client.ConfigClient, apiToDeploy api.Api,
 	return client.UpsertConfigByNonUniqueNameAndId(apiToDeploy, entityUuid, configName, []byte(renderedConfig))
 }
 
-func deploySetting(settingsClient client.SettingsClient, entityMap *EntityMap, c *config.Config) (parameter.ResolvedEntity, []error) {
-	properties, errors := resolveProperties(c, entityMap.Resolved())
+func deploySetting(settingsClient client.SettingsClient, entityMap *EntityMap, c *config.Config, ctx deployContext) (parameter.ResolvedEntity, report.Action, []error) {
+	properties, errors := resolveProperties(c, entityMap.Resolved(), ctx.lookup)
 	if len(errors) > 0 {
-		return parameter.ResolvedEntity{}, errors
+		return parameter.ResolvedEntity{}, "", errors
 	}
 
 	scope, err := extractScope(properties)
 	if err != nil {
-		return parameter.ResolvedEntity{}, []error{err}
+		return parameter.ResolvedEntity{}, "", []error{err}
 	}
 
 	renderedConfig, err := c.Render(properties)
 	if err != nil {
-		return parameter.ResolvedEntity{}, []error{err}
+		return parameter.ResolvedEntity{}, "", []error{err}
 	}
 
-	entity, err := settingsClient.UpsertSettings(client.SettingsObject{
-		Id:             c.Coordinate.ConfigId,
-		SchemaId:       c.Type.SchemaId,
-		SchemaVersion:  c.Type.SchemaVersion,
-		Scope:          scope,
-		Content:        []byte(renderedConfig),
-		OriginObjectId: c.OriginObjectId,
-	})
-	if err != nil {
-		return parameter.ResolvedEntity{}, []error{newConfigDeployErr(c, err.Error())}
+	var existing liveObject
+	var existingFound bool
+	if ctx.readsExistingObjects() {
+		existing, existingFound, err = ctx.live.findSetting(c)
+		if err != nil {
+			return parameter.ResolvedEntity{}, "", []error{newConfigDeployErr(c, fmt.Sprintf("failed to read existing object: %s", err))}
+		}
+	}
+
+	var entity api.DynatraceEntity
+	var action report.Action
+
+	switch {
+	case ctx.skipUnchanged && existingFound && existing.scope == scope && isUnchanged(renderedConfig, existing, false):
+		log.Info("\tConfig %s is unchanged, skipping update", c.Coordinate)
+		entity = api.DynatraceEntity{Id: existing.id, Name: existing.id}
+		action = report.ActionUnchanged
+	default:
+		entity, err = settingsClient.UpsertSettings(client.SettingsObject{
+			Id:             c.Coordinate.ConfigId,
+			SchemaId:       c.Type.SchemaId,
+			SchemaVersion:  c.Type.SchemaVersion,
+			Scope:          scope,
+			Content:        []byte(renderedConfig),
+			OriginObjectId: c.OriginObjectId,
+		})
+		if err != nil {
+			return parameter.ResolvedEntity{}, "", []error{newConfigDeployErr(c, err.Error())}
+		}
+
+		ctx.journal.recordDeployment(c, nil, entity.Name, entity.Id, existing, existingFound)
+		action = ctx.actionOf(existing, existingFound, entity.Id)
+	}
+
+	if ctx.state != nil {
+		ctx.state.Put(c.Environment, c.Coordinate, state.Entry{
+			ObjectId: entity.Id,
+			Name:     entity.Name,
+			Checksum: state.Checksum([]byte(renderedConfig)),
+		})
 	}
 
 	properties[config.IdParameter] = entity.Id
@@ -198,7 +533,7 @@ func deploySetting(settingsClient client.SettingsClient, entityMap *EntityMap, c
 		Coordinate: c.Coordinate,
 		Properties: properties,
 		Skip:       false,
-	}, nil
+	}, action, nil
 
 }
 
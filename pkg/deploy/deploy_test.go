@@ -23,12 +23,15 @@ import (
 	"github.com/golang/mock/gomock"
 	"testing"
 
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2/topologysort"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/state"
 	"github.com/google/uuid"
 	"gotest.tools/assert"
 )
@@ -76,7 +79,7 @@ func TestDeployConfig(t *testing.T) {
 		Skip:        false,
 	}
 
-	resolvedEntity, errors := deployConfig(client, testApiMap, NewEntityMap(testApiMap), &conf)
+	resolvedEntity, _, errors := deployConfig(client, testApiMap, NewEntityMap(testApiMap), &conf, deployContext{})
 
 	assert.Assert(t, len(errors) == 0, "there should be no errors (no errors: %d, %s)", len(errors), errors)
 	assert.Equal(t, name, resolvedEntity.EntityName, "%s == %s")
@@ -122,7 +125,7 @@ func TestDeploySettingShouldFailCyclicParameterDependencies(t *testing.T) {
 		Template:   generateDummyTemplate(t),
 		Parameters: toParameterMap(parameters),
 	}
-	_, errors := deploySetting(client, NewEntityMap(testApiMap), conf)
+	_, _, errors := deploySetting(client, NewEntityMap(testApiMap), conf, deployContext{})
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -133,7 +136,7 @@ func TestDeploySettingShouldFailRenderTemplate(t *testing.T) {
 		Template: generateFaultyTemplate(t),
 	}
 
-	_, errors := deploySetting(client, NewEntityMap(testApiMap), conf)
+	_, _, errors := deploySetting(client, NewEntityMap(testApiMap), conf, deployContext{})
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -169,7 +172,7 @@ func TestDeploySettingShouldFailUpsert(t *testing.T) {
 		Template:   generateDummyTemplate(t),
 		Parameters: toParameterMap(parameters),
 	}
-	_, errors := deploySetting(client, NewEntityMap(testApiMap), conf)
+	_, _, errors := deploySetting(client, NewEntityMap(testApiMap), conf, deployContext{})
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -202,7 +205,7 @@ func TestDeploySetting(t *testing.T) {
 		Template:   generateDummyTemplate(t),
 		Parameters: toParameterMap(parameters),
 	}
-	_, errors := deploySetting(client, NewEntityMap(testApiMap), conf)
+	_, _, errors := deploySetting(client, NewEntityMap(testApiMap), conf, deployContext{})
 	assert.Assert(t, len(errors) == 0, "there should be no errors (no errors: %d, %s)", len(errors), errors)
 }
 
@@ -231,11 +234,48 @@ func TestDeployConfigShouldFailOnAnAlreadyKnownEntityName(t *testing.T) {
 	}
 	entityMap := NewEntityMap(testApiMap)
 	entityMap.PutResolved(coordinate.Coordinate{Type: "dashboard"}, parameter.ResolvedEntity{EntityName: name})
-	_, errors := deployConfig(client, testApiMap, entityMap, &conf)
+	_, _, errors := deployConfig(client, testApiMap, entityMap, &conf, deployContext{})
 
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
+func TestDeployConfigReleasesNameOfFailedConfig(t *testing.T) {
+	name := "test"
+	parameters := []topologysort.ParameterWithName{
+		{
+			Name: config.NameParameter,
+			Parameter: &parameter.DummyParameter{
+				Value: name,
+			},
+		},
+	}
+
+	conf := config.Config{
+		Template: generateDummyTemplate(t),
+		Coordinate: coordinate.Coordinate{
+			Project:  "project1",
+			Type:     "dashboard",
+			ConfigId: "dashboard-1",
+		},
+		Environment: "development",
+		Parameters:  toParameterMap(parameters),
+		Skip:        false,
+	}
+
+	failingClient := client.NewMockClient(gomock.NewController(t))
+	failingClient.EXPECT().UpsertConfigByName(gomock.Any(), name, gomock.Any()).Return(api.DynatraceEntity{}, fmt.Errorf("upsert failed"))
+
+	entityMap := NewEntityMap(testApiMap)
+	_, _, errors := deployConfig(failingClient, testApiMap, entityMap, &conf, deployContext{})
+	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
+	assert.Equal(t, entityMap.Known("dashboard", name), false)
+
+	otherConf := conf
+	otherConf.Coordinate.ConfigId = "dashboard-2"
+	_, _, errors = deployConfig(&client.DummyClient{}, testApiMap, entityMap, &otherConf, deployContext{})
+	assert.Assert(t, len(errors) == 0, "there should be no errors (errors: %s)", errors)
+}
+
 func TestDeployConfigShouldFailCyclicParameterDependencies(t *testing.T) {
 	ownerParameterName := "owner"
 	configCoordinates := coordinate.Coordinate{
@@ -282,7 +322,7 @@ func TestDeployConfigShouldFailCyclicParameterDependencies(t *testing.T) {
 		Skip:        false,
 	}
 
-	_, errors := deployConfig(client, testApiMap, NewEntityMap(testApiMap), &conf)
+	_, _, errors := deployConfig(client, testApiMap, NewEntityMap(testApiMap), &conf, deployContext{})
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -302,7 +342,7 @@ func TestDeployConfigShouldFailOnMissingNameParameter(t *testing.T) {
 		Skip:        false,
 	}
 
-	_, errors := deployConfig(client, testApiMap, NewEntityMap(testApiMap), &conf)
+	_, _, errors := deployConfig(client, testApiMap, NewEntityMap(testApiMap), &conf, deployContext{})
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -338,7 +378,7 @@ func TestDeployConfigShouldFailOnReferenceOnUnknownConfig(t *testing.T) {
 		Skip:        false,
 	}
 
-	_, errors := deployConfig(client, testApiMap, NewEntityMap(testApiMap), &conf)
+	_, _, errors := deployConfig(client, testApiMap, NewEntityMap(testApiMap), &conf, deployContext{})
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -376,7 +416,7 @@ func TestDeployConfigShouldFailOnReferenceOnSkipConfig(t *testing.T) {
 		Skip:        false,
 	}
 
-	_, errors := deployConfig(client, testApiMap, NewEntityMap(testApiMap), &conf)
+	_, _, errors := deployConfig(client, testApiMap, NewEntityMap(testApiMap), &conf, deployContext{})
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -584,6 +624,393 @@ func TestDeployConfigsWithDeploymentErrors(t *testing.T) {
 
 }
 
+func TestDeployConfigsInParallel(t *testing.T) {
+	theApiName := "theApiName"
+	theApi := api.NewStandardApi(theApiName, "/api/config/v1/theApi", false, "", false)
+	apis := api.ApiMap{theApiName: theApi}
+
+	referenced := coordinate.Coordinate{Project: "project", Type: theApiName, ConfigId: "referenced"}
+	referencing := coordinate.Coordinate{Project: "project", Type: theApiName, ConfigId: "referencing"}
+	independent := coordinate.Coordinate{Project: "project", Type: theApiName, ConfigId: "independent"}
+
+	sortedConfigs := []config.Config{
+		{
+			Coordinate: referenced,
+			Template:   generateDummyTemplate(t),
+			Type:       config.Type{Api: theApiName},
+			Parameters: config.Parameters{config.NameParameter: &parameter.DummyParameter{Value: "referenced"}},
+		},
+		{
+			Coordinate: independent,
+			Template:   generateDummyTemplate(t),
+			Type:       config.Type{Api: theApiName},
+			Parameters: config.Parameters{config.NameParameter: &parameter.DummyParameter{Value: "independent"}},
+		},
+		{
+			Coordinate: referencing,
+			Template:   generateDummyTemplate(t),
+			Type:       config.Type{Api: theApiName},
+			Parameters: config.Parameters{
+				config.NameParameter: &parameter.DummyParameter{Value: "referencing"},
+				"ref": &parameter.DummyParameter{
+					Value:      "ref",
+					References: []parameter.ParameterReference{{Config: referenced, Property: config.IdParameter}},
+				},
+			},
+		},
+	}
+
+	dummyClient := &client.DummyClient{}
+	errors := DeployConfigs(dummyClient, apis, sortedConfigs, DeployConfigsOptions{Parallel: true})
+
+	assert.Assert(t, len(errors) == 0, "there should be no errors (errors: %s)", errors)
+	assert.Equal(t, len(dummyClient.Entries[theApi]), 3)
+}
+
+func TestDeployConfigsInParallelFailsOnDuplicatedNamesInSameLevel(t *testing.T) {
+	theApiName := "theApiName"
+	theApi := api.NewStandardApi(theApiName, "/api/config/v1/theApi", false, "", false)
+	apis := api.ApiMap{theApiName: theApi}
+
+	sortedConfigs := []config.Config{
+		{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: theApiName, ConfigId: "first"},
+			Template:   generateDummyTemplate(t),
+			Type:       config.Type{Api: theApiName},
+			Parameters: config.Parameters{config.NameParameter: &parameter.DummyParameter{Value: "same name"}},
+		},
+		{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: theApiName, ConfigId: "second"},
+			Template:   generateDummyTemplate(t),
+			Type:       config.Type{Api: theApiName},
+			Parameters: config.Parameters{config.NameParameter: &parameter.DummyParameter{Value: "same name"}},
+		},
+	}
+
+	for i := 0; i < 50; i++ {
+		dummyClient := &client.DummyClient{}
+		errors := DeployConfigs(dummyClient, apis, sortedConfigs, DeployConfigsOptions{Parallel: true, ContinueOnErr: true})
+
+		assert.Equal(t, len(errors), 1, "expected exactly one config to fail with a duplicated name (errors: %s)", errors)
+		assert.ErrorContains(t, errors[0], "duplicated config name `same name`")
+		assert.Equal(t, len(dummyClient.Entries[theApi]), 1)
+	}
+}
+
+func TestDeployConfigsInParallelWithDeploymentErrors(t *testing.T) {
+	theApiName := "theApiName"
+	theApi := api.NewStandardApi(theApiName, "/api/config/v1/theApi", false, "", false)
+	apis := api.ApiMap{theApiName: theApi}
+
+	failing := coordinate.Coordinate{Project: "project", Type: theApiName, ConfigId: "failing"}
+	dependent := coordinate.Coordinate{Project: "project", Type: theApiName, ConfigId: "dependent"}
+
+	sortedConfigs := []config.Config{
+		{
+			Coordinate: failing,
+			Template:   generateDummyTemplate(t),
+			Type:       config.Type{Api: theApiName},
+			Parameters: config.Parameters{}, // missing name parameter leads to deployment failure
+		},
+		{
+			Coordinate: dependent,
+			Template:   generateDummyTemplate(t),
+			Type:       config.Type{Api: theApiName},
+			Parameters: config.Parameters{
+				config.NameParameter: &parameter.DummyParameter{
+					Value:      "dependent",
+					References: []parameter.ParameterReference{{Config: failing, Property: config.IdParameter}},
+				},
+			},
+		},
+	}
+
+	t.Run("deployment error - stop on error", func(t *testing.T) {
+		dummyClient := &client.DummyClient{}
+		recorder := report.NewRecorder()
+		errors := DeployConfigs(dummyClient, apis, sortedConfigs, DeployConfigsOptions{Parallel: true, Report: recorder})
+		assert.Equal(t, 1, len(errors), fmt.Sprintf("Expected 1 error, but just got %d", len(errors)))
+		assert.Equal(t, 0, len(dummyClient.Entries[theApi]))
+		assert.DeepEqual(t, recorder.CountByAction(), map[report.Action]int{report.ActionFailed: 1, report.ActionNotRun: 1})
+	})
+
+	t.Run("deployment error - continue on error", func(t *testing.T) {
+		dummyClient := &client.DummyClient{}
+		errors := DeployConfigs(dummyClient, apis, sortedConfigs, DeployConfigsOptions{Parallel: true, ContinueOnErr: true})
+		assert.Equal(t, 2, len(errors), fmt.Sprintf("Expected 2 errors, but just got %d", len(errors)))
+	})
+}
+
+func TestDeployConfigsRecordsConfigsNotRunAfterError(t *testing.T) {
+	failing := dashboardConfig(t, "failing", "failing")
+	failing.Parameters = config.Parameters{} // missing name parameter leads to deployment failure
+
+	sortedConfigs := []config.Config{
+		dashboardConfig(t, "deployed", "deployed"),
+		failing,
+		dashboardConfig(t, "not-run", "not run"),
+	}
+
+	recorder := report.NewRecorder()
+	errors := DeployConfigs(client.NewDummyClient(), testApiMap, sortedConfigs, DeployConfigsOptions{Report: recorder})
+	assert.Equal(t, len(errors), 1)
+
+	records := recorder.Records()
+	assert.Equal(t, len(records), 3)
+	assert.Equal(t, records[0].Action, report.ActionDeployed)
+	assert.Equal(t, records[1].Action, report.ActionFailed)
+	assert.Equal(t, records[2].Action, report.ActionNotRun)
+	assert.DeepEqual(t, records[2].Coordinate, sortedConfigs[2].Coordinate)
+}
+
+func TestDeployConfigWithStateUpdatesKnownObjectById(t *testing.T) {
+	dummyClient := client.NewDummyClient()
+	dummyClient.Entries[dashboardApi] = []client.DataEntry{{Name: "old name", Id: "known-id"}}
+
+	conf := config.Config{
+		Template:    generateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project1", Type: "dashboard", ConfigId: "dashboard-1"},
+		Environment: "development",
+		Parameters: toParameterMap([]topologysort.ParameterWithName{
+			{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: "new name"}},
+		}),
+	}
+
+	deployState := state.New()
+	deployState.Put(conf.Environment, conf.Coordinate, state.Entry{ObjectId: "known-id", Name: "old name"})
+
+	resolvedEntity, _, errors := deployConfig(dummyClient, testApiMap, NewEntityMap(testApiMap), &conf, deployContext{state: deployState})
+	assert.Assert(t, len(errors) == 0, "there should be no errors (errors: %s)", errors)
+	assert.Equal(t, "known-id", resolvedEntity.Properties[config.IdParameter])
+
+	assert.Equal(t, len(dummyClient.Entries[dashboardApi]), 1)
+	assert.Equal(t, dummyClient.Entries[dashboardApi][0].Name, "new name")
+
+	entry, found := deployState.Get(conf.Environment, conf.Coordinate)
+	assert.Assert(t, found)
+	assert.Equal(t, entry.ObjectId, "known-id")
+	assert.Equal(t, entry.Name, "new name")
+	assert.Equal(t, entry.Checksum, state.Checksum([]byte("{}")))
+}
+
+func TestDeployConfigWithStateFallsBackToNameIfKnownObjectIsGone(t *testing.T) {
+	dummyClient := client.NewDummyClient()
+
+	conf := config.Config{
+		Template:    generateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project1", Type: "dashboard", ConfigId: "dashboard-1"},
+		Environment: "development",
+		Parameters: toParameterMap([]topologysort.ParameterWithName{
+			{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: "name"}},
+		}),
+	}
+
+	deployState := state.New()
+	deployState.Put(conf.Environment, conf.Coordinate, state.Entry{ObjectId: "deleted-id", Name: "name"})
+
+	resolvedEntity, _, errors := deployConfig(dummyClient, testApiMap, NewEntityMap(testApiMap), &conf, deployContext{state: deployState})
+	assert.Assert(t, len(errors) == 0, "there should be no errors (errors: %s)", errors)
+
+	assert.Equal(t, len(dummyClient.Entries[dashboardApi]), 1)
+	createdId := dummyClient.Entries[dashboardApi][0].Id
+	assert.Equal(t, createdId, resolvedEntity.Properties[config.IdParameter])
+
+	entry, found := deployState.Get(conf.Environment, conf.Coordinate)
+	assert.Assert(t, found)
+	assert.Equal(t, entry.ObjectId, createdId)
+}
+
+func TestDeployConfigsRecordsSettingsInState(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().UpsertSettings(gomock.Any()).Return(api.DynatraceEntity{Id: "object-id", Name: "object-id"}, nil)
+
+	sortedConfigs := []config.Config{
+		{
+			Template:    generateDummyTemplate(t),
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "schema", ConfigId: "setting"},
+			Environment: "development",
+			Type:        config.Type{SchemaId: "schema", SchemaVersion: "1.0"},
+			Parameters: config.Parameters{
+				config.ScopeParameter: &value.ValueParameter{Value: "tenant"},
+			},
+		},
+	}
+
+	deployState := state.New()
+	errors := DeployConfigs(c, nil, sortedConfigs, DeployConfigsOptions{State: deployState})
+	assert.Assert(t, len(errors) == 0, "there should be no errors (errors: %s)", errors)
+
+	entry, found := deployState.Get("development", sortedConfigs[0].Coordinate)
+	assert.Assert(t, found)
+	assert.Equal(t, entry.ObjectId, "object-id")
+}
+
+func TestDeployConfigsRecordsReport(t *testing.T) {
+	dummyClient := client.NewDummyClient()
+	sortedConfigs := []config.Config{
+		{
+			Template:    generateDummyTemplate(t),
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "deployed"},
+			Environment: "development",
+			Type:        config.Type{Api: "dashboard"},
+			Parameters: toParameterMap([]topologysort.ParameterWithName{
+				{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: "name"}},
+			}),
+		},
+		{
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "skipped"},
+			Environment: "development",
+			Skip:        true,
+		},
+		{
+			Template:    generateFaultyTemplate(t),
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "failed"},
+			Environment: "development",
+			Type:        config.Type{Api: "dashboard"},
+			Parameters: toParameterMap([]topologysort.ParameterWithName{
+				{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: "other name"}},
+			}),
+		},
+	}
+
+	recorder := report.NewRecorder()
+	errors := DeployConfigs(dummyClient, testApiMap, sortedConfigs, DeployConfigsOptions{ContinueOnErr: true, Report: recorder})
+	assert.Equal(t, len(errors), 1)
+
+	records := recorder.Records()
+	assert.Equal(t, len(records), 3)
+
+	assert.Equal(t, records[0].Coordinate, sortedConfigs[0].Coordinate)
+	assert.Equal(t, records[0].Environment, "development")
+	assert.Equal(t, records[0].Action, report.ActionDeployed)
+	assert.Equal(t, records[0].ObjectId, dummyClient.Entries[dashboardApi][0].Id)
+
+	assert.Equal(t, records[1].Action, report.ActionSkipped)
+
+	assert.Equal(t, records[2].Action, report.ActionFailed)
+	assert.Equal(t, len(records[2].Errors), 1)
+	assert.DeepEqual(t, records[2].Errors[0].Coordinate, &sortedConfigs[2].Coordinate)
+}
+
+func TestDeployConfigsWithSkipUnchanged_SkipsUnchangedConfig(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(dashboardApi).Return([]api.Value{{Id: "existing-id", Name: "name"}}, nil)
+	c.EXPECT().ReadConfigById(dashboardApi, "existing-id").Return([]byte(`{"id":"existing-id","value":1,"metadata":{"clusterVersion":"1.0"}}`), nil)
+
+	conf := dashboardConfig(t, "config", "name")
+	conf.Template = template.CreateTemplateFromString("deploy_test-unchanged", `{"value": 1}`)
+
+	recorder := report.NewRecorder()
+	errors := DeployConfigs(c, testApiMap, []config.Config{conf}, DeployConfigsOptions{SkipUnchanged: true, Report: recorder})
+	assert.Equal(t, len(errors), 0, "there should be no errors (errors: %s)", errors)
+
+	records := recorder.Records()
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, records[0].Action, report.ActionUnchanged)
+	assert.Equal(t, records[0].ObjectId, "existing-id")
+}
+
+func TestDeployConfigsWithSkipUnchanged_UpdatesChangedConfig(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(dashboardApi).Return([]api.Value{{Id: "existing-id", Name: "name"}}, nil)
+	c.EXPECT().ReadConfigById(dashboardApi, "existing-id").Return([]byte(`{"value":2}`), nil)
+	c.EXPECT().UpsertConfigByName(dashboardApi, "name", gomock.Any()).Return(api.DynatraceEntity{Id: "existing-id", Name: "name"}, nil)
+
+	c.EXPECT().UpsertConfigByName(dashboardApi, "new", gomock.Any()).Return(api.DynatraceEntity{Id: "new-id", Name: "new"}, nil)
+
+	changed := dashboardConfig(t, "changed", "name")
+	changed.Template = template.CreateTemplateFromString("deploy_test-changed", `{"value": 1}`)
+
+	recorder := report.NewRecorder()
+	errors := DeployConfigs(c, testApiMap, []config.Config{changed, dashboardConfig(t, "new", "new")}, DeployConfigsOptions{SkipUnchanged: true, Report: recorder})
+	assert.Equal(t, len(errors), 0, "there should be no errors (errors: %s)", errors)
+
+	assert.DeepEqual(t, recorder.CountByAction(), map[report.Action]int{report.ActionUpdated: 1, report.ActionCreated: 1})
+}
+
+func TestDeployConfigsWithSkipUnchanged_UpdatesConfigWithAdditionalFields(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(dashboardApi).Return([]api.Value{{Id: "existing-id", Name: "name"}}, nil)
+	c.EXPECT().ReadConfigById(dashboardApi, "existing-id").Return([]byte(`{"id":"existing-id","value":1,"description":"removed by the update"}`), nil)
+	c.EXPECT().UpsertConfigByName(dashboardApi, "name", gomock.Any()).Return(api.DynatraceEntity{Id: "existing-id", Name: "name"}, nil)
+
+	conf := dashboardConfig(t, "config", "name")
+	conf.Template = template.CreateTemplateFromString("deploy_test-additional-fields", `{"value": 1}`)
+
+	recorder := report.NewRecorder()
+	errors := DeployConfigs(c, testApiMap, []config.Config{conf}, DeployConfigsOptions{SkipUnchanged: true, Report: recorder})
+	assert.Equal(t, len(errors), 0, "there should be no errors (errors: %s)", errors)
+
+	assert.DeepEqual(t, recorder.CountByAction(), map[report.Action]int{report.ActionUpdated: 1})
+}
+
+func TestDeployConfigsWithSkipUnchanged_SkipsUnchangedSingleConfigurationApi(t *testing.T) {
+	singleConfigApi := api.NewSingleConfigurationApi("single", "single", "", false)
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ReadConfigById(singleConfigApi, "").Return([]byte(`{"enabled":true}`), nil)
+
+	conf := config.Config{
+		Template:    template.CreateTemplateFromString("deploy_test-single", `{"enabled": true}`),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "single", ConfigId: "config"},
+		Environment: "env",
+		Type:        config.Type{Api: "single"},
+		Parameters: config.Parameters{
+			config.NameParameter: &parameter.DummyParameter{Value: "name"},
+		},
+	}
+
+	recorder := report.NewRecorder()
+	errors := DeployConfigs(c, api.ApiMap{"single": singleConfigApi}, []config.Config{conf}, DeployConfigsOptions{SkipUnchanged: true, Report: recorder})
+	assert.Equal(t, len(errors), 0, "there should be no errors (errors: %s)", errors)
+	assert.Equal(t, recorder.Records()[0].Action, report.ActionUnchanged)
+}
+
+func TestDeployConfigsWithSkipUnchanged_SkipsUnchangedSetting(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings("schema", gomock.Any()).Return([]client.DownloadSettingsObject{
+		{ObjectId: "object-id", ExternalId: idutils.GenerateExternalID("schema", "setting"), Scope: "tenant", Value: []byte(`{"enabled":true}`)},
+	}, nil)
+
+	conf := config.Config{
+		Template:    template.CreateTemplateFromString("deploy_test-setting", `{"enabled": true}`),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "schema", ConfigId: "setting"},
+		Environment: "env",
+		Type:        config.Type{SchemaId: "schema", SchemaVersion: "1.0"},
+		Parameters: config.Parameters{
+			config.ScopeParameter: &value.ValueParameter{Value: "tenant"},
+		},
+	}
+
+	recorder := report.NewRecorder()
+	errors := DeployConfigs(c, testApiMap, []config.Config{conf}, DeployConfigsOptions{SkipUnchanged: true, Report: recorder})
+	assert.Equal(t, len(errors), 0, "there should be no errors (errors: %s)", errors)
+	assert.Equal(t, recorder.Records()[0].Action, report.ActionUnchanged)
+}
+
+func TestDeployConfigsWithSkipUnchanged_UpdatesSettingInDifferentScope(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings("schema", gomock.Any()).Return([]client.DownloadSettingsObject{
+		{ObjectId: "object-id", ExternalId: idutils.GenerateExternalID("schema", "setting"), Scope: "HOST-1", Value: []byte(`{"enabled":true}`)},
+	}, nil)
+	c.EXPECT().UpsertSettings(gomock.Any()).Return(api.DynatraceEntity{Id: "other-id", Name: "other-id"}, nil)
+
+	conf := config.Config{
+		Template:    template.CreateTemplateFromString("deploy_test-setting-scope", `{"enabled": true}`),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "schema", ConfigId: "setting"},
+		Environment: "env",
+		Type:        config.Type{SchemaId: "schema", SchemaVersion: "1.0"},
+		Parameters: config.Parameters{
+			config.ScopeParameter: &value.ValueParameter{Value: "tenant"},
+		},
+	}
+
+	recorder := report.NewRecorder()
+	errors := DeployConfigs(c, testApiMap, []config.Config{conf}, DeployConfigsOptions{SkipUnchanged: true, Report: recorder})
+	assert.Equal(t, len(errors), 0, "there should be no errors (errors: %s)", errors)
+	assert.Equal(t, recorder.Records()[0].Action, report.ActionCreated)
+}
+
 func toParameterMap(params []topologysort.ParameterWithName) map[string]parameter.Parameter {
 	result := make(map[string]parameter.Parameter)
 
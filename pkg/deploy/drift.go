@@ -0,0 +1,242 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/classic"
+	"sort"
+	"strings"
+)
+
+// DriftStatus describes how the live object of a config differs from the config
+type DriftStatus string
+
+const (
+	DriftStatusInSync  DriftStatus = "in-sync"
+	DriftStatusDrifted DriftStatus = "drifted"
+	DriftStatusMissing DriftStatus = "missing"
+	DriftStatusSkipped DriftStatus = "skipped"
+)
+
+// Drift describes the differences between a config and its live object in an environment
+type Drift struct {
+	Coordinate  coordinate.Coordinate
+	Environment string
+	Status      DriftStatus
+	// Differences between the live object and the rendered config. Only set for DriftStatusDrifted.
+	Differences []json.Difference
+}
+
+// driftDownloadProject is the project name downloaded configs are assigned to. It is not visible to users.
+const driftDownloadProject = "drift"
+
+// DetectDrift compares the given configs against their live objects in the environment of the given client, without
+// modifying anything. The live objects are fetched using the classic downloader and the settings API. If any live
+// object can not be fetched, no drift is reported, as the config would be wrongly reported as missing.
+// NOTE: the given configs need to be sorted, otherwise references cannot be resolved
+func DetectDrift(c client.Client, apis api.ApiMap, sortedConfigs []config.Config) ([]Drift, []error) {
+	lookup, errs := downloadLiveObjects(c, apis, sortedConfigs)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	changes, errs := planConfigs(lookup, NewEnvironmentLookup(c, apis), apis, sortedConfigs)
+
+	result := make([]Drift, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, Drift{
+			Coordinate:  change.Coordinate,
+			Environment: change.Environment,
+			Status:      driftStatusOf(change.Action),
+			Differences: change.Differences,
+		})
+	}
+
+	return result, errs
+}
+
+func driftStatusOf(action PlanAction) DriftStatus {
+	switch action {
+	case PlanActionCreate:
+		return DriftStatusMissing
+	case PlanActionUpdate:
+		return DriftStatusDrifted
+	case PlanActionSkip:
+		return DriftStatusSkipped
+	default:
+		return DriftStatusInSync
+	}
+}
+
+// downloadedLookup looks up live objects in configs downloaded from an environment
+type downloadedLookup struct {
+	// classicConfigs holds the downloaded classic configs per API ID
+	classicConfigs map[string][]downloadedConfig
+	// settingsById holds all downloaded settings objects by their object ID
+	settingsById map[string]liveObject
+	// settingsObjectIds maps the external IDs of settings objects created by monaco to their object ID
+	settingsObjectIds map[string]string
+}
+
+// downloadedConfig is a classic config downloaded from an environment
+type downloadedConfig struct {
+	name string
+	obj  liveObject
+}
+
+// downloadLiveObjects downloads all classic configs and settings objects of the APIs and schemas used by the given
+// configs. Settings objects are listed directly, as the settings downloader does not keep their external ID.
+func downloadLiveObjects(c client.Client, apis api.ApiMap, configs []config.Config) (downloadedLookup, []error) {
+	apisToDownload := api.ApiMap{}
+	schemas := map[string]struct{}{}
+
+	for _, conf := range configs {
+		switch {
+		case conf.Skip || conf.Type.IsEntities():
+			continue
+		case conf.Type.IsSettings():
+			schemas[conf.Type.SchemaId] = struct{}{}
+		default:
+			if a, found := apis[conf.Coordinate.Type]; found {
+				apisToDownload[a.GetId()] = a
+			}
+		}
+	}
+
+	schemaIds := make([]string, 0, len(schemas))
+	for s := range schemas {
+		schemaIds = append(schemaIds, s)
+	}
+	sort.Strings(schemaIds)
+
+	lookup := downloadedLookup{
+		classicConfigs:    map[string][]downloadedConfig{},
+		settingsById:      map[string]liveObject{},
+		settingsObjectIds: map[string]string{},
+	}
+
+	// no filters are applied, as all objects are potential targets of configs
+	downloadedClassic, errs := classic.NewDownloader(c, classic.WithAPIFilters(nil)).DownloadAllWithErrors(apisToDownload, driftDownloadProject)
+	for apiId, downloaded := range downloadedClassic {
+		for _, d := range downloaded {
+			lookup.classicConfigs[apiId] = append(lookup.classicConfigs[apiId], downloadedConfigOf(d))
+		}
+	}
+
+	for _, schemaId := range schemaIds {
+		objects, err := c.ListSettings(schemaId, client.ListSettingsOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fetch settings of schema %q: %w", schemaId, err))
+			continue
+		}
+
+		for _, o := range objects {
+			lookup.settingsById[o.ObjectId] = liveSettingOf(o)
+
+			if strings.HasPrefix(o.ExternalId, idutils.ExternalIDPrefix) {
+				lookup.settingsObjectIds[o.ExternalId] = o.ObjectId
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return downloadedLookup{}, errs
+	}
+
+	return lookup, nil
+}
+
+// downloadedConfigOf converts a downloaded classic config back into its live object. The downloader replaces the
+// name in the payload by a template, which is rendered again for comparison.
+func downloadedConfigOf(d config.Config) downloadedConfig {
+	name := d.Template.Name()
+
+	payload, err := template.Render(d.Template, map[string]interface{}{config.NameParameter: name}, template.Context{Environment: d.Environment, Group: d.Group})
+	if err != nil {
+		log.Debug("Failed to render downloaded config %s of api %s, comparing raw payload: %s", d.Template.Id(), d.Coordinate.Type, err)
+		payload = d.Template.Content()
+	}
+
+	return downloadedConfig{
+		name: name,
+		obj:  liveObject{id: d.Template.Id(), payload: []byte(payload)},
+	}
+}
+
+func (l downloadedLookup) findConfig(theApi api.Api, conf *config.Config, configName string) (liveObject, bool, error) {
+	downloaded := l.classicConfigs[theApi.GetId()]
+
+	if theApi.IsSingleConfigurationApi() {
+		if len(downloaded) == 0 {
+			return liveObject{}, false, nil
+		}
+		return liveObject{payload: downloaded[0].obj.payload}, true, nil
+	}
+
+	var sameName []downloadedConfig
+	for _, d := range downloaded {
+		if d.name == configName {
+			sameName = append(sameName, d)
+		}
+	}
+
+	if theApi.IsNonUniqueNameApi() {
+		entityUuid := conf.Coordinate.ConfigId
+		if !idutils.IsUuid(entityUuid) && !idutils.IsMeId(entityUuid) {
+			entityUuid = idutils.GenerateUuidFromConfigId(conf.Coordinate.Project, conf.Coordinate.ConfigId)
+		}
+
+		for _, d := range downloaded {
+			if d.obj.id == entityUuid {
+				return d.obj, true, nil
+			}
+		}
+
+		if len(sameName) == 1 {
+			return sameName[0].obj, true, nil
+		}
+		return liveObject{}, false, nil
+	}
+
+	if len(sameName) == 0 {
+		return liveObject{}, false, nil
+	}
+	return sameName[0].obj, true, nil
+}
+
+func (l downloadedLookup) findSetting(conf *config.Config) (liveObject, bool, error) {
+	if objectId, found := l.settingsObjectIds[idutils.GenerateExternalID(conf.Type.SchemaId, conf.Coordinate.ConfigId)]; found {
+		if obj, found := l.settingsById[objectId]; found {
+			return obj, true, nil
+		}
+	}
+
+	if conf.OriginObjectId != "" {
+		if obj, found := l.settingsById[conf.OriginObjectId]; found {
+			return obj, true, nil
+		}
+	}
+
+	return liveObject{}, false, nil
+}
@@ -0,0 +1,133 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestDetectDrift(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ListConfigs(dashboardApi).Return([]api.Value{
+		{Id: "in-sync-id", Name: "in sync"},
+		{Id: "drifted-id", Name: "drifted"},
+	}, nil)
+	c.EXPECT().ReadConfigById(dashboardApi, "in-sync-id").Return([]byte(`{"id": "in-sync-id", "name": "in sync", "value": 1, "metadata": {}}`), nil)
+	c.EXPECT().ReadConfigById(dashboardApi, "drifted-id").Return([]byte(`{"id": "drifted-id", "name": "drifted", "value": 2}`), nil)
+
+	settingsObjects := []client.DownloadSettingsObject{
+		{
+			ObjectId:      "setting-id",
+			SchemaId:      "builtin:schema",
+			SchemaVersion: "1.0",
+			Scope:         "tenant",
+			ExternalId:    idutils.GenerateExternalID("builtin:schema", "setting"),
+			Value:         []byte(`{"enabled": false}`),
+		},
+	}
+	c.EXPECT().ListSettings("builtin:schema", gomock.Any()).Return(settingsObjects, nil)
+
+	classicConfig := func(id string, name string) config.Config {
+		return config.Config{
+			Template:    template.CreateTemplateFromString(id, `{"name": "{{.name}}", "value": 1}`),
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: id},
+			Environment: "env",
+			Type:        config.Type{Api: "dashboard"},
+			Parameters: config.Parameters{
+				config.NameParameter: &value.ValueParameter{Value: name},
+			},
+		}
+	}
+
+	sortedConfigs := []config.Config{
+		classicConfig("in-sync", "in sync"),
+		classicConfig("drifted", "drifted"),
+		classicConfig("missing", "missing"),
+		{
+			Template:    template.CreateTemplateFromString("setting", `{"enabled": true}`),
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "builtin:schema", ConfigId: "setting"},
+			Environment: "env",
+			Type:        config.Type{SchemaId: "builtin:schema", SchemaVersion: "1.0"},
+			Parameters: config.Parameters{
+				config.ScopeParameter: &value.ValueParameter{Value: "tenant"},
+			},
+		},
+		{
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "skipped"},
+			Environment: "env",
+			Skip:        true,
+		},
+	}
+
+	drifts, errs := DetectDrift(c, testApiMap, sortedConfigs)
+	assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+
+	assert.DeepEqual(t, drifts, []Drift{
+		{Coordinate: sortedConfigs[0].Coordinate, Environment: "env", Status: DriftStatusInSync},
+		{Coordinate: sortedConfigs[1].Coordinate, Environment: "env", Status: DriftStatusDrifted, Differences: []json.Difference{{Path: "value", Expected: float64(1), Actual: float64(2)}}},
+		{Coordinate: sortedConfigs[2].Coordinate, Environment: "env", Status: DriftStatusMissing},
+		{Coordinate: sortedConfigs[3].Coordinate, Environment: "env", Status: DriftStatusDrifted, Differences: []json.Difference{{Path: "enabled", Expected: true, Actual: false}}},
+		{Coordinate: sortedConfigs[4].Coordinate, Environment: "env", Status: DriftStatusSkipped},
+	})
+}
+
+func TestDetectDrift_ReturnsErrorsOfFailedDownloads(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ListConfigs(dashboardApi).Return([]api.Value{{Id: "id", Name: "name"}}, nil)
+	c.EXPECT().ReadConfigById(dashboardApi, "id").Return(nil, errors.New("connection reset"))
+	c.EXPECT().ListSettings("builtin:schema", gomock.Any()).Return(nil, errors.New("internal server error"))
+
+	sortedConfigs := []config.Config{
+		{
+			Template:    template.CreateTemplateFromString("dashboard", `{"name": "{{.name}}"}`),
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "dashboard"},
+			Environment: "env",
+			Type:        config.Type{Api: "dashboard"},
+			Parameters: config.Parameters{
+				config.NameParameter: &value.ValueParameter{Value: "name"},
+			},
+		},
+		{
+			Template:    template.CreateTemplateFromString("setting", `{"enabled": true}`),
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "builtin:schema", ConfigId: "setting"},
+			Environment: "env",
+			Type:        config.Type{SchemaId: "builtin:schema", SchemaVersion: "1.0"},
+			Parameters: config.Parameters{
+				config.ScopeParameter: &value.ValueParameter{Value: "tenant"},
+			},
+		},
+	}
+
+	drifts, errs := DetectDrift(c, testApiMap, sortedConfigs)
+	assert.Equal(t, len(drifts), 0, "failed downloads must not be reported as missing configs")
+	assert.Equal(t, len(errs), 2)
+	assert.ErrorContains(t, errs[0], "connection reset")
+	assert.ErrorContains(t, errs[1], "internal server error")
+}
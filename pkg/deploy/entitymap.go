@@ -18,10 +18,13 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"sync"
 )
 
-// EntityMap is holds information about known entity names and entities already resolved by monaco
+// EntityMap is holds information about known entity names and entities already resolved by monaco.
+// It is safe to be used concurrently by multiple goroutines.
 type EntityMap struct {
+	lock             sync.RWMutex
 	resolvedEntities parameter.ResolvedEntities
 	knownEntityNames map[string]map[string]struct{}
 }
@@ -41,6 +44,9 @@ func NewEntityMap(apis api.ApiMap) *EntityMap {
 
 // PutResolved adds a resolved entity to the entity map
 func (k *EntityMap) PutResolved(coordinate coordinate.Coordinate, resolvedEntity parameter.ResolvedEntity) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
 	// memorize resolved entity
 	k.resolvedEntities[coordinate] = resolvedEntity
 
@@ -57,13 +63,50 @@ func (k *EntityMap) PutResolved(coordinate coordinate.Coordinate, resolvedEntity
 	k.knownEntityNames[coordinate.Type][resolvedEntity.EntityName] = struct{}{}
 }
 
-// Resolved gives back the currently resolved entities
+// Resolved gives back a copy of the currently resolved entities
 func (k *EntityMap) Resolved() parameter.ResolvedEntities {
-	return k.resolvedEntities
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
+	resolved := make(parameter.ResolvedEntities, len(k.resolvedEntities))
+	for c, e := range k.resolvedEntities {
+		resolved[c] = e
+	}
+	return resolved
 }
 
 // Known checks if an entity name was already resolved
 func (k *EntityMap) Known(entityType string, entityName string) bool {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
 	_, found := k.knownEntityNames[entityType][entityName]
 	return found
 }
+
+// ReserveName atomically checks if an entity name was already resolved or reserved and, if not, reserves it.
+// It returns false if the name is already taken. Reserving the name before deploying prevents configs
+// deployed in parallel from using the same name.
+func (k *EntityMap) ReserveName(entityType string, entityName string) bool {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if _, found := k.knownEntityNames[entityType][entityName]; found {
+		return false
+	}
+
+	if _, found := k.knownEntityNames[entityType]; !found {
+		k.knownEntityNames[entityType] = make(map[string]struct{})
+	}
+	k.knownEntityNames[entityType][entityName] = struct{}{}
+	return true
+}
+
+// ReleaseName releases a name reserved by ReserveName, e.g. if the config reserving it failed to deploy. This allows
+// other configs to use the name again, instead of failing as duplicates of a config which was never deployed.
+func (k *EntityMap) ReleaseName(entityType string, entityName string) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	delete(k.knownEntityNames[entityType], entityName)
+}
@@ -113,3 +113,34 @@ func TestEntityMap_PutResolved(t *testing.T) {
 	})
 
 }
+
+func TestEntityMap_ReserveName(t *testing.T) {
+	entityMap := NewEntityMap(api.ApiMap{"dashboard": api.NewStandardApi("dashboard", "dashboard", false, "dashboard-v2", false)})
+
+	assert.Equal(t, entityMap.ReserveName("dashboard", "entityName"), true)
+	assert.Equal(t, entityMap.Known("dashboard", "entityName"), true)
+	assert.Equal(t, entityMap.ReserveName("dashboard", "entityName"), false)
+	assert.Equal(t, entityMap.ReserveName("type", "entityName"), true)
+}
+
+func TestEntityMap_ReleaseName(t *testing.T) {
+	entityMap := NewEntityMap(api.ApiMap{"dashboard": api.NewStandardApi("dashboard", "dashboard", false, "dashboard-v2", false)})
+
+	assert.Equal(t, entityMap.ReserveName("dashboard", "entityName"), true)
+	entityMap.ReleaseName("dashboard", "entityName")
+	assert.Equal(t, entityMap.Known("dashboard", "entityName"), false)
+	assert.Equal(t, entityMap.ReserveName("dashboard", "entityName"), true)
+}
+
+func TestEntityMap_ReserveNameOfResolvedEntity(t *testing.T) {
+	c1 := coordinate.Coordinate{
+		Project:  "project",
+		Type:     "type",
+		ConfigId: "configID",
+	}
+
+	entityMap := NewEntityMap(api.ApiMap{"dashboard": api.NewStandardApi("dashboard", "dashboard", false, "dashboard-v2", false)})
+	entityMap.PutResolved(c1, parameter.ResolvedEntity{EntityName: "entityName", Coordinate: c1})
+
+	assert.Equal(t, entityMap.ReserveName("type", "entityName"), false)
+}
@@ -0,0 +1,239 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"sync"
+)
+
+// liveObject holds the currently deployed state of a config in a Dynatrace environment
+type liveObject struct {
+	// id is the Dynatrace object ID of the deployed config
+	id string
+	// payload is the JSON payload of the deployed config. For settings this is only the settings value.
+	payload []byte
+	// scope, schemaVersion and externalId are only set for settings
+	scope         string
+	schemaVersion string
+	externalId    string
+}
+
+// liveLookup finds the objects configs are deployed to in an environment
+type liveLookup interface {
+	// findConfig finds the object of a classic config, see clientLookup.findConfig
+	findConfig(theApi api.Api, conf *config.Config, configName string) (liveObject, bool, error)
+	// findSetting finds the object of a settings config, see clientLookup.findSetting
+	findSetting(conf *config.Config) (liveObject, bool, error)
+}
+
+// clientLookup looks up live objects via the client of an environment. Each API and settings schema is only listed
+// once and the configs are looked up in the cached list, so the lookup should not outlive a single deployment of an
+// environment. The lookup is safe to be used by parallel deployments.
+type clientLookup struct {
+	c client.Client
+
+	configs  listCache[api.Value]
+	settings listCache[client.DownloadSettingsObject]
+}
+
+func newClientLookup(c client.Client) *clientLookup {
+	return &clientLookup{
+		c:        c,
+		configs:  listCache[api.Value]{results: map[string]*listResult[api.Value]{}},
+		settings: listCache[client.DownloadSettingsObject]{results: map[string]*listResult[client.DownloadSettingsObject]{}},
+	}
+}
+
+// listCache caches the result of listing all objects of an API or settings schema
+type listCache[T any] struct {
+	// lock guards results. It is not held while listing, so that lists of different keys do not wait for each other.
+	lock    sync.Mutex
+	results map[string]*listResult[T]
+}
+
+// listResult is the cached result of listing the objects of one key. done is closed once values and err are set,
+// so that concurrent lookups of the same key wait for the first one instead of listing again.
+type listResult[T any] struct {
+	done   chan struct{}
+	values []T
+	err    error
+}
+
+// get returns the cached result of the given key, or lists it using the given function
+func (c *listCache[T]) get(key string, list func() ([]T, error)) ([]T, error) {
+	c.lock.Lock()
+	if result, found := c.results[key]; found {
+		c.lock.Unlock()
+		<-result.done
+		return result.values, result.err
+	}
+
+	result := &listResult[T]{done: make(chan struct{})}
+	c.results[key] = result
+	c.lock.Unlock()
+
+	result.values, result.err = list()
+
+	if result.err != nil {
+		// failed lists are not cached, so that they are retried by later configs
+		c.lock.Lock()
+		delete(c.results, key)
+		c.lock.Unlock()
+	}
+	close(result.done)
+
+	return result.values, result.err
+}
+
+func (l *clientLookup) listConfigs(theApi api.Api) ([]api.Value, error) {
+	return l.configs.get(theApi.GetId(), func() ([]api.Value, error) {
+		return l.c.ListConfigs(theApi)
+	})
+}
+
+func (l *clientLookup) listSettings(schemaId string) ([]client.DownloadSettingsObject, error) {
+	return l.settings.get(schemaId, func() ([]client.DownloadSettingsObject, error) {
+		objects, err := l.c.ListSettings(schemaId, client.ListSettingsOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list settings of schema %q: %w", schemaId, err)
+		}
+		return objects, nil
+	})
+}
+
+// findConfig searches the classic config API for the object the given config would be deployed to.
+// It follows the same rules as the upsert functions of the client. If no object exists, found is false.
+func (l *clientLookup) findConfig(theApi api.Api, conf *config.Config, configName string) (obj liveObject, found bool, err error) {
+	var id string
+
+	switch {
+	case theApi.IsSingleConfigurationApi():
+		found = true
+	case theApi.IsNonUniqueNameApi():
+		id, found, err = l.findNonUniqueNameConfigId(theApi, conf, configName)
+	default:
+		id, found, err = l.findConfigIdByName(theApi, configName)
+	}
+
+	if err != nil || !found {
+		return liveObject{}, false, err
+	}
+
+	return l.readConfig(theApi, id)
+}
+
+// readConfig reads the object with the given ID
+func (l *clientLookup) readConfig(theApi api.Api, id string) (liveObject, bool, error) {
+	payload, err := l.c.ReadConfigById(theApi, id)
+	if err != nil {
+		return liveObject{}, false, err
+	}
+
+	return liveObject{id: id, payload: payload}, true, nil
+}
+
+// findConfigIdByName finds the ID of a config by its name, by mirroring the logic of
+// client.ConfigClient.ConfigExistsByName
+func (l *clientLookup) findConfigIdByName(theApi api.Api, configName string) (id string, found bool, err error) {
+	values, err := l.listConfigs(theApi)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, v := range values {
+		if v.Name == configName || escapedName(v) == configName {
+			return v.Id, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// escapedName returns the name of the value escaped like names of configs are, as names are compared escaped by the
+// client as well
+func escapedName(v api.Value) string {
+	escaped, err := template.EscapeSpecialCharactersInValue(v.Name, template.FullStringEscapeFunction)
+	if err != nil {
+		return v.Name
+	}
+	return escaped.(string)
+}
+
+// findNonUniqueNameConfigId finds the ID of a config of a non-unique-name API, by mirroring the logic of
+// client.ConfigClient.UpsertConfigByNonUniqueNameAndId
+func (l *clientLookup) findNonUniqueNameConfigId(theApi api.Api, conf *config.Config, configName string) (id string, found bool, err error) {
+	entityUuid := conf.Coordinate.ConfigId
+	if !idutils.IsUuid(entityUuid) && !idutils.IsMeId(entityUuid) {
+		entityUuid = idutils.GenerateUuidFromConfigId(conf.Coordinate.Project, conf.Coordinate.ConfigId)
+	}
+
+	values, err := l.listConfigs(theApi)
+	if err != nil {
+		return "", false, err
+	}
+
+	var sameName []api.Value
+	for _, v := range values {
+		if v.Id == entityUuid {
+			return v.Id, true, nil
+		}
+		if v.Name == configName {
+			sameName = append(sameName, v)
+		}
+	}
+
+	if len(sameName) == 1 {
+		return sameName[0].Id, true, nil
+	}
+
+	return "", false, nil
+}
+
+// findSetting searches for the settings object the given config would be deployed to, using
+// the monaco external ID or the origin object ID of the config. If no object exists, found is false.
+func (l *clientLookup) findSetting(conf *config.Config) (obj liveObject, found bool, err error) {
+	externalId := idutils.GenerateExternalID(conf.Type.SchemaId, conf.Coordinate.ConfigId)
+
+	objects, err := l.listSettings(conf.Type.SchemaId)
+	if err != nil {
+		return liveObject{}, false, err
+	}
+
+	for _, o := range objects {
+		if o.ExternalId == externalId {
+			return liveSettingOf(o), true, nil
+		}
+	}
+
+	if conf.OriginObjectId == "" {
+		return liveObject{}, false, nil
+	}
+
+	for _, o := range objects {
+		if o.ObjectId == conf.OriginObjectId {
+			return liveSettingOf(o), true, nil
+		}
+	}
+	return liveObject{}, false, nil
+}
+
+func liveSettingOf(o client.DownloadSettingsObject) liveObject {
+	return liveObject{id: o.ObjectId, payload: o.Value, scope: o.Scope, schemaVersion: o.SchemaVersion, externalId: o.ExternalId}
+}
@@ -0,0 +1,136 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"sync"
+)
+
+// EnvironmentLookup finds existing objects in the environment configs are deployed to. It is used to resolve
+// parameters looking up entities or configs.
+type EnvironmentLookup interface {
+	parameter.EntityLookup
+	parameter.ConfigLookup
+}
+
+// clientEnvironmentLookup looks up objects via the client of an environment. The result of each entity selector
+// is cached, so configs using the same selector only query the environment once per deployment.
+type clientEnvironmentLookup struct {
+	client client.Client
+	apis   api.ApiMap
+
+	// entityLock guards entityCache, as the lookup is shared by all configs of parallel deployments. It is not held
+	// while querying the environment, so that lookups of different selectors do not wait for each other.
+	entityLock  sync.Mutex
+	entityCache map[string]*entityLookupResult
+}
+
+// entityLookupResult is the cached result of looking up an entity selector. done is closed once ids and err are set,
+// so that concurrent lookups of the same selector wait for the first one instead of querying the environment again.
+type entityLookupResult struct {
+	done chan struct{}
+	ids  []string
+	err  error
+}
+
+var _ EnvironmentLookup = (*clientEnvironmentLookup)(nil)
+
+// NewEnvironmentLookup returns a lookup querying the given client. Results are cached for the lifetime of the
+// returned lookup, which should therefore not outlive a single deployment of an environment.
+func NewEnvironmentLookup(c client.Client, apis api.ApiMap) EnvironmentLookup {
+	return &clientEnvironmentLookup{
+		client:      c,
+		apis:        apis,
+		entityCache: map[string]*entityLookupResult{},
+	}
+}
+
+func (l *clientEnvironmentLookup) LookupEntityIds(entitySelector string) ([]string, error) {
+	l.entityLock.Lock()
+	if result, found := l.entityCache[entitySelector]; found {
+		l.entityLock.Unlock()
+		<-result.done
+		return result.ids, result.err
+	}
+
+	result := &entityLookupResult{done: make(chan struct{})}
+	l.entityCache[entitySelector] = result
+	l.entityLock.Unlock()
+
+	log.Debug("Looking up entities matching %s", entitySelector)
+	result.ids, result.err = l.client.ListEntityIds(entitySelector)
+
+	if result.err != nil {
+		// failed lookups are not cached, so that they are retried by later configs
+		l.entityLock.Lock()
+		delete(l.entityCache, entitySelector)
+		l.entityLock.Unlock()
+	}
+	close(result.done)
+
+	return result.ids, result.err
+}
+
+func (l *clientEnvironmentLookup) LookupConfigId(apiId string, name string) (string, bool, error) {
+	theApi, found := l.apis[apiId]
+	if !found {
+		return "", false, fmt.Errorf("unknown api `%s`", apiId)
+	}
+
+	exists, id, err := l.client.ConfigExistsByName(theApi, name)
+	if err != nil {
+		return "", false, err
+	}
+
+	return id, exists, nil
+}
+
+func (l *clientEnvironmentLookup) LookupSettingIds(schemaId string, scope string, filter func(value []byte) bool) ([]string, error) {
+	objects, err := l.client.ListSettings(schemaId, client.ListSettingsOptions{
+		Filter: func(o client.DownloadSettingsObject) bool {
+			return (scope == "" || o.Scope == scope) && filter(o.Value)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(objects))
+	for i, o := range objects {
+		ids[i] = o.ObjectId
+	}
+	return ids, nil
+}
+
+// dryRunEnvironmentLookup resolves config lookups to placeholder IDs, as configs not managed by monaco are unknown
+// to the client used for offline dry-runs. Entity lookups are passed on to the client, which returns placeholders
+// itself. Online dry-runs use the clientEnvironmentLookup instead, so that missing objects are reported.
+type dryRunEnvironmentLookup struct {
+	EnvironmentLookup
+}
+
+func (l dryRunEnvironmentLookup) LookupConfigId(apiId string, name string) (string, bool, error) {
+	return idutils.GenerateUuidFromName(apiId + "/" + name), true, nil
+}
+
+func (l dryRunEnvironmentLookup) LookupSettingIds(schemaId string, scope string, _ func(value []byte) bool) ([]string, error) {
+	return []string{idutils.GenerateUuidFromName(schemaId + "/" + scope)}, nil
+}
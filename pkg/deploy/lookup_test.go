@@ -0,0 +1,202 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/configlookup"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/entitylookup"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+	"time"
+)
+
+func hostDashboardConfig(id string) config.Config {
+	return config.Config{
+		Template:    template.CreateTemplateFromString("dashboard-"+id, `{"host": "{{ .host }}"}`),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: id},
+		Environment: "env",
+		Type:        config.Type{Api: "dashboard"},
+		Parameters: config.Parameters{
+			config.NameParameter: &parameter.DummyParameter{Value: id},
+			"host":               entitylookup.New("HOST", "my-host", nil),
+		},
+	}
+}
+
+func TestDeployConfigs_LooksUpEntitiesOncePerSelector(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ListEntityIds(`type("HOST"),entityName.equals("my-host")`).Return([]string{"HOST-1234567890ABCDEF"}, nil).Times(1)
+	c.EXPECT().UpsertConfigByName(dashboardApi, "first", []byte(`{"host": "HOST-1234567890ABCDEF"}`)).Return(api.DynatraceEntity{Id: "first-id", Name: "first"}, nil)
+	c.EXPECT().UpsertConfigByName(dashboardApi, "second", []byte(`{"host": "HOST-1234567890ABCDEF"}`)).Return(api.DynatraceEntity{Id: "second-id", Name: "second"}, nil)
+
+	errs := DeployConfigs(c, testApiMap, []config.Config{hostDashboardConfig("first"), hostDashboardConfig("second")}, DeployConfigsOptions{})
+	assert.Equal(t, len(errs), 0, "expected no errors: %v", errs)
+}
+
+func TestEnvironmentLookup_DoesNotBlockLookupsOfOtherSelectors(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	release := make(chan struct{})
+	c.EXPECT().ListEntityIds("slow").DoAndReturn(func(string) ([]string, error) {
+		<-release
+		return []string{"HOST-1"}, nil
+	}).Times(1)
+	c.EXPECT().ListEntityIds("fast").Return([]string{"HOST-2"}, nil).Times(1)
+
+	lookup := NewEnvironmentLookup(c, testApiMap)
+
+	slowResults := make(chan []string, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			ids, _ := lookup.LookupEntityIds("slow")
+			slowResults <- ids
+		}()
+	}
+
+	fastResult := make(chan []string)
+	go func() {
+		ids, _ := lookup.LookupEntityIds("fast")
+		fastResult <- ids
+	}()
+
+	select {
+	case ids := <-fastResult:
+		assert.DeepEqual(t, ids, []string{"HOST-2"})
+	case <-time.After(5 * time.Second):
+		t.Fatal("lookup of other selector is blocked by a pending lookup")
+	}
+
+	close(release)
+	assert.DeepEqual(t, <-slowResults, []string{"HOST-1"})
+	assert.DeepEqual(t, <-slowResults, []string{"HOST-1"})
+}
+
+func TestEnvironmentLookup_DoesNotCacheErrors(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	gomock.InOrder(
+		c.EXPECT().ListEntityIds("selector").Return(nil, errors.New("failed")),
+		c.EXPECT().ListEntityIds("selector").Return([]string{"HOST-1"}, nil),
+	)
+
+	lookup := NewEnvironmentLookup(c, testApiMap)
+
+	_, err := lookup.LookupEntityIds("selector")
+	assert.ErrorContains(t, err, "failed")
+
+	ids, err := lookup.LookupEntityIds("selector")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ids, []string{"HOST-1"})
+}
+
+func TestDeployConfigs_LooksUpPlaceholderEntitiesDuringDryRun(t *testing.T) {
+	c := client.NewDummyClient()
+
+	errs := DeployConfigs(c, testApiMap, []config.Config{hostDashboardConfig("first")}, DeployConfigsOptions{DryRun: true})
+	assert.Equal(t, len(errs), 0, "expected no errors: %v", errs)
+
+	ids, err := c.ListEntityIds(`type("HOST"),entityName.equals("my-host")`)
+	assert.NilError(t, err)
+	assert.Equal(t, len(ids), 1)
+	assert.Assert(t, idutils.IsMeId(ids[0]), "placeholder %q is not a valid entity ID", ids[0])
+}
+
+func mzDashboardConfig(lookup parameter.Parameter) config.Config {
+	return config.Config{
+		Template:    template.CreateTemplateFromString("dashboard-mz", `{"mz": "{{ .mz }}"}`),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "mz"},
+		Environment: "env",
+		Type:        config.Type{Api: "dashboard"},
+		Parameters: config.Parameters{
+			config.NameParameter: &parameter.DummyParameter{Value: "mz"},
+			"mz":                 lookup,
+		},
+	}
+}
+
+func TestDeployConfigs_LooksUpConfigsByName(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ConfigExistsByName(dashboardApi, "Team A").Return(true, "1234", nil)
+	c.EXPECT().UpsertConfigByName(dashboardApi, "mz", []byte(`{"mz": "1234"}`)).Return(api.DynatraceEntity{Id: "mz-id", Name: "mz"}, nil)
+
+	errs := DeployConfigs(c, testApiMap, []config.Config{mzDashboardConfig(configlookup.NewConfig("dashboard", "Team A"))}, DeployConfigsOptions{})
+	assert.Equal(t, len(errs), 0, "expected no errors: %v", errs)
+}
+
+func TestDeployConfigs_LooksUpSettingsByFilter(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ListSettings("builtin:management-zones", gomock.Any()).DoAndReturn(func(_ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		var result []client.DownloadSettingsObject
+		for _, o := range []client.DownloadSettingsObject{
+			{ObjectId: "object-a", Scope: "environment", Value: []byte(`{"name": "Team A"}`)},
+			{ObjectId: "object-b", Scope: "environment", Value: []byte(`{"name": "Team B"}`)},
+		} {
+			if opts.Filter(o) {
+				result = append(result, o)
+			}
+		}
+		return result, nil
+	})
+	c.EXPECT().UpsertConfigByName(dashboardApi, "mz", []byte(`{"mz": "object-b"}`)).Return(api.DynatraceEntity{Id: "mz-id", Name: "mz"}, nil)
+
+	lookup := configlookup.NewSetting("builtin:management-zones", "environment", map[string]interface{}{"name": "Team B"})
+	errs := DeployConfigs(c, testApiMap, []config.Config{mzDashboardConfig(lookup)}, DeployConfigsOptions{})
+	assert.Equal(t, len(errs), 0, "expected no errors: %v", errs)
+}
+
+func TestDeployConfigs_FailsIfLookedUpConfigDoesNotExist(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ConfigExistsByName(dashboardApi, "Team A").Return(false, "", nil)
+
+	errs := DeployConfigs(c, testApiMap, []config.Config{mzDashboardConfig(configlookup.NewConfig("dashboard", "Team A"))}, DeployConfigsOptions{})
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorContains(t, errs[0], "no `dashboard` config named `Team A` exists")
+}
+
+func TestDeployConfigs_LooksUpPlaceholderConfigsDuringDryRun(t *testing.T) {
+	c := client.NewDummyClient()
+
+	errs := DeployConfigs(c, testApiMap, []config.Config{
+		mzDashboardConfig(configlookup.NewConfig("dashboard", "Team A")),
+	}, DeployConfigsOptions{DryRun: true})
+	assert.Equal(t, len(errs), 0, "expected no errors: %v", errs)
+}
+
+func TestDeployConfigs_LooksUpConfigsDuringOnlineDryRun(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ConfigExistsByName(dashboardApi, "Team A").Return(false, "", nil)
+
+	errs := DeployConfigs(c, testApiMap, []config.Config{
+		mzDashboardConfig(configlookup.NewConfig("dashboard", "Team A")),
+	}, DeployConfigsOptions{DryRun: true, Online: true})
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorContains(t, errs[0], "no `dashboard` config named `Team A` exists")
+}
@@ -0,0 +1,171 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+)
+
+// PlanAction describes what a deployment would do with a config
+type PlanAction string
+
+const (
+	PlanActionCreate    PlanAction = "create"
+	PlanActionUpdate    PlanAction = "update"
+	PlanActionUnchanged PlanAction = "unchanged"
+	PlanActionSkip      PlanAction = "skip"
+)
+
+// PlannedChange describes the action a deployment would take for a single config
+type PlannedChange struct {
+	Coordinate  coordinate.Coordinate
+	Environment string
+	Action      PlanAction
+	// Differences between the live object and the rendered config. Only set for PlanActionUpdate.
+	Differences []json.Difference
+}
+
+// HasChanges returns whether the given planned change would modify the environment
+func (p PlannedChange) HasChanges() bool {
+	return p.Action == PlanActionCreate || p.Action == PlanActionUpdate
+}
+
+// PlanConfigs calculates the changes a deployment of the given configs would apply to the environment of the
+// given client, without modifying anything. References to configs that do not exist yet are resolved to
+// placeholder IDs.
+// NOTE: the given configs need to be sorted, otherwise references cannot be resolved
+func PlanConfigs(c client.Client, apis api.ApiMap, sortedConfigs []config.Config) ([]PlannedChange, []error) {
+	return planConfigs(newClientLookup(c), NewEnvironmentLookup(c, apis), apis, sortedConfigs)
+}
+
+func planConfigs(lookup liveLookup, envLookup EnvironmentLookup, apis api.ApiMap, sortedConfigs []config.Config) ([]PlannedChange, []error) {
+	entityMap := NewEntityMap(apis)
+	var changes []PlannedChange
+	var errors []error
+
+	for _, conf := range sortedConfigs {
+		conf := conf // to avoid implicit memory aliasing (gosec G601)
+
+		if conf.Skip {
+			entityMap.PutResolved(conf.Coordinate, parameter.ResolvedEntity{
+				EntityName: conf.Coordinate.ConfigId,
+				Coordinate: conf.Coordinate,
+				Properties: parameter.Properties{},
+				Skip:       true,
+			})
+			changes = append(changes, PlannedChange{Coordinate: conf.Coordinate, Environment: conf.Environment, Action: PlanActionSkip})
+			continue
+		}
+
+		if conf.Type.IsEntities() {
+			continue
+		}
+
+		change, entity, planErrors := planConfig(lookup, envLookup, apis, entityMap, &conf)
+		if planErrors != nil {
+			for _, err := range planErrors {
+				errors = append(errors, fmt.Errorf("failed to plan config %s: %w", conf.Coordinate, err))
+			}
+			continue
+		}
+
+		entityMap.PutResolved(entity.Coordinate, entity)
+		changes = append(changes, change)
+	}
+
+	return changes, errors
+}
+
+func planConfig(lookup liveLookup, envLookup EnvironmentLookup, apis api.ApiMap, entityMap *EntityMap, conf *config.Config) (PlannedChange, parameter.ResolvedEntity, []error) {
+	properties, errors := resolveProperties(conf, entityMap.Resolved(), envLookup)
+	if len(errors) > 0 {
+		return PlannedChange{}, parameter.ResolvedEntity{}, errors
+	}
+
+	var live liveObject
+	var found bool
+	var name string
+
+	if conf.Type.IsSettings() {
+		if _, err := extractScope(properties); err != nil {
+			return PlannedChange{}, parameter.ResolvedEntity{}, []error{err}
+		}
+
+		var err error
+		if live, found, err = lookup.findSetting(conf); err != nil {
+			return PlannedChange{}, parameter.ResolvedEntity{}, []error{newConfigDeployErr(conf, err.Error())}
+		}
+		name = live.id
+	} else {
+		theApi := apis[conf.Coordinate.Type]
+		if theApi == nil {
+			return PlannedChange{}, parameter.ResolvedEntity{}, []error{fmt.Errorf("unknown api `%s`. this is most likely a bug", conf.Type.Api)}
+		}
+
+		var err error
+		if name, err = extractConfigName(conf, properties); err != nil {
+			return PlannedChange{}, parameter.ResolvedEntity{}, []error{err}
+		}
+
+		if live, found, err = lookup.findConfig(theApi, conf, name); err != nil {
+			return PlannedChange{}, parameter.ResolvedEntity{}, []error{newConfigDeployErr(conf, err.Error())}
+		}
+	}
+
+	renderedConfig, err := conf.Render(properties)
+	if err != nil {
+		return PlannedChange{}, parameter.ResolvedEntity{}, []error{err}
+	}
+
+	change := PlannedChange{
+		Coordinate:  conf.Coordinate,
+		Environment: conf.Environment,
+		Action:      PlanActionCreate,
+	}
+
+	if found {
+		differences, err := differencesTo(renderedConfig, live, !conf.Type.IsSettings())
+		if err != nil {
+			return PlannedChange{}, parameter.ResolvedEntity{}, []error{newConfigDeployErr(conf, err.Error())}
+		}
+
+		change.Action = PlanActionUnchanged
+		if len(differences) > 0 {
+			change.Action = PlanActionUpdate
+			change.Differences = differences
+		}
+	} else {
+		// the object does not exist yet, so we do not know its ID. References to it are resolved to a placeholder.
+		live.id = fmt.Sprintf("planned-%s", conf.Coordinate.ConfigId)
+		if name == "" {
+			name = live.id
+		}
+	}
+
+	properties[config.IdParameter] = live.id
+	properties[config.NameParameter] = name
+
+	return change, parameter.ResolvedEntity{
+		EntityName: name,
+		Coordinate: conf.Coordinate,
+		Properties: properties,
+	}, nil
+}
@@ -0,0 +1,165 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestPlanConfigs_ClassicConfigs(t *testing.T) {
+	tests := []struct {
+		name            string
+		exists          bool
+		livePayload     string
+		wantAction      PlanAction
+		wantDifferences []json.Difference
+	}{
+		{
+			name:       "not existing config is created",
+			exists:     false,
+			wantAction: PlanActionCreate,
+		},
+		{
+			name:        "existing equal config is unchanged",
+			exists:      true,
+			livePayload: `{"id": "live-id", "name": "test", "metadata": {"configurationVersions": [1]}}`,
+			wantAction:  PlanActionUnchanged,
+		},
+		{
+			name:            "existing different config is updated",
+			exists:          true,
+			livePayload:     `{"id": "live-id", "name": "changed in ui"}`,
+			wantAction:      PlanActionUpdate,
+			wantDifferences: []json.Difference{{Path: "name", Expected: "test", Actual: "changed in ui"}},
+		},
+		{
+			name:            "existing config with additional field is updated",
+			exists:          true,
+			livePayload:     `{"id": "live-id", "name": "test", "description": "removed by the update"}`,
+			wantAction:      PlanActionUpdate,
+			wantDifferences: []json.Difference{{Path: "description", Expected: nil, Actual: "removed by the update"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := client.NewMockClient(gomock.NewController(t))
+			if tt.exists {
+				c.EXPECT().ListConfigs(dashboardApi).Return([]api.Value{{Id: "live-id", Name: "test"}}, nil)
+				c.EXPECT().ReadConfigById(dashboardApi, "live-id").Return([]byte(tt.livePayload), nil)
+			} else {
+				c.EXPECT().ListConfigs(dashboardApi).Return([]api.Value{{Id: "other-id", Name: "other"}}, nil)
+			}
+
+			conf := config.Config{
+				Template:    template.CreateTemplateFromString("template", `{"name": "{{ .name }}"}`),
+				Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "dashboard"},
+				Environment: "development",
+				Type:        config.Type{Api: "dashboard"},
+				Parameters:  config.Parameters{config.NameParameter: &value.ValueParameter{Value: "test"}},
+			}
+
+			changes, errs := PlanConfigs(c, testApiMap, []config.Config{conf})
+
+			assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+			assert.DeepEqual(t, changes, []PlannedChange{{
+				Coordinate:  conf.Coordinate,
+				Environment: "development",
+				Action:      tt.wantAction,
+				Differences: tt.wantDifferences,
+			}})
+		})
+	}
+}
+
+func TestPlanConfigs_Settings(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings("builtin:alerting.profile", gomock.Any()).Return([]client.DownloadSettingsObject{
+		{ObjectId: "object-id", ExternalId: idutils.GenerateExternalID("builtin:alerting.profile", "profile"), Value: []byte(`{"enabled": false}`)},
+	}, nil)
+
+	conf := config.Config{
+		Template:   template.CreateTemplateFromString("template", `{"enabled": true}`),
+		Coordinate: coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "profile"},
+		Type:       config.Type{SchemaId: "builtin:alerting.profile"},
+		Parameters: config.Parameters{config.ScopeParameter: &value.ValueParameter{Value: "environment"}},
+	}
+
+	changes, errs := PlanConfigs(c, testApiMap, []config.Config{conf})
+
+	assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+	assert.Equal(t, len(changes), 1)
+	assert.Equal(t, changes[0].Action, PlanActionUpdate)
+	assert.DeepEqual(t, changes[0].Differences, []json.Difference{{Path: "enabled", Expected: true, Actual: false}})
+}
+
+func TestPlanConfigs_ReferencesToConfigsToBeCreatedAreResolved(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(dashboardApi).Return(nil, nil).Times(1)
+
+	referenced := coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "referenced"}
+	configs := []config.Config{
+		{
+			Template:   template.CreateTemplateFromString("template", `{}`),
+			Coordinate: referenced,
+			Type:       config.Type{Api: "dashboard"},
+			Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "referenced"}},
+		},
+		{
+			Template:   template.CreateTemplateFromString("template", `{"ref": "{{ .ref }}"}`),
+			Coordinate: coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "referencing"},
+			Type:       config.Type{Api: "dashboard"},
+			Parameters: config.Parameters{
+				config.NameParameter: &value.ValueParameter{Value: "referencing"},
+				"ref": &parameter.DummyParameter{
+					Value:      "referenced-id",
+					References: []parameter.ParameterReference{{Config: referenced, Property: config.IdParameter}},
+				},
+			},
+		},
+	}
+
+	changes, errs := PlanConfigs(c, testApiMap, configs)
+
+	assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+	assert.Equal(t, len(changes), 2)
+	assert.Equal(t, changes[0].Action, PlanActionCreate)
+	assert.Equal(t, changes[1].Action, PlanActionCreate)
+}
+
+func TestPlanConfigs_SkippedConfigs(t *testing.T) {
+	conf := config.Config{
+		Coordinate: coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "dashboard"},
+		Skip:       true,
+	}
+
+	changes, errs := PlanConfigs(client.NewMockClient(gomock.NewController(t)), api.ApiMap{}, []config.Config{conf})
+
+	assert.Equal(t, len(errs), 0)
+	assert.DeepEqual(t, changes, []PlannedChange{{Coordinate: conf.Coordinate, Action: PlanActionSkip}})
+}
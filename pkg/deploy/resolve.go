@@ -27,6 +27,7 @@ func ResolveParameterValues(
 	conf *config.Config,
 	entities map[coordinate.Coordinate]parameter.ResolvedEntity,
 	parameters []topologysort.ParameterWithName,
+	lookup EnvironmentLookup,
 ) (parameter.Properties, []error) {
 
 	var errors []error
@@ -51,6 +52,8 @@ func ResolveParameterValues(
 			Environment:             conf.Environment,
 			ParameterName:           name,
 			ResolvedParameterValues: properties,
+			EntityLookup:            lookup,
+			ConfigLookup:            lookup,
 		})
 
 		if err != nil {
@@ -74,13 +77,13 @@ func ResolveParameterValues(
 	return properties, nil
 }
 
-func resolveProperties(c *config.Config, entities map[coordinate.Coordinate]parameter.ResolvedEntity) (parameter.Properties, []error) {
+func resolveProperties(c *config.Config, entities map[coordinate.Coordinate]parameter.ResolvedEntity, lookup EnvironmentLookup) (parameter.Properties, []error) {
 	var errors []error
 
 	parameters, sortErrs := topologysort.SortParameters(c.Group, c.Environment, c.Coordinate, c.Parameters)
 	errors = append(errors, sortErrs...)
 
-	properties, errs := ResolveParameterValues(c, entities, parameters)
+	properties, errs := ResolveParameterValues(c, entities, parameters, lookup)
 	errors = append(errors, errs...)
 
 	if len(errors) > 0 {
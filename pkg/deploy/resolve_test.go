@@ -67,7 +67,7 @@ func TestResolveParameterValues(t *testing.T) {
 
 	entities := map[coordinate.Coordinate]parameter.ResolvedEntity{}
 
-	values, errors := ResolveParameterValues(&conf, entities, parameters)
+	values, errors := ResolveParameterValues(&conf, entities, parameters, nil)
 
 	assert.Assert(t, len(errors) == 0, "there should be no errors (errors: %s)", errors)
 	assert.Equal(t, name, values[config.NameParameter])
@@ -109,7 +109,7 @@ func TestResolveParameterValuesShouldFailWhenReferencingNonExistingConfig(t *tes
 
 	entities := map[coordinate.Coordinate]parameter.ResolvedEntity{}
 
-	_, errors := ResolveParameterValues(&conf, entities, parameters)
+	_, errors := ResolveParameterValues(&conf, entities, parameters, nil)
 
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
@@ -156,7 +156,7 @@ func TestResolveParameterValuesShouldFailWhenReferencingSkippedConfig(t *testing
 		},
 	}
 
-	_, errors := ResolveParameterValues(&conf, entities, parameters)
+	_, errors := ResolveParameterValues(&conf, entities, parameters, nil)
 
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
@@ -185,7 +185,7 @@ func TestResolveParameterValuesShouldFailWhenParameterResolveReturnsError(t *tes
 
 	entities := map[coordinate.Coordinate]parameter.ResolvedEntity{}
 
-	_, errors := ResolveParameterValues(&conf, entities, parameters)
+	_, errors := ResolveParameterValues(&conf, entities, parameters, nil)
 
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
@@ -0,0 +1,197 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/state"
+	"sync"
+)
+
+// journalEntry records a single object modified during a deployment, and how to restore it
+type journalEntry struct {
+	conf *config.Config
+	// api is the API of classic configs, it is nil for settings
+	api api.Api
+	// name is the name the config was deployed with
+	name string
+	// objectId is the ID of the object the config was deployed to
+	objectId string
+	// created is true if the object did not exist before the deployment
+	created bool
+	// previous is the snapshot of the object before the deployment. It is only set if created is false.
+	previous liveObject
+	// previousState is the state entry of the config before the deployment, if previousStateFound is set
+	previousState      state.Entry
+	previousStateFound bool
+}
+
+// rollbackJournal records all objects modified during a deployment, so they can be restored if the deployment fails.
+// It is safe to be used concurrently by multiple goroutines.
+type rollbackJournal struct {
+	lock    sync.Mutex
+	entries []journalEntry
+	// state is the optional deployment state. Entries of rolled back configs are reverted to their previous value.
+	state *state.State
+	// report is the optional recorder rolled back configs are marked as report.ActionRolledBack in
+	report *report.Recorder
+}
+
+func (j *rollbackJournal) add(e journalEntry) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.entries = append(j.entries, e)
+}
+
+// recordDeployment records the object the given config was deployed to. If the given snapshot was not found, the
+// object is considered created by the deployment, otherwise the snapshot is restored on rollback.
+// It needs to be called before the state entry of the config is updated.
+func (j *rollbackJournal) recordDeployment(conf *config.Config, theApi api.Api, name string, objectId string, snapshot liveObject, snapshotFound bool) {
+	if j == nil {
+		return
+	}
+
+	created := !snapshotFound
+	if theApi != nil && theApi.IsSingleConfigurationApi() {
+		created = false
+	} else if snapshotFound && snapshot.id != objectId {
+		// a different object than the one we snapshotted got updated. The snapshotted object stays untouched.
+		created = true
+	}
+
+	e := journalEntry{
+		conf:     conf,
+		api:      theApi,
+		name:     name,
+		objectId: objectId,
+		created:  created,
+		previous: snapshot,
+	}
+	if j.state != nil {
+		e.previousState, e.previousStateFound = j.state.Get(conf.Environment, conf.Coordinate)
+	}
+	j.add(e)
+}
+
+// rollback restores all recorded objects in reverse order of their deployment. Created objects are deleted,
+// modified objects are restored to their snapshot. All objects that could not be restored are returned as errors.
+func (j *rollbackJournal) rollback(c client.Client) []error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	log.Info("Rolling back %d deployed config(s)...", len(j.entries))
+
+	var errs []error
+	rolledBack := 0
+
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		e := j.entries[i]
+
+		if err := e.restore(c); err != nil {
+			errs = append(errs, newConfigDeployErr(e.conf, fmt.Sprintf("failed to roll back object %s: %s", e.objectId, err)))
+			continue
+		}
+
+		if e.created {
+			log.Info("\tRolled back config %s by deleting created object %s", e.conf.Coordinate, e.objectId)
+		} else {
+			log.Info("\tRolled back config %s by restoring object %s", e.conf.Coordinate, e.objectId)
+		}
+		j.revertStateAndReport(e)
+		rolledBack++
+	}
+
+	if len(errs) > 0 {
+		log.Error("Rolled back %d config(s), %d config(s) could not be rolled back", rolledBack, len(errs))
+	} else {
+		log.Info("Rolled back %d config(s)", rolledBack)
+	}
+
+	j.entries = nil
+	return errs
+}
+
+// revertStateAndReport reverts the state entry of the rolled back config to its value before the deployment, and
+// marks the config as rolled back in the report
+func (j *rollbackJournal) revertStateAndReport(e journalEntry) {
+	if j.state != nil {
+		if e.previousStateFound {
+			j.state.Put(e.conf.Environment, e.conf.Coordinate, e.previousState)
+		} else {
+			j.state.Delete(e.conf.Environment, e.conf.Coordinate)
+		}
+	}
+
+	if j.report != nil {
+		j.report.UpdateAction(e.conf.Environment, e.conf.Coordinate, report.ActionRolledBack)
+	}
+}
+
+func (e journalEntry) restore(c client.Client) error {
+	if e.api == nil {
+		return e.restoreSetting(c)
+	}
+
+	if e.created {
+		return c.DeleteConfigById(e.api, e.objectId)
+	}
+
+	payload, err := withoutMetadata(e.previous.payload)
+	if err != nil {
+		return err
+	}
+
+	if e.api.IsSingleConfigurationApi() {
+		_, err = c.UpsertConfigByName(e.api, e.name, payload)
+	} else {
+		_, err = c.UpdateConfigById(e.api, e.previous.id, e.name, payload)
+	}
+	return err
+}
+
+func (e journalEntry) restoreSetting(c client.SettingsClient) error {
+	if e.created {
+		return c.DeleteSettings(e.objectId)
+	}
+
+	_, err := c.UpsertSettings(client.SettingsObject{
+		Id:             e.conf.Coordinate.ConfigId,
+		SchemaId:       e.conf.Type.SchemaId,
+		SchemaVersion:  e.previous.schemaVersion,
+		Scope:          e.previous.scope,
+		Content:        e.previous.payload,
+		OriginObjectId: e.previous.id,
+		ExternalId:     &e.previous.externalId,
+	})
+	return err
+}
+
+// withoutMetadata removes the server managed metadata from a classic config payload, as it can not be sent back
+func withoutMetadata(payload []byte) ([]byte, error) {
+	var obj map[string]any
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+
+	delete(obj, "metadata")
+	return json.Marshal(obj)
+}
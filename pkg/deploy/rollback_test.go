@@ -0,0 +1,175 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/state"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func dashboardConfig(t *testing.T, id string, name string) config.Config {
+	return config.Config{
+		Template:    generateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: id},
+		Environment: "env",
+		Type:        config.Type{Api: "dashboard"},
+		Parameters: config.Parameters{
+			config.NameParameter: &parameter.DummyParameter{Value: name},
+		},
+	}
+}
+
+func failingDashboardConfig(t *testing.T) config.Config {
+	c := dashboardConfig(t, "failing", "failing")
+	c.Template = generateFaultyTemplate(t)
+	return c
+}
+
+func TestDeployConfigsWithRollback_RestoresModifiedAndDeletesCreatedObjects(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ListConfigs(dashboardApi).Return([]api.Value{{Id: "existing-id", Name: "existing"}}, nil)
+	c.EXPECT().ReadConfigById(dashboardApi, "existing-id").Return([]byte(`{"name":"existing","old":true,"metadata":{"clusterVersion":"1.0"}}`), nil)
+	c.EXPECT().UpsertConfigByName(dashboardApi, "existing", gomock.Any()).Return(api.DynatraceEntity{Id: "existing-id", Name: "existing"}, nil)
+
+	c.EXPECT().UpsertConfigByName(dashboardApi, "new", gomock.Any()).Return(api.DynatraceEntity{Id: "new-id", Name: "new"}, nil)
+
+	gomock.InOrder(
+		c.EXPECT().DeleteConfigById(dashboardApi, "new-id").Return(nil),
+		c.EXPECT().UpdateConfigById(dashboardApi, "existing-id", "existing", []byte(`{"name":"existing","old":true}`)).Return(api.DynatraceEntity{Id: "existing-id"}, nil),
+	)
+
+	sortedConfigs := []config.Config{
+		dashboardConfig(t, "existing", "existing"),
+		dashboardConfig(t, "new", "new"),
+		failingDashboardConfig(t),
+	}
+
+	errs := DeployConfigs(c, testApiMap, sortedConfigs, DeployConfigsOptions{Rollback: true})
+	assert.Equal(t, len(errs), 1, "only the deployment error is expected: %v", errs)
+}
+
+func TestDeployConfigsWithRollback_ReportsObjectsThatCouldNotBeRolledBack(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ListConfigs(dashboardApi).Return(nil, nil)
+	c.EXPECT().UpsertConfigByName(dashboardApi, "new", gomock.Any()).Return(api.DynatraceEntity{Id: "new-id", Name: "new"}, nil)
+	c.EXPECT().DeleteConfigById(dashboardApi, "new-id").Return(errors.New("delete failed"))
+
+	sortedConfigs := []config.Config{
+		dashboardConfig(t, "new", "new"),
+		failingDashboardConfig(t),
+	}
+
+	errs := DeployConfigs(c, testApiMap, sortedConfigs, DeployConfigsOptions{Rollback: true})
+	assert.Equal(t, len(errs), 2)
+	assert.ErrorContains(t, errs[1], "failed to roll back object new-id")
+}
+
+func TestDeployConfigsWithRollback_RestoresSettings(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ListSettings("builtin:schema", gomock.Any()).Return([]client.DownloadSettingsObject{
+		{ObjectId: "object-id", ExternalId: "created-in-ui", Scope: "tenant", SchemaVersion: "1.0", Value: []byte(`{"old":true}`)},
+	}, nil)
+	c.EXPECT().UpsertSettings(gomock.Any()).Return(api.DynatraceEntity{Id: "object-id", Name: "object-id"}, nil)
+	previousExternalId := "created-in-ui"
+	c.EXPECT().UpsertSettings(client.SettingsObject{
+		Id:             "setting",
+		SchemaId:       "builtin:schema",
+		SchemaVersion:  "1.0",
+		Scope:          "tenant",
+		Content:        []byte(`{"old":true}`),
+		OriginObjectId: "object-id",
+		ExternalId:     &previousExternalId,
+	}).Return(api.DynatraceEntity{Id: "object-id"}, nil)
+
+	sortedConfigs := []config.Config{
+		{
+			Template:       generateDummyTemplate(t),
+			Coordinate:     coordinate.Coordinate{Project: "project", Type: "builtin:schema", ConfigId: "setting"},
+			Environment:    "env",
+			Type:           config.Type{SchemaId: "builtin:schema", SchemaVersion: "2.0"},
+			OriginObjectId: "object-id",
+			Parameters: config.Parameters{
+				config.ScopeParameter: &value.ValueParameter{Value: "tenant"},
+			},
+		},
+		failingDashboardConfig(t),
+	}
+
+	errs := DeployConfigs(c, testApiMap, sortedConfigs, DeployConfigsOptions{Rollback: true})
+	assert.Equal(t, len(errs), 1, "only the deployment error is expected: %v", errs)
+}
+
+func TestDeployConfigsWithRollback_RevertsStateAndReport(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ListConfigs(dashboardApi).Return([]api.Value{{Id: "existing-id", Name: "existing"}}, nil)
+	c.EXPECT().ReadConfigById(dashboardApi, "existing-id").Return([]byte(`{"name":"existing"}`), nil)
+	c.EXPECT().UpdateConfigById(dashboardApi, "existing-id", "existing", gomock.Any()).Return(api.DynatraceEntity{Id: "existing-id", Name: "existing"}, nil).Times(2)
+	c.EXPECT().UpsertConfigByName(dashboardApi, "new", gomock.Any()).Return(api.DynatraceEntity{Id: "new-id", Name: "new"}, nil)
+	c.EXPECT().DeleteConfigById(dashboardApi, "new-id").Return(nil)
+
+	existing := dashboardConfig(t, "existing", "existing")
+	created := dashboardConfig(t, "new", "new")
+
+	deployState := state.New()
+	previousEntry := state.Entry{ObjectId: "existing-id", Name: "existing", Checksum: "previous"}
+	deployState.Put("env", existing.Coordinate, previousEntry)
+
+	recorder := report.NewRecorder()
+	errs := DeployConfigs(c, testApiMap, []config.Config{existing, created, failingDashboardConfig(t)}, DeployConfigsOptions{Rollback: true, State: deployState, Report: recorder})
+	assert.Equal(t, len(errs), 1, "only the deployment error is expected: %v", errs)
+
+	entry, found := deployState.Get("env", existing.Coordinate)
+	assert.Assert(t, found)
+	assert.Equal(t, entry, previousEntry)
+
+	_, found = deployState.Get("env", created.Coordinate)
+	assert.Assert(t, !found, "expected the state entry of the deleted object to be removed")
+
+	assert.DeepEqual(t, recorder.CountByAction(), map[report.Action]int{report.ActionRolledBack: 2, report.ActionFailed: 1})
+}
+
+func TestDeployConfigsWithRollback_DoesNothingIfDeploymentSucceeds(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+
+	c.EXPECT().ListConfigs(dashboardApi).Return(nil, nil)
+	c.EXPECT().UpsertConfigByName(dashboardApi, "new", gomock.Any()).Return(api.DynatraceEntity{Id: "new-id", Name: "new"}, nil)
+
+	errs := DeployConfigs(c, testApiMap, []config.Config{dashboardConfig(t, "new", "new")}, DeployConfigsOptions{Rollback: true})
+	assert.Equal(t, len(errs), 0)
+}
+
+func TestDeployConfigsWithRollback_IsIgnoredDuringDryRun(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().UpsertConfigByName(dashboardApi, "new", gomock.Any()).Return(api.DynatraceEntity{Id: "new-id", Name: "new"}, nil)
+
+	errs := DeployConfigs(c, testApiMap, []config.Config{dashboardConfig(t, "new", "new"), failingDashboardConfig(t)}, DeployConfigsOptions{Rollback: true, DryRun: true})
+	assert.Equal(t, len(errs), 1)
+}
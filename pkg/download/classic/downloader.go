@@ -18,6 +18,7 @@ package classic
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"sync"
 	"time"
@@ -70,7 +71,15 @@ func NewDownloader(client client.Client, opts ...func(*Downloader)) *Downloader
 //
 // See package documentation for implementation details.
 func (d *Downloader) DownloadAll(apisToDownload api.ApiMap, projectName string) project.ConfigsPerType {
+	results, _ := d.DownloadAllWithErrors(apisToDownload, projectName) // errors are already logged
+	return results
+}
+
+// DownloadAllWithErrors works like DownloadAll, but additionally returns the errors of all failed downloads. This allows
+// callers to tell failed downloads apart from configs which do not exist in the environment.
+func (d *Downloader) DownloadAllWithErrors(apisToDownload api.ApiMap, projectName string) (project.ConfigsPerType, []error) {
 	results := make(project.ConfigsPerType, len(apisToDownload))
+	var errs []error
 	mutex := sync.Mutex{}
 	wg := sync.WaitGroup{}
 	wg.Add(len(apisToDownload))
@@ -84,6 +93,9 @@ func (d *Downloader) DownloadAll(apisToDownload api.ApiMap, projectName string)
 			configsToDownload, err := d.findConfigsToDownload(currentApi)
 			if err != nil {
 				log.Error("\tFailed to fetch configs of type '%v', skipping download of this type. Reason: %v", currentApi.GetId(), err)
+				mutex.Lock()
+				errs = append(errs, fmt.Errorf("failed to fetch configs of type '%v': %w", currentApi.GetId(), err))
+				mutex.Unlock()
 				return
 			}
 			// filter all configs we do not want to download. All remaining will be downloaded
@@ -95,14 +107,15 @@ func (d *Downloader) DownloadAll(apisToDownload api.ApiMap, projectName string)
 			}
 
 			log.Debug("\tFound %d configs of type '%v' to download", len(configsToDownload), currentApi.GetId())
-			configs := d.downloadConfigsOfAPI(currentApi, configsToDownload, projectName)
+			configs, downloadErrs := d.downloadConfigsOfAPI(currentApi, configsToDownload, projectName)
 
 			log.Debug("\tFinished downloading all configs of type '%v'", currentApi.GetId())
+			mutex.Lock()
 			if len(configs) > 0 {
-				mutex.Lock()
 				results[currentApi.GetId()] = configs
-				mutex.Unlock()
 			}
+			errs = append(errs, downloadErrs...)
+			mutex.Unlock()
 
 		}()
 	}
@@ -112,11 +125,12 @@ func (d *Downloader) DownloadAll(apisToDownload api.ApiMap, projectName string)
 	duration := time.Now().Sub(startTime).Truncate(1 * time.Second)
 	log.Debug("Finished fetching all configs in %v", duration)
 
-	return results
+	return results, errs
 }
 
-func (d *Downloader) downloadConfigsOfAPI(api api.Api, values []api.Value, projectName string) []config.Config {
+func (d *Downloader) downloadConfigsOfAPI(api api.Api, values []api.Value, projectName string) ([]config.Config, []error) {
 	results := make([]config.Config, 0, len(values))
+	var errs []error
 	mutex := sync.Mutex{}
 	wg := sync.WaitGroup{}
 	wg.Add(len(values))
@@ -128,6 +142,9 @@ func (d *Downloader) downloadConfigsOfAPI(api api.Api, values []api.Value, proje
 			downloadedJson, err := d.downloadAndUnmarshalConfig(api, value)
 			if err != nil {
 				log.Error("Error fetching config '%v' in api '%v': %v", value.Id, api.GetId(), err)
+				mutex.Lock()
+				errs = append(errs, fmt.Errorf("failed to fetch config '%v' in api '%v': %w", value.Id, api.GetId(), err))
+				mutex.Unlock()
 				return
 			}
 
@@ -139,6 +156,9 @@ func (d *Downloader) downloadConfigsOfAPI(api api.Api, values []api.Value, proje
 			c, err := d.createConfigForDownloadedJson(downloadedJson, api, value, projectName)
 			if err != nil {
 				log.Error("Error creating config for %v in api %v: %v", value.Id, api.GetId(), err)
+				mutex.Lock()
+				errs = append(errs, fmt.Errorf("failed to create config for '%v' in api '%v': %w", value.Id, api.GetId(), err))
+				mutex.Unlock()
 				return
 			}
 
@@ -149,7 +169,7 @@ func (d *Downloader) downloadConfigsOfAPI(api api.Api, values []api.Value, proje
 		}()
 	}
 	wg.Wait()
-	return results
+	return results, errs
 }
 
 func (d *Downloader) downloadAndUnmarshalConfig(theApi api.Api, value api.Value) (map[string]interface{}, error) {
@@ -130,6 +130,32 @@ func TestDownloadAll_ErrorFetchingConfig(t *testing.T) {
 	assert.Len(t, configurations, 1)
 }
 
+func TestDownloadAllWithErrors_ReturnsErrorsOfFailedDownloads(t *testing.T) {
+	client := client.NewMockClient(gomock.NewController(t))
+	client.EXPECT().ListConfigs(gomock.Any()).DoAndReturn(func(a api.Api) ([]api.Value, error) {
+		if a.GetId() == "API_ID_1" {
+			return nil, fmt.Errorf("LIST FAILED")
+		}
+		return []api.Value{{Id: "API_ID_2", Name: "API_NAME_2"}, {Id: "API_ID_3", Name: "API_NAME_3"}}, nil
+	}).Times(2)
+
+	client.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).DoAndReturn(func(a api.Api, id string) (json []byte, err error) {
+		if id == "API_ID_2" {
+			return nil, fmt.Errorf("READ FAILED")
+		}
+		return []byte("{}"), nil
+	}).Times(2)
+
+	testAPI1 := api.NewApi("API_ID_1", "API_PATH_1", "", false, true, "", false)
+	testAPI2 := api.NewApi("API_ID_2", "API_PATH_2", "", false, true, "", false)
+
+	apiMap := api.ApiMap{"API_ID_1": testAPI1, "API_ID_2": testAPI2}
+	configurations, errs := NewDownloader(client).DownloadAllWithErrors(apiMap, "project")
+	assert.Len(t, configurations, 1)
+	assert.Len(t, configurations["API_ID_2"], 1)
+	assert.Len(t, errs, 2)
+}
+
 func TestDownloadAll_SkipConfigThatShouldNotBePersisted(t *testing.T) {
 
 	client := client.NewMockClient(gomock.NewController(t))
@@ -0,0 +1,250 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graph builds the dependency graph of the configs of an environment, as used to determine the order
+// configs are deployed in.
+package graph
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/sort"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	s "sort"
+)
+
+// DependsOnLabel is the label of edges declared in the `dependsOn` of a config instead of a parameter
+const DependsOnLabel = "dependsOn"
+
+// Graph is the dependency graph of the configs of an environment
+type Graph struct {
+	Environment string
+	Nodes       []Node
+	Edges       []Edge
+}
+
+// Node is a config in a dependency graph
+type Node struct {
+	Coordinate coordinate.Coordinate
+	// Skip is set if the config is skipped during deployment. Dependencies of skipped configs are not considered when
+	// sorting configs.
+	Skip bool
+	// Missing is set if the config is referenced, but not defined in any loaded project
+	Missing bool
+	// InCycle is set if the config is part of a circular dependency
+	InCycle bool
+}
+
+// Edge is a dependency of one config on another
+type Edge struct {
+	// From is the config depending on To
+	From coordinate.Coordinate
+	To   coordinate.Coordinate
+	// Labels holds the names of the parameters referencing To, or DependsOnLabel
+	Labels []string
+	// InCycle is set if the dependency is part of a circular dependency
+	InCycle bool
+}
+
+// Filter restricts a graph to configs of certain projects and types. Empty fields do not restrict the graph.
+type Filter struct {
+	Projects []string
+	Types    []string
+}
+
+func (f Filter) matches(c coordinate.Coordinate) bool {
+	return (len(f.Projects) == 0 || contains(f.Projects, c.Project)) && (len(f.Types) == 0 || contains(f.Types, c.Type))
+}
+
+// Build returns the dependency graph of the given configs of an environment. Only edges of configs matching the
+// filter, or edges to them, are part of the graph, as well as the configs connected by them.
+func Build(environment string, configs []config.Config, filter Filter) Graph {
+	defined := make(map[coordinate.Coordinate]config.Config, len(configs))
+	for _, c := range configs {
+		defined[c.Coordinate] = c
+	}
+
+	var edges []Edge
+	for _, c := range configs {
+		edges = append(edges, edgesOf(c)...)
+	}
+
+	markCycles(configs, edges)
+
+	nodes := make(map[coordinate.Coordinate]Node)
+	addNode := func(c coordinate.Coordinate) {
+		if _, found := nodes[c]; found {
+			return
+		}
+		conf, found := defined[c]
+		nodes[c] = Node{Coordinate: c, Skip: found && conf.Skip, Missing: !found}
+	}
+
+	result := Graph{Environment: environment}
+
+	for _, c := range configs {
+		if filter.matches(c.Coordinate) {
+			addNode(c.Coordinate)
+		}
+	}
+
+	for _, e := range edges {
+		if filter.matches(e.From) || filter.matches(e.To) {
+			addNode(e.From)
+			addNode(e.To)
+			result.Edges = append(result.Edges, e)
+		}
+	}
+
+	for _, e := range result.Edges {
+		if e.InCycle {
+			for _, c := range []coordinate.Coordinate{e.From, e.To} {
+				n := nodes[c]
+				n.InCycle = true
+				nodes[c] = n
+			}
+		}
+	}
+
+	for _, n := range nodes {
+		result.Nodes = append(result.Nodes, n)
+	}
+
+	s.Slice(result.Nodes, func(i, j int) bool {
+		return result.Nodes[i].Coordinate.String() < result.Nodes[j].Coordinate.String()
+	})
+	s.SliceStable(result.Edges, func(i, j int) bool {
+		if result.Edges[i].From != result.Edges[j].From {
+			return result.Edges[i].From.String() < result.Edges[j].From.String()
+		}
+		return result.Edges[i].To.String() < result.Edges[j].To.String()
+	})
+
+	return result
+}
+
+// edgesOf returns an edge for each config the given config depends on
+func edgesOf(c config.Config) []Edge {
+	labels := make(map[coordinate.Coordinate][]string)
+	var targets []coordinate.Coordinate
+
+	addLabel := func(to coordinate.Coordinate, label string) {
+		if to == c.Coordinate {
+			// references to other parameters of the same config are no dependency
+			return
+		}
+		if _, found := labels[to]; !found {
+			targets = append(targets, to)
+		}
+		if !contains(labels[to], label) {
+			labels[to] = append(labels[to], label)
+		}
+	}
+
+	names := make([]string, 0, len(c.Parameters))
+	for name := range c.Parameters {
+		names = append(names, name)
+	}
+	s.Strings(names)
+
+	for _, name := range names {
+		for _, ref := range c.Parameters[name].GetReferences() {
+			addLabel(ref.Config, name)
+		}
+	}
+
+	for _, d := range c.DependsOn {
+		addLabel(d, DependsOnLabel)
+	}
+
+	edges := make([]Edge, len(targets))
+	for i, to := range targets {
+		edges[i] = Edge{From: c.Coordinate, To: to, Labels: labels[to]}
+	}
+	return edges
+}
+
+// markCycles marks all edges which are part of a circular dependency, in the same way configs are sorted for
+// deployment: dependencies of skipped configs are ignored. Only dependencies left unresolved by sorting the configs
+// topologically, see sort.TopologySortError, can be part of a circular dependency. Such a dependency is part of one,
+// if the config depending on the other one is in turn a transitive dependency of it.
+func markCycles(configs []config.Config, edges []Edge) {
+	index := make(map[coordinate.Coordinate]int, len(configs))
+	for i, c := range configs {
+		index[c.Coordinate] = i
+	}
+
+	isDependency := func(e Edge) (from, to int, ok bool) {
+		from, fromFound := index[e.From]
+		to, toFound := index[e.To]
+		return from, to, fromFound && toFound && !configs[from].Skip
+	}
+
+	// the edges of the matrix point from each config to the configs depending on it, as in the deployment sorting
+	matrix := make([][]bool, len(configs))
+	inDegrees := make([]int, len(configs))
+	for i := range configs {
+		matrix[i] = make([]bool, len(configs))
+	}
+	for _, e := range edges {
+		if from, to, ok := isDependency(e); ok && !matrix[to][from] {
+			matrix[to][from] = true
+			inDegrees[to]++
+		}
+	}
+
+	_, sortErrs := sort.TopologySort(matrix, inDegrees)
+
+	unresolvedDependents := make(map[int][]int, len(sortErrs))
+	for _, sortErr := range sortErrs {
+		unresolvedDependents[sortErr.OnId] = sortErr.UnresolvedIncomingEdgesFrom
+	}
+
+	for i, e := range edges {
+		from, to, ok := isDependency(e)
+		edges[i].InCycle = ok && slices.Contains(unresolvedDependents[to], from) && reaches(unresolvedDependents, from, to)
+	}
+}
+
+// reaches returns whether the target node can be reached from the start node, following the given edges
+func reaches(edges map[int][]int, start, target int) bool {
+	visited := map[int]bool{start: true}
+	queue := []int{start}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, next := range edges[node] {
+			if next == target {
+				return true
+			}
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package graph
+
+import (
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"gotest.tools/assert"
+	"testing"
+)
+
+var (
+	a = coordinate.Coordinate{Project: "p1", Type: "dashboard", ConfigId: "a"}
+	b = coordinate.Coordinate{Project: "p1", Type: "dashboard", ConfigId: "b"}
+	c = coordinate.Coordinate{Project: "p1", Type: "alerting-profile", ConfigId: "c"}
+	d = coordinate.Coordinate{Project: "p2", Type: "alerting-profile", ConfigId: "d"}
+)
+
+func ref(c coordinate.Coordinate) *reference.ReferenceParameter {
+	return reference.NewWithCoordinate(c, "id")
+}
+
+func TestBuild(t *testing.T) {
+	configs := []config.Config{
+		{
+			Coordinate: a,
+			Parameters: config.Parameters{
+				"name":    &value.ValueParameter{Value: "a"},
+				"profile": ref(c),
+				"other":   ref(c),
+				"self":    reference.NewWithCoordinate(a, "name"),
+			},
+		},
+		{
+			Coordinate: b,
+			Parameters: config.Parameters{"missing": ref(coordinate.Coordinate{Project: "p1", Type: "dashboard", ConfigId: "missing"})},
+			DependsOn:  []coordinate.Coordinate{a},
+			Skip:       true,
+		},
+		{
+			Coordinate: c,
+		},
+	}
+
+	got := Build("env", configs, Filter{})
+
+	assert.DeepEqual(t, got, Graph{
+		Environment: "env",
+		Nodes: []Node{
+			{Coordinate: c},
+			{Coordinate: a},
+			{Coordinate: b, Skip: true},
+			{Coordinate: coordinate.Coordinate{Project: "p1", Type: "dashboard", ConfigId: "missing"}, Missing: true},
+		},
+		Edges: []Edge{
+			{From: a, To: c, Labels: []string{"other", "profile"}},
+			{From: b, To: a, Labels: []string{DependsOnLabel}},
+			{From: b, To: coordinate.Coordinate{Project: "p1", Type: "dashboard", ConfigId: "missing"}, Labels: []string{"missing"}},
+		},
+	})
+}
+
+func TestBuild_MarksCycles(t *testing.T) {
+	configs := []config.Config{
+		{Coordinate: a, Parameters: config.Parameters{"b": ref(b)}},
+		{Coordinate: b, Parameters: config.Parameters{"a": ref(a), "c": ref(c)}},
+		// d depends on the cycle, and c is required by it, both are no part of it
+		{Coordinate: c},
+		{Coordinate: d, Parameters: config.Parameters{"a": ref(a)}},
+	}
+
+	got := Build("env", configs, Filter{})
+
+	assert.DeepEqual(t, got.Nodes, []Node{
+		{Coordinate: c},
+		{Coordinate: a, InCycle: true},
+		{Coordinate: b, InCycle: true},
+		{Coordinate: d},
+	})
+	assert.DeepEqual(t, got.Edges, []Edge{
+		{From: a, To: b, Labels: []string{"b"}, InCycle: true},
+		{From: b, To: c, Labels: []string{"c"}},
+		{From: b, To: a, Labels: []string{"a"}, InCycle: true},
+		{From: d, To: a, Labels: []string{"a"}},
+	})
+}
+
+func TestBuild_DoesNotMarkDependenciesBetweenCycles(t *testing.T) {
+	x := coordinate.Coordinate{Project: "p1", Type: "dashboard", ConfigId: "x"}
+
+	// a <-> b and c <-> d are cycles, x lies downstream of one and upstream of the other, but on none of them
+	configs := []config.Config{
+		{Coordinate: a, Parameters: config.Parameters{"b": ref(b)}},
+		{Coordinate: b, Parameters: config.Parameters{"a": ref(a)}},
+		{Coordinate: x, Parameters: config.Parameters{"a": ref(a)}},
+		{Coordinate: c, Parameters: config.Parameters{"d": ref(d), "x": ref(x)}},
+		{Coordinate: d, Parameters: config.Parameters{"c": ref(c)}},
+	}
+
+	got := Build("env", configs, Filter{})
+
+	assert.DeepEqual(t, got.Nodes, []Node{
+		{Coordinate: c, InCycle: true},
+		{Coordinate: a, InCycle: true},
+		{Coordinate: b, InCycle: true},
+		{Coordinate: x},
+		{Coordinate: d, InCycle: true},
+	})
+	assert.DeepEqual(t, got.Edges, []Edge{
+		{From: c, To: x, Labels: []string{"x"}},
+		{From: c, To: d, Labels: []string{"d"}, InCycle: true},
+		{From: a, To: b, Labels: []string{"b"}, InCycle: true},
+		{From: b, To: a, Labels: []string{"a"}, InCycle: true},
+		{From: x, To: a, Labels: []string{"a"}},
+		{From: d, To: c, Labels: []string{"c"}, InCycle: true},
+	})
+}
+
+func TestBuild_IgnoresCyclesOfSkippedConfigs(t *testing.T) {
+	configs := []config.Config{
+		{Coordinate: a, Parameters: config.Parameters{"b": ref(b)}},
+		{Coordinate: b, Parameters: config.Parameters{"a": ref(a)}, Skip: true},
+	}
+
+	got := Build("env", configs, Filter{})
+
+	for _, e := range got.Edges {
+		assert.Assert(t, !e.InCycle, "edge %v should not be part of a cycle", e)
+	}
+}
+
+func TestBuild_Filter(t *testing.T) {
+	configs := []config.Config{
+		{Coordinate: a, Parameters: config.Parameters{"profile": ref(c)}},
+		{Coordinate: b},
+		{Coordinate: c},
+		{Coordinate: d, Parameters: config.Parameters{"profile": ref(c)}},
+	}
+
+	got := Build("env", configs, Filter{Projects: []string{"p2"}})
+	assert.DeepEqual(t, got.Nodes, []Node{{Coordinate: c}, {Coordinate: d}})
+	assert.DeepEqual(t, got.Edges, []Edge{{From: d, To: c, Labels: []string{"profile"}}})
+
+	got = Build("env", configs, Filter{Types: []string{"dashboard"}})
+	assert.DeepEqual(t, got.Nodes, []Node{{Coordinate: c}, {Coordinate: a}, {Coordinate: b}})
+	assert.DeepEqual(t, got.Edges, []Edge{{From: a, To: c, Labels: []string{"profile"}}})
+}
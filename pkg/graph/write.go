@@ -0,0 +1,236 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"io"
+	"strings"
+)
+
+// Format is a supported output format of a graph
+type Format string
+
+const (
+	FormatDOT     Format = "dot"
+	FormatMermaid Format = "mermaid"
+	FormatJSON    Format = "json"
+)
+
+// Formats lists all supported graph formats
+var Formats = []Format{FormatDOT, FormatMermaid, FormatJSON}
+
+// ParseFormat returns the Format of the given name, or an error if the format is not supported
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported graph format %q, supported formats are %v", name, Formats)
+}
+
+// Write writes the given graphs in the given format
+func Write(w io.Writer, format Format, graphs []Graph) error {
+	switch format {
+	case FormatMermaid:
+		return WriteMermaid(w, graphs)
+	case FormatJSON:
+		return WriteJSON(w, graphs)
+	default:
+		return WriteDOT(w, graphs)
+	}
+}
+
+// WriteDOT writes the given graphs as a single Graphviz DOT graph, containing a cluster per environment. Configs and
+// dependencies which are part of a cycle are colored red, skipped configs are dashed and missing configs dotted.
+func WriteDOT(w io.Writer, graphs []Graph) error {
+	b := strings.Builder{}
+	b.WriteString("digraph monaco {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for i, g := range graphs {
+		fmt.Fprintf(&b, "  subgraph %s {\n", dotQuote("cluster_"+g.Environment))
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote(g.Environment))
+
+		for _, n := range g.Nodes {
+			var attributes []string
+			attributes = append(attributes, "label="+dotQuote(n.Coordinate.String()))
+			if n.InCycle {
+				attributes = append(attributes, "color=red")
+			}
+			switch {
+			case n.Missing:
+				attributes = append(attributes, "style=dotted")
+			case n.Skip:
+				attributes = append(attributes, "style=dashed")
+			}
+			fmt.Fprintf(&b, "    %s [%s];\n", dotQuote(nodeId(i, n.Coordinate)), strings.Join(attributes, ", "))
+		}
+
+		for _, e := range g.Edges {
+			attributes := []string{"label=" + dotQuote(strings.Join(e.Labels, ", "))}
+			if e.InCycle {
+				attributes = append(attributes, "color=red")
+			}
+			fmt.Fprintf(&b, "    %s -> %s [%s];\n", dotQuote(nodeId(i, e.From)), dotQuote(nodeId(i, e.To)), strings.Join(attributes, ", "))
+		}
+
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the given graphs as a single Mermaid flowchart, containing a subgraph per environment. Configs
+// and dependencies which are part of a cycle are colored red, skipped configs are dashed and missing configs dotted.
+func WriteMermaid(w io.Writer, graphs []Graph) error {
+	b := strings.Builder{}
+	b.WriteString("flowchart LR\n")
+	b.WriteString("  classDef cycle stroke:#f00,stroke-width:2px\n")
+	b.WriteString("  classDef skipped stroke-dasharray:5 5\n")
+	b.WriteString("  classDef missing stroke-dasharray:2 2,fill:#eee\n")
+
+	var cycleEdges []int
+	edgeIndex := 0
+
+	for i, g := range graphs {
+		fmt.Fprintf(&b, "  subgraph env%d [%s]\n", i, mermaidQuote(g.Environment))
+
+		for _, n := range g.Nodes {
+			fmt.Fprintf(&b, "    %s[%s]\n", nodeId(i, n.Coordinate), mermaidQuote(n.Coordinate.String()))
+
+			switch {
+			case n.InCycle:
+				fmt.Fprintf(&b, "    class %s cycle\n", nodeId(i, n.Coordinate))
+			case n.Missing:
+				fmt.Fprintf(&b, "    class %s missing\n", nodeId(i, n.Coordinate))
+			case n.Skip:
+				fmt.Fprintf(&b, "    class %s skipped\n", nodeId(i, n.Coordinate))
+			}
+		}
+
+		for _, e := range g.Edges {
+			fmt.Fprintf(&b, "    %s -->|%s| %s\n", nodeId(i, e.From), mermaidEscape(strings.Join(e.Labels, ", ")), nodeId(i, e.To))
+			if e.InCycle {
+				cycleEdges = append(cycleEdges, edgeIndex)
+			}
+			edgeIndex++
+		}
+
+		b.WriteString("  end\n")
+	}
+
+	for _, e := range cycleEdges {
+		fmt.Fprintf(&b, "  linkStyle %d stroke:#f00,stroke-width:2px\n", e)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type jsonGraph struct {
+	Environment string     `json:"environment"`
+	Nodes       []jsonNode `json:"nodes"`
+	Edges       []jsonEdge `json:"edges"`
+}
+
+type jsonNode struct {
+	Id       string `json:"id"`
+	Project  string `json:"project"`
+	Type     string `json:"type"`
+	ConfigId string `json:"configId"`
+	Skip     bool   `json:"skip,omitempty"`
+	Missing  bool   `json:"missing,omitempty"`
+	InCycle  bool   `json:"inCycle,omitempty"`
+}
+
+type jsonEdge struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Parameters []string `json:"parameters"`
+	InCycle    bool     `json:"inCycle,omitempty"`
+}
+
+// WriteJSON writes the given graphs as JSON list, containing an object with nodes and edges per environment
+func WriteJSON(w io.Writer, graphs []Graph) error {
+	result := make([]jsonGraph, len(graphs))
+
+	for i, g := range graphs {
+		result[i] = jsonGraph{
+			Environment: g.Environment,
+			Nodes:       make([]jsonNode, len(g.Nodes)),
+			Edges:       make([]jsonEdge, len(g.Edges)),
+		}
+
+		for j, n := range g.Nodes {
+			result[i].Nodes[j] = jsonNode{
+				Id:       n.Coordinate.String(),
+				Project:  n.Coordinate.Project,
+				Type:     n.Coordinate.Type,
+				ConfigId: n.Coordinate.ConfigId,
+				Skip:     n.Skip,
+				Missing:  n.Missing,
+				InCycle:  n.InCycle,
+			}
+		}
+
+		for j, e := range g.Edges {
+			result[i].Edges[j] = jsonEdge{
+				From:       e.From.String(),
+				To:         e.To.String(),
+				Parameters: e.Labels,
+				InCycle:    e.InCycle,
+			}
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// nodeId returns an identifier of the node of the given config in the graph with the given index, which is unique
+// within all written graphs
+func nodeId(graph int, c coordinate.Coordinate) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "g%d_", graph)
+	for _, r := range c.String() {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		} else {
+			fmt.Fprintf(&b, "_%x_", r)
+		}
+	}
+	return b.String()
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + mermaidEscape(s) + `"`
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;").Replace(s)
+}
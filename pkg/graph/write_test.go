@@ -0,0 +1,153 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package graph
+
+import (
+	"bytes"
+	"gotest.tools/assert"
+	"testing"
+)
+
+var testGraphs = []Graph{
+	{
+		Environment: "dev",
+		Nodes: []Node{
+			{Coordinate: a, InCycle: true},
+			{Coordinate: b, InCycle: true},
+			{Coordinate: c, Skip: true},
+		},
+		Edges: []Edge{
+			{From: a, To: b, Labels: []string{"b"}, InCycle: true},
+			{From: b, To: a, Labels: []string{"a", "name"}, InCycle: true},
+			{From: b, To: c, Labels: []string{DependsOnLabel}},
+		},
+	},
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("Mermaid")
+	assert.NilError(t, err)
+	assert.Equal(t, f, FormatMermaid)
+
+	_, err = ParseFormat("svg")
+	assert.ErrorContains(t, err, `unsupported graph format "svg"`)
+}
+
+func TestWriteDOT(t *testing.T) {
+	var b bytes.Buffer
+	assert.NilError(t, WriteDOT(&b, testGraphs))
+
+	assert.Equal(t, b.String(), `digraph monaco {
+  rankdir=LR;
+  node [shape=box];
+  subgraph "cluster_dev" {
+    label="dev";
+    "g0_p1_3a_dashboard_3a_a" [label="p1:dashboard:a", color=red];
+    "g0_p1_3a_dashboard_3a_b" [label="p1:dashboard:b", color=red];
+    "g0_p1_3a_alerting_2d_profile_3a_c" [label="p1:alerting-profile:c", style=dashed];
+    "g0_p1_3a_dashboard_3a_a" -> "g0_p1_3a_dashboard_3a_b" [label="b", color=red];
+    "g0_p1_3a_dashboard_3a_b" -> "g0_p1_3a_dashboard_3a_a" [label="a, name", color=red];
+    "g0_p1_3a_dashboard_3a_b" -> "g0_p1_3a_alerting_2d_profile_3a_c" [label="dependsOn"];
+  }
+}
+`)
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var b bytes.Buffer
+	assert.NilError(t, WriteMermaid(&b, testGraphs))
+
+	assert.Equal(t, b.String(), `flowchart LR
+  classDef cycle stroke:#f00,stroke-width:2px
+  classDef skipped stroke-dasharray:5 5
+  classDef missing stroke-dasharray:2 2,fill:#eee
+  subgraph env0 ["dev"]
+    g0_p1_3a_dashboard_3a_a["p1:dashboard:a"]
+    class g0_p1_3a_dashboard_3a_a cycle
+    g0_p1_3a_dashboard_3a_b["p1:dashboard:b"]
+    class g0_p1_3a_dashboard_3a_b cycle
+    g0_p1_3a_alerting_2d_profile_3a_c["p1:alerting-profile:c"]
+    class g0_p1_3a_alerting_2d_profile_3a_c skipped
+    g0_p1_3a_dashboard_3a_a -->|b| g0_p1_3a_dashboard_3a_b
+    g0_p1_3a_dashboard_3a_b -->|a, name| g0_p1_3a_dashboard_3a_a
+    g0_p1_3a_dashboard_3a_b -->|dependsOn| g0_p1_3a_alerting_2d_profile_3a_c
+  end
+  linkStyle 0 stroke:#f00,stroke-width:2px
+  linkStyle 1 stroke:#f00,stroke-width:2px
+`)
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	assert.NilError(t, WriteJSON(&b, testGraphs[:1]))
+
+	assert.Equal(t, b.String(), `[
+  {
+    "environment": "dev",
+    "nodes": [
+      {
+        "id": "p1:dashboard:a",
+        "project": "p1",
+        "type": "dashboard",
+        "configId": "a",
+        "inCycle": true
+      },
+      {
+        "id": "p1:dashboard:b",
+        "project": "p1",
+        "type": "dashboard",
+        "configId": "b",
+        "inCycle": true
+      },
+      {
+        "id": "p1:alerting-profile:c",
+        "project": "p1",
+        "type": "alerting-profile",
+        "configId": "c",
+        "skip": true
+      }
+    ],
+    "edges": [
+      {
+        "from": "p1:dashboard:a",
+        "to": "p1:dashboard:b",
+        "parameters": [
+          "b"
+        ],
+        "inCycle": true
+      },
+      {
+        "from": "p1:dashboard:b",
+        "to": "p1:dashboard:a",
+        "parameters": [
+          "a",
+          "name"
+        ],
+        "inCycle": true
+      },
+      {
+        "from": "p1:dashboard:b",
+        "to": "p1:alerting-profile:c",
+        "parameters": [
+          "dependsOn"
+        ]
+      }
+    ]
+  }
+]
+`)
+}
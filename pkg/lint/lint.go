@@ -0,0 +1,261 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint finds issues in configs which do not prevent loading them, but are most likely mistakes: templates
+// referencing undefined parameters, parameters which are never used, and JSON files no config uses.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	fileParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/file"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of issue a Warning reports
+type Kind string
+
+const (
+	// KindUndefinedParameter is reported for templates referencing a parameter the config does not define
+	KindUndefinedParameter Kind = "undefined-parameter"
+	// KindUnusedParameter is reported for parameters neither used by the template, nor referenced by any parameter
+	KindUnusedParameter Kind = "unused-parameter"
+	// KindOrphanedFile is reported for JSON files in project folders not used by any config
+	KindOrphanedFile Kind = "orphaned-file"
+)
+
+// Warning is a single issue found by linting
+type Warning struct {
+	Kind Kind
+	// Path of the file the issue is found in
+	Path string
+	// Coordinate of the config the issue is found in. It is not set for KindOrphanedFile.
+	Coordinate *coordinate.Coordinate
+	// Parameter the issue is about. It is not set for KindOrphanedFile.
+	Parameter string
+	// Environments holds the names of all environments the issue is found in. It is not set for KindOrphanedFile.
+	Environments []string
+	Message      string
+}
+
+type jsonWarning struct {
+	Kind         Kind     `json:"kind"`
+	Path         string   `json:"path"`
+	Config       string   `json:"config,omitempty"`
+	Parameter    string   `json:"parameter,omitempty"`
+	Environments []string `json:"environments,omitempty"`
+	Message      string   `json:"message"`
+}
+
+// MarshalJSON writes the warning with the coordinate of the config in its string form
+func (w Warning) MarshalJSON() ([]byte, error) {
+	result := jsonWarning{
+		Kind:         w.Kind,
+		Path:         filepath.ToSlash(w.Path),
+		Parameter:    w.Parameter,
+		Environments: w.Environments,
+		Message:      w.Message,
+	}
+	if w.Coordinate != nil {
+		result.Config = w.Coordinate.String()
+	}
+	return json.Marshal(result)
+}
+
+func (w Warning) String() string {
+	if w.Coordinate == nil {
+		return fmt.Sprintf("%s: %s", w.Path, w.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s (environments: %s)", w.Path, w.Coordinate, w.Message, strings.Join(w.Environments, ", "))
+}
+
+// ignoredParameters are not required to be used by templates, as they are used by monaco itself
+var ignoredParameters = map[string]struct{}{
+	config.NameParameter:  {},
+	config.ScopeParameter: {},
+	config.SkipParameter:  {},
+}
+
+// Configs compares the parameters referenced by the template of each config with its parameters. Issues found in
+// multiple environments are reported once, listing all affected environments.
+func Configs(configsPerEnvironment map[string][]config.Config) []Warning {
+	type key struct {
+		kind       Kind
+		coordinate coordinate.Coordinate
+		parameter  string
+	}
+
+	warnings := make(map[key]*Warning)
+	var keys []key
+
+	add := func(kind Kind, c config.Config, param string, message string) {
+		k := key{kind: kind, coordinate: c.Coordinate, parameter: param}
+		if w, found := warnings[k]; found {
+			w.Environments = append(w.Environments, c.Environment)
+			return
+		}
+
+		coord := c.Coordinate
+		warnings[k] = &Warning{
+			Kind:         kind,
+			Path:         c.Template.Name(),
+			Coordinate:   &coord,
+			Parameter:    param,
+			Environments: []string{c.Environment},
+			Message:      message,
+		}
+		keys = append(keys, k)
+	}
+
+	for _, env := range sortedKeys(configsPerEnvironment) {
+		configs := configsPerEnvironment[env]
+		referenced := referencedProperties(configs)
+
+		for _, c := range configs {
+			if c.Template == nil {
+				continue
+			}
+
+			fields, err := template.ReferencedFields(c.Template)
+			if err != nil {
+				// invalid templates are already reported when loading configs
+				continue
+			}
+
+			used := make(map[string]struct{}, len(fields))
+			for _, f := range fields {
+				used[f] = struct{}{}
+				if _, found := c.Parameters[f]; !found {
+					add(KindUndefinedParameter, c, f, fmt.Sprintf("template references `.%s`, which is not a parameter of the config", f))
+				}
+			}
+
+			for _, name := range sortedKeys(c.Parameters) {
+				if _, found := ignoredParameters[name]; found {
+					continue
+				}
+				if _, found := used[name]; found {
+					continue
+				}
+				if _, found := referenced[propertyOf(c.Coordinate, name)]; found {
+					continue
+				}
+				add(KindUnusedParameter, c, name, fmt.Sprintf("parameter `%s` is neither used by the template, nor referenced by any parameter", name))
+			}
+		}
+	}
+
+	result := make([]Warning, len(keys))
+	for i, k := range keys {
+		result[i] = *warnings[k]
+	}
+
+	sortWarnings(result)
+	return result
+}
+
+// referencedProperties returns all properties of configs referenced by any parameter of the given configs
+func referencedProperties(configs []config.Config) map[string]struct{} {
+	result := make(map[string]struct{})
+	for _, c := range configs {
+		for _, p := range c.Parameters {
+			for _, ref := range p.GetReferences() {
+				result[propertyOf(ref.Config, ref.Property)] = struct{}{}
+			}
+		}
+	}
+	return result
+}
+
+func propertyOf(c coordinate.Coordinate, property string) string {
+	return c.String() + ":" + property
+}
+
+// OrphanedFiles returns a warning for each JSON file in the given project folders which is neither used as template,
+// nor as file parameter by any of the given configs. Hidden folders are skipped, like when loading projects.
+func OrphanedFiles(fs afero.Fs, projectPaths []string, configsPerEnvironment map[string][]config.Config) ([]Warning, error) {
+	used := make(map[string]struct{})
+	for _, configs := range configsPerEnvironment {
+		for _, c := range configs {
+			if t, ok := c.Template.(template.FileBasedTemplate); ok {
+				used[filepath.Clean(t.FilePath())] = struct{}{}
+			}
+			for _, p := range c.Parameters {
+				if f, ok := p.(*fileParam.FileParameter); ok {
+					used[filepath.Join(f.Folder, filepath.FromSlash(f.Path))] = struct{}{}
+				}
+			}
+		}
+	}
+
+	var result []Warning
+
+	for _, projectPath := range projectPaths {
+		err := afero.Walk(fs, projectPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				if path != projectPath && strings.HasPrefix(info.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if strings.ToLower(filepath.Ext(path)) != ".json" {
+				return nil
+			}
+
+			if _, found := used[filepath.Clean(path)]; !found {
+				result = append(result, Warning{
+					Kind:    KindOrphanedFile,
+					Path:    path,
+					Message: "file is not used by any config",
+				})
+			}
+			return nil
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to search project folder %q for orphaned files: %w", projectPath, err)
+		}
+	}
+
+	sortWarnings(result)
+	return result, nil
+}
+
+func sortWarnings(warnings []Warning) {
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Path != warnings[j].Path {
+			return warnings[i].Path < warnings[j].Path
+		}
+		return warnings[i].Kind < warnings[j].Kind
+	})
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,192 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package lint
+
+import (
+	"encoding/json"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	fileParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/file"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"path/filepath"
+	"testing"
+)
+
+var (
+	dashboard = coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "dashboard"}
+	profile   = coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: "profile"}
+)
+
+func TestConfigs(t *testing.T) {
+	newConfigs := func(env string) []config.Config {
+		return []config.Config{
+			{
+				Coordinate:  dashboard,
+				Environment: env,
+				Template:    template.CreateTemplateFromString("project/dashboard/dashboard.json", `{"name": "{{ .name }}", "profile": "{{ .profileId }}", "owner": "{{ .owner }}"}`),
+				Parameters: config.Parameters{
+					"name":      &value.ValueParameter{Value: "dashboard"},
+					"profileId": reference.NewWithCoordinate(profile, "id"),
+					"unused":    &value.ValueParameter{Value: "unused"},
+				},
+			},
+			{
+				Coordinate:  profile,
+				Environment: env,
+				Template:    template.CreateTemplateFromString("project/alerting-profile/profile.json", `{"name": "{{ .name }}"}`),
+				Parameters: config.Parameters{
+					"name":         &value.ValueParameter{Value: "profile"},
+					"referencedBy": &value.ValueParameter{Value: "dashboard"},
+				},
+			},
+			{
+				Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "other"},
+				Environment: env,
+				Template:    template.CreateTemplateFromString("project/dashboard/other.json", `{"name": "{{ .name }}", "text": "{{ .text }}"}`),
+				Parameters: config.Parameters{
+					"name": &value.ValueParameter{Value: "other"},
+					"text": reference.NewWithCoordinate(profile, "referencedBy"),
+				},
+			},
+		}
+	}
+
+	got := Configs(map[string][]config.Config{
+		"env1": newConfigs("env1"),
+		"env2": newConfigs("env2"),
+	})
+
+	assert.DeepEqual(t, got, []Warning{
+		{
+			Kind:         KindUndefinedParameter,
+			Path:         "project/dashboard/dashboard.json",
+			Coordinate:   &dashboard,
+			Parameter:    "owner",
+			Environments: []string{"env1", "env2"},
+			Message:      "template references `.owner`, which is not a parameter of the config",
+		},
+		{
+			Kind:         KindUnusedParameter,
+			Path:         "project/dashboard/dashboard.json",
+			Coordinate:   &dashboard,
+			Parameter:    "unused",
+			Environments: []string{"env1", "env2"},
+			Message:      "parameter `unused` is neither used by the template, nor referenced by any parameter",
+		},
+	})
+}
+
+func TestConfigs_IgnoresSpecialParameters(t *testing.T) {
+	got := Configs(map[string][]config.Config{
+		"env": {
+			{
+				Coordinate:  dashboard,
+				Environment: "env",
+				Template:    template.CreateTemplateFromString("dashboard.json", `{}`),
+				Parameters: config.Parameters{
+					config.NameParameter:  &value.ValueParameter{Value: "dashboard"},
+					config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+					config.SkipParameter:  &value.ValueParameter{Value: false},
+				},
+			},
+		},
+	})
+
+	assert.Equal(t, len(got), 0)
+}
+
+func TestConfigs_ReportsOnlyAffectedEnvironments(t *testing.T) {
+	newConfig := func(env string, params config.Parameters) config.Config {
+		return config.Config{
+			Coordinate:  dashboard,
+			Environment: env,
+			Template:    template.CreateTemplateFromString("dashboard.json", `{"name": "{{ .name }}"}`),
+			Parameters:  params,
+		}
+	}
+
+	got := Configs(map[string][]config.Config{
+		"env1": {newConfig("env1", config.Parameters{"name": &value.ValueParameter{Value: "a"}})},
+		"env2": {newConfig("env2", config.Parameters{})},
+	})
+
+	assert.Equal(t, len(got), 1)
+	assert.DeepEqual(t, got[0].Environments, []string{"env2"})
+}
+
+func TestOrphanedFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for _, f := range []string{
+		"project/dashboard/dashboard.json",
+		"project/dashboard/tiles.json",
+		"project/dashboard/orphan.json",
+		"project/dashboard/config.yaml",
+		"project/.hidden/orphan.json",
+		"other/orphan.json",
+	} {
+		assert.NilError(t, afero.WriteFile(fs, filepath.FromSlash(f), []byte("{}"), 0644))
+	}
+
+	tiles := fileParam.New("tiles.json", "{}", false)
+	tiles.Folder = filepath.FromSlash("project/dashboard")
+
+	configs := map[string][]config.Config{
+		"env": {
+			{
+				Coordinate: dashboard,
+				Template:   template.CreateTemplateFromString(filepath.FromSlash("project/dashboard/dashboard.json"), "{}"),
+				Parameters: config.Parameters{"tiles": tiles},
+			},
+		},
+	}
+
+	got, err := OrphanedFiles(fs, []string{"project"}, configs)
+
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, []Warning{
+		{
+			Kind:    KindOrphanedFile,
+			Path:    filepath.FromSlash("project/dashboard/orphan.json"),
+			Message: "file is not used by any config",
+		},
+	})
+}
+
+func TestWarning_MarshalJSON(t *testing.T) {
+	got, err := json.Marshal([]Warning{
+		{
+			Kind:         KindUnusedParameter,
+			Path:         "dashboard.json",
+			Coordinate:   &dashboard,
+			Parameter:    "unused",
+			Environments: []string{"env"},
+			Message:      "message",
+		},
+		{
+			Kind:    KindOrphanedFile,
+			Path:    "orphan.json",
+			Message: "message",
+		},
+	})
+
+	assert.NilError(t, err)
+	assert.Equal(t, string(got), `[{"kind":"unused-parameter","path":"dashboard.json","config":"project:dashboard:dashboard","parameter":"unused","environments":["env"],"message":"message"},{"kind":"orphaned-file","path":"orphan.json","message":"message"}]`)
+}
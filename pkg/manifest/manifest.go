@@ -41,6 +41,33 @@ type EnvironmentDefinition struct {
 	url   UrlDefinition
 	Group string
 	Token
+	// Variables are the variables defined for the environment and its group. Variables of the environment
+	// override variables of the same name of the group.
+	Variables map[string]string
+	// GroupVariables are the variables defined for the group of the environment. They are contained in Variables as
+	// well, and only kept to write them back to the group.
+	GroupVariables map[string]string
+	// RateLimit are the rate limit settings defined for the environment, nil if none are defined
+	RateLimit *RateLimit
+}
+
+const (
+	// SimpleRateLimitStrategy only waits after the environment answered with HTTP 429
+	SimpleRateLimitStrategy = "simple"
+	// TokenBucketRateLimitStrategy limits requests using a token bucket before they are sent
+	TokenBucketRateLimitStrategy = "token-bucket"
+)
+
+// RateLimit are the rate limit settings of an environment, as defined in the manifest. They are applied to the client
+// created for the environment.
+type RateLimit struct {
+	// Strategy is the rate limiting strategy, either SimpleRateLimitStrategy or TokenBucketRateLimitStrategy. If
+	// empty, SimpleRateLimitStrategy is used
+	Strategy string
+	// RequestsPerSecond are the requests per second allowed by the TokenBucketRateLimitStrategy, 0 for the default
+	RequestsPerSecond float64
+	// Burst is the number of requests the TokenBucketRateLimitStrategy allows to be sent at once, 0 for the default
+	Burst int
 }
 
 type UrlType string
@@ -235,7 +235,7 @@ func toEnvironments(context *ManifestLoaderContext, groups []group) (map[string]
 		groupNames[group.Name] = true
 
 		for _, conf := range group.Environments {
-			env, configErrors := toEnvironment(context, conf, group.Name)
+			env, configErrors := toEnvironment(context, conf, group.Name, group.Variables)
 
 			if configErrors != nil {
 				errors = append(errors, configErrors...)
@@ -258,7 +258,7 @@ func toEnvironments(context *ManifestLoaderContext, groups []group) (map[string]
 	return environments, nil
 }
 
-func toEnvironment(context *ManifestLoaderContext, config environment, group string) (EnvironmentDefinition, []error) {
+func toEnvironment(context *ManifestLoaderContext, config environment, group string, groupVariables map[string]string) (EnvironmentDefinition, []error) {
 	var errors []error
 
 	token, err := parseToken(context, config, group, config.Token)
@@ -276,6 +276,11 @@ func toEnvironment(context *ManifestLoaderContext, config environment, group str
 		errors = append(errors, newManifestEnvironmentLoaderError(context.ManifestPath, group, config.Name, fmt.Sprintf("failed to parse URL %v", err)))
 	}
 
+	rateLimitSettings, err := parseRateLimit(config)
+	if err != nil {
+		errors = append(errors, newManifestEnvironmentLoaderError(context.ManifestPath, group, config.Name, err.Error()))
+	}
+
 	if len(errors) > 0 {
 		return EnvironmentDefinition{}, errors
 	}
@@ -286,11 +291,58 @@ func toEnvironment(context *ManifestLoaderContext, config environment, group str
 			Type:  urlType,
 			Value: strings.TrimSuffix(config.Url.Value, "/"),
 		},
-		Token: token,
-		Group: group,
+		Token:          token,
+		Group:          group,
+		Variables:      mergeVariables(groupVariables, config.Variables),
+		GroupVariables: groupVariables,
+		RateLimit:      rateLimitSettings,
 	}, nil
 }
 
+// parseRateLimit returns the rate limit settings of an environment, or nil if it does not define any
+func parseRateLimit(config environment) (*RateLimit, error) {
+	if config.RateLimit == nil {
+		return nil, nil
+	}
+
+	switch config.RateLimit.Strategy {
+	case "", SimpleRateLimitStrategy, TokenBucketRateLimitStrategy:
+	default:
+		return nil, fmt.Errorf("invalid `rateLimit`: unknown rate limit strategy %q, supported are %q and %q", config.RateLimit.Strategy, SimpleRateLimitStrategy, TokenBucketRateLimitStrategy)
+	}
+
+	if config.RateLimit.RequestsPerSecond < 0 {
+		return nil, fmt.Errorf("invalid `rateLimit`: rate limit requests per second must not be negative, but was %v", config.RateLimit.RequestsPerSecond)
+	}
+
+	if config.RateLimit.Burst < 0 {
+		return nil, fmt.Errorf("invalid `rateLimit`: rate limit burst must not be negative, but was %d", config.RateLimit.Burst)
+	}
+
+	return &RateLimit{
+		Strategy:          config.RateLimit.Strategy,
+		RequestsPerSecond: config.RateLimit.RequestsPerSecond,
+		Burst:             config.RateLimit.Burst,
+	}, nil
+}
+
+// mergeVariables returns the variables of an environment, which are the variables of its group overridden by the
+// variables defined for the environment itself
+func mergeVariables(groupVariables, environmentVariables map[string]string) map[string]string {
+	if len(groupVariables) == 0 && len(environmentVariables) == 0 {
+		return nil
+	}
+
+	variables := make(map[string]string, len(groupVariables)+len(environmentVariables))
+	for k, v := range groupVariables {
+		variables[k] = v
+	}
+	for k, v := range environmentVariables {
+		variables[k] = v
+	}
+	return variables
+}
+
 func extractUrlType(config environment) (UrlType, error) {
 	if config.Url.Type == "" || config.Url.Type == strings2.ToString(ValueUrlType) {
 		return ValueUrlType, nil
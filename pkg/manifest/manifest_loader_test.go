@@ -878,3 +878,77 @@ environmentGroups: [{name: b, environments: [{name: c, url: {value: d, type: f},
 		})
 	}
 }
+
+func TestLoadManifest_MergesGroupAndEnvironmentVariables(t *testing.T) {
+	manifestContent := `
+manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups:
+  - name: production
+    variables: {tenant: prod, email: ops@example.com, retries: 3}
+    environments:
+      - {name: prod-eu, url: {value: d}, token: {name: e}, variables: {tenant: prod-eu}}
+      - {name: prod-us, url: {value: d}, token: {name: e}}
+  - name: development
+    environments:
+      - {name: dev, url: {value: d}, token: {name: e}}
+`
+	fs := afero.NewMemMapFs()
+	assert.NilError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent), 0400))
+
+	m, errs := LoadManifest(&ManifestLoaderContext{
+		Fs:           fs,
+		ManifestPath: "manifest.yaml",
+	})
+	assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+
+	assert.DeepEqual(t, m.Environments["prod-eu"].Variables, map[string]string{"tenant": "prod-eu", "email": "ops@example.com", "retries": "3"})
+	assert.DeepEqual(t, m.Environments["prod-us"].Variables, map[string]string{"tenant": "prod", "email": "ops@example.com", "retries": "3"})
+	assert.Assert(t, m.Environments["dev"].Variables == nil)
+
+	assert.DeepEqual(t, m.Environments["prod-eu"].GroupVariables, map[string]string{"tenant": "prod", "email": "ops@example.com", "retries": "3"})
+	assert.Assert(t, m.Environments["dev"].GroupVariables == nil)
+}
+
+func TestLoadManifest_ParsesRateLimit(t *testing.T) {
+	manifestContent := `
+manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups:
+  - name: default
+    environments:
+      - {name: limited, url: {value: d}, token: {name: e}, rateLimit: {strategy: token-bucket, requestsPerSecond: 2.5, burst: 5}}
+      - {name: unlimited, url: {value: d}, token: {name: e}}
+`
+	fs := afero.NewMemMapFs()
+	assert.NilError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent), 0400))
+
+	m, errs := LoadManifest(&ManifestLoaderContext{
+		Fs:           fs,
+		ManifestPath: "manifest.yaml",
+	})
+	assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+
+	assert.DeepEqual(t, m.Environments["limited"].RateLimit, &RateLimit{Strategy: TokenBucketRateLimitStrategy, RequestsPerSecond: 2.5, Burst: 5})
+	assert.Assert(t, m.Environments["unlimited"].RateLimit == nil)
+}
+
+func TestLoadManifest_ReportsInvalidRateLimit(t *testing.T) {
+	manifestContent := `
+manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups:
+  - name: default
+    environments:
+      - {name: env, url: {value: d}, token: {name: e}, rateLimit: {strategy: leaky-bucket}}
+`
+	fs := afero.NewMemMapFs()
+	assert.NilError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent), 0400))
+
+	_, errs := LoadManifest(&ManifestLoaderContext{
+		Fs:           fs,
+		ManifestPath: "manifest.yaml",
+	})
+	assert.Equal(t, len(errs), 1, "unexpected errors: %v", errs)
+	assert.ErrorContains(t, errs[0], `unknown rate limit strategy "leaky-bucket"`)
+}
@@ -29,9 +29,17 @@ type tokenConfig struct {
 }
 
 type environment struct {
-	Name  string      `yaml:"name"`
-	Url   url         `yaml:"url"`
-	Token tokenConfig `yaml:"token"`
+	Name      string            `yaml:"name"`
+	Url       url               `yaml:"url"`
+	Token     tokenConfig       `yaml:"token"`
+	Variables map[string]string `yaml:"variables,omitempty"`
+	RateLimit *rateLimit        `yaml:"rateLimit,omitempty"`
+}
+
+type rateLimit struct {
+	Strategy          string  `yaml:"strategy,omitempty"`
+	RequestsPerSecond float64 `yaml:"requestsPerSecond,omitempty"`
+	Burst             int     `yaml:"burst,omitempty"`
 }
 
 type url struct {
@@ -40,8 +48,9 @@ type url struct {
 }
 
 type group struct {
-	Name         string        `yaml:"name"`
-	Environments []environment `yaml:"environments"`
+	Name         string            `yaml:"name"`
+	Variables    map[string]string `yaml:"variables,omitempty"`
+	Environments []environment     `yaml:"environments"`
 }
 
 type manifest struct {
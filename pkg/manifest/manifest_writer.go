@@ -110,24 +110,64 @@ func extractGroupedProjectDetails(projectDefinition ProjectDefinition) (groupNam
 
 func toWriteableEnvironmentGroups(environments map[string]EnvironmentDefinition) (result []group) {
 	environmentPerGroup := make(map[string][]environment)
+	variablesPerGroup := make(map[string]map[string]string)
+
+	for _, env := range environments {
+		if _, found := variablesPerGroup[env.Group]; !found && len(env.GroupVariables) > 0 {
+			variablesPerGroup[env.Group] = env.GroupVariables
+		}
+	}
 
 	for name, env := range environments {
 		e := environment{
-			Name:  name,
-			Url:   toWriteableUrl(env),
-			Token: toWritableToken(env),
+			Name:      name,
+			Url:       toWriteableUrl(env),
+			Token:     toWritableToken(env),
+			Variables: withoutGroupVariables(env.Variables, variablesPerGroup[env.Group]),
+			RateLimit: toWriteableRateLimit(env),
 		}
 
 		environmentPerGroup[env.Group] = append(environmentPerGroup[env.Group], e)
 	}
 
 	for g, envs := range environmentPerGroup {
-		result = append(result, group{Name: g, Environments: envs})
+		result = append(result, group{Name: g, Variables: variablesPerGroup[g], Environments: envs})
+	}
+
+	return result
+}
+
+// withoutGroupVariables returns the variables of an environment which are not already defined with the same value
+// by its group, so that variables are written back at the level they are defined at
+func withoutGroupVariables(variables, groupVariables map[string]string) map[string]string {
+	var result map[string]string
+
+	for k, v := range variables {
+		if groupValue, found := groupVariables[k]; found && groupValue == v {
+			continue
+		}
+
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[k] = v
 	}
 
 	return result
 }
 
+func toWriteableRateLimit(environment EnvironmentDefinition) *rateLimit {
+	if environment.RateLimit == nil {
+		return nil
+	}
+
+	return &rateLimit{
+		Strategy:          environment.RateLimit.Strategy,
+		RequestsPerSecond: environment.RateLimit.RequestsPerSecond,
+		Burst:             environment.RateLimit.Burst,
+	}
+}
+
 func toWriteableUrl(environment EnvironmentDefinition) url {
 	if environment.url.Type == EnvironmentUrlType {
 		return url{
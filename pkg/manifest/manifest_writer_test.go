@@ -213,6 +213,114 @@ func Test_toWriteableEnvironmentGroups(t *testing.T) {
 				},
 			},
 		},
+		{
+			"writes variables of environments",
+			map[string]EnvironmentDefinition{
+				"env1": {
+					Name: "env1",
+					url: UrlDefinition{
+						Value: "www.an.url",
+					},
+					Group:     "group1",
+					Token:     nil,
+					Variables: map[string]string{"tenant": "env1"},
+				},
+			},
+			[]group{
+				{
+					Name: "group1",
+					Environments: []environment{
+						{
+							Name: "env1",
+							Url:  url{Value: "www.an.url"},
+							Token: tokenConfig{
+								Config: map[string]interface{}{
+									"name": "env1_TOKEN",
+								},
+							},
+							Variables: map[string]string{"tenant": "env1"},
+						},
+					},
+				},
+			},
+		},
+		{
+			"writes variables of groups to the group",
+			map[string]EnvironmentDefinition{
+				"env1": {
+					Name:           "env1",
+					url:            UrlDefinition{Value: "www.an.url"},
+					Group:          "group1",
+					Variables:      map[string]string{"tenant": "env1", "email": "ops@example.com"},
+					GroupVariables: map[string]string{"tenant": "group", "email": "ops@example.com"},
+				},
+				"env2": {
+					Name:           "env2",
+					url:            UrlDefinition{Value: "www.an.url"},
+					Group:          "group1",
+					Variables:      map[string]string{"tenant": "group", "email": "ops@example.com"},
+					GroupVariables: map[string]string{"tenant": "group", "email": "ops@example.com"},
+				},
+			},
+			[]group{
+				{
+					Name:      "group1",
+					Variables: map[string]string{"tenant": "group", "email": "ops@example.com"},
+					Environments: []environment{
+						{
+							Name: "env1",
+							Url:  url{Value: "www.an.url"},
+							Token: tokenConfig{
+								Config: map[string]interface{}{
+									"name": "env1_TOKEN",
+								},
+							},
+							Variables: map[string]string{"tenant": "env1"},
+						},
+						{
+							Name: "env2",
+							Url:  url{Value: "www.an.url"},
+							Token: tokenConfig{
+								Config: map[string]interface{}{
+									"name": "env2_TOKEN",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			"writes rate limit of environments",
+			map[string]EnvironmentDefinition{
+				"env1": {
+					Name: "env1",
+					url: UrlDefinition{
+						Value: "www.an.url",
+					},
+					Group:     "group1",
+					Token:     nil,
+					RateLimit: &RateLimit{Strategy: TokenBucketRateLimitStrategy, RequestsPerSecond: 5},
+				},
+			},
+			[]group{
+				{
+					Name: "group1",
+					Environments: []environment{
+						{
+							Name: "env1",
+							Url:  url{Value: "www.an.url"},
+							Token: tokenConfig{
+								Config: map[string]interface{}{
+									"name": "env1_TOKEN",
+								},
+							},
+							RateLimit: &rateLimit{Strategy: "token-bucket", RequestsPerSecond: 5},
+						},
+					},
+				},
+			},
+		},
 		{
 			"returns empty groups for empty env defintion",
 			map[string]EnvironmentDefinition{},
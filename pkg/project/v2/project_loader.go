@@ -17,8 +17,10 @@ package v2
 import (
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/secret"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
 	"os"
+	"sort"
 	"strings"
 
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
@@ -34,6 +36,9 @@ type ProjectLoaderContext struct {
 	WorkingDir      string
 	Manifest        manifest.Manifest
 	ParametersSerde map[string]parameter.ParameterSerDe
+	// SecretKey loads the key used to decrypt secret parameters. If not set, the key is loaded once from the
+	// filesystem the projects are loaded from.
+	SecretKey *secret.KeyLoader
 }
 
 type DuplicateConfigIdentifierError struct {
@@ -63,10 +68,58 @@ func newDuplicateConfigIdentifierError(c config.Config) DuplicateConfigIdentifie
 	}
 }
 
+// UnknownDependencyError is returned if a config declares a dependency in `dependsOn` on a config that does not exist
+type UnknownDependencyError struct {
+	Config             coordinate.Coordinate
+	EnvironmentDetails configErrors.EnvironmentDetails
+	DependsOn          coordinate.Coordinate
+}
+
+func (e UnknownDependencyError) Coordinates() coordinate.Coordinate {
+	return e.Config
+}
+
+func (e UnknownDependencyError) LocationDetails() configErrors.EnvironmentDetails {
+	return e.EnvironmentDetails
+}
+
+func (e UnknownDependencyError) Error() string {
+	return fmt.Sprintf("dependsOn references unknown config `%s`", e.DependsOn)
+}
+
+// CircularExtendsError is returned if the `extends` of configs form a cycle
+type CircularExtendsError struct {
+	Config coordinate.Coordinate
+	// Cycle holds the coordinates of all configs of the cycle, starting and ending with Config
+	Cycle []coordinate.Coordinate
+}
+
+func (e CircularExtendsError) Coordinates() coordinate.Coordinate {
+	return e.Config
+}
+
+func (e CircularExtendsError) Error() string {
+	cycle := make([]string, len(e.Cycle))
+	for i, c := range e.Cycle {
+		cycle[i] = c.String()
+	}
+	return fmt.Sprintf("configs extend each other in a cycle: %s", strings.Join(cycle, " -> "))
+}
+
+var (
+	_ configErrors.DetailedConfigError = (*DuplicateConfigIdentifierError)(nil)
+	_ configErrors.DetailedConfigError = (*UnknownDependencyError)(nil)
+	_ configErrors.ConfigError         = (*CircularExtendsError)(nil)
+)
+
 func LoadProjects(fs afero.Fs, context ProjectLoaderContext) ([]Project, []error) {
 	environments := toEnvironmentSlice(context.Manifest.Environments)
 	projects := make([]Project, 0)
 
+	if context.SecretKey == nil {
+		context.SecretKey = secret.NewKeyLoader(fs)
+	}
+
 	var workingDirFs afero.Fs
 
 	if context.WorkingDir == "." {
@@ -75,10 +128,15 @@ func LoadProjects(fs afero.Fs, context ProjectLoaderContext) ([]Project, []error
 		workingDirFs = afero.NewBasePathFs(fs, context.WorkingDir)
 	}
 
+	definitions := loadDefinitions(workingDirFs, context.Manifest.Projects)
+	if errs := findExtendsCycles(definitions); errs != nil {
+		return nil, errs
+	}
+
 	var errors []error
 
 	for _, projectDefinition := range context.Manifest.Projects {
-		project, projectErrors := loadProject(workingDirFs, context, projectDefinition, environments)
+		project, projectErrors := loadProject(workingDirFs, context, projectDefinition, environments, definitions)
 
 		if projectErrors != nil {
 			errors = append(errors, projectErrors...)
@@ -92,9 +150,119 @@ func LoadProjects(fs afero.Fs, context ProjectLoaderContext) ([]Project, []error
 		return nil, errors
 	}
 
+	if errs := validateDependsOn(projects); errs != nil {
+		return nil, errs
+	}
+
 	return projects, nil
 }
 
+// validateDependsOn checks that all configs declared in the `dependsOn` of any config exist in the same environment
+func validateDependsOn(projects []Project) []error {
+	known := make(map[string]map[coordinate.Coordinate]struct{})
+
+	for _, p := range projects {
+		for env, configsPerType := range p.Configs {
+			if known[env] == nil {
+				known[env] = make(map[coordinate.Coordinate]struct{})
+			}
+			for _, configs := range configsPerType {
+				for _, c := range configs {
+					known[env][c.Coordinate] = struct{}{}
+				}
+			}
+		}
+	}
+
+	var errors []error
+	for _, p := range projects {
+		for env, configsPerType := range p.Configs {
+			for _, configs := range configsPerType {
+				for _, c := range configs {
+					for _, dep := range c.DependsOn {
+						if _, found := known[env][dep]; !found {
+							errors = append(errors, UnknownDependencyError{
+								Config:             c.Coordinate,
+								EnvironmentDetails: configErrors.EnvironmentDetails{Group: c.Group, Environment: c.Environment},
+								DependsOn:          dep,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return errors
+}
+
+// loadDefinitions loads the definitions of all configs of all projects, which are required to resolve `extends`
+// across files and projects. Errors are ignored, as they are reported when the configs are loaded.
+func loadDefinitions(fs afero.Fs, projects map[string]manifest.ProjectDefinition) config.Definitions {
+	result := make(config.Definitions)
+
+	for _, projectDefinition := range projects {
+		_ = walkConfigFolders(fs, projectDefinition.Path, func(path string) {
+			definitions, _ := config.LoadDefinitions(fs, projectDefinition.Name, path)
+			for _, d := range definitions {
+				result[d.Coordinate] = d
+			}
+		})
+	}
+
+	return result
+}
+
+// findExtendsCycles returns an error for each cycle formed by the `extends` of the given definitions
+func findExtendsCycles(definitions config.Definitions) []error {
+	coordinates := make([]coordinate.Coordinate, 0, len(definitions))
+	for c := range definitions {
+		coordinates = append(coordinates, c)
+	}
+	sort.Slice(coordinates, func(i, j int) bool {
+		return coordinates[i].String() < coordinates[j].String()
+	})
+
+	// each config extends at most one config, therefore each config is part of at most one cycle
+	inCycle := make(map[coordinate.Coordinate]struct{})
+	var errors []error
+
+	for _, start := range coordinates {
+		if _, found := inCycle[start]; found {
+			continue
+		}
+
+		var chain []coordinate.Coordinate
+		positions := make(map[coordinate.Coordinate]int)
+
+		current := start
+		for {
+			if i, found := positions[current]; found {
+				cycle := append(chain[i:], current)
+				for _, c := range cycle {
+					inCycle[c] = struct{}{}
+				}
+				errors = append(errors, CircularExtendsError{Config: current, Cycle: cycle})
+				break
+			}
+			if _, found := inCycle[current]; found {
+				break
+			}
+
+			positions[current] = len(chain)
+			chain = append(chain, current)
+
+			d, found := definitions[current]
+			if !found || d.Extends == nil {
+				break
+			}
+			current = *d.Extends
+		}
+	}
+
+	return errors
+}
+
 func toEnvironmentSlice(environments map[string]manifest.EnvironmentDefinition) []manifest.EnvironmentDefinition {
 	var result []manifest.EnvironmentDefinition
 
@@ -106,7 +274,7 @@ func toEnvironmentSlice(environments map[string]manifest.EnvironmentDefinition)
 }
 
 func loadProject(fs afero.Fs, context ProjectLoaderContext, projectDefinition manifest.ProjectDefinition,
-	environments []manifest.EnvironmentDefinition) (Project, []error) {
+	environments []manifest.EnvironmentDefinition, definitions config.Definitions) (Project, []error) {
 
 	exists, err := afero.Exists(fs, projectDefinition.Path)
 	if err != nil {
@@ -118,7 +286,7 @@ func loadProject(fs afero.Fs, context ProjectLoaderContext, projectDefinition ma
 
 	log.Debug("Loading project `%s` (%s)...", projectDefinition.Name, projectDefinition.Path)
 
-	configs, errors := loadConfigsOfProject(fs, context, projectDefinition, environments)
+	configs, errors := loadConfigsOfProject(fs, context, projectDefinition, environments, definitions)
 
 	if d := findDuplicatedConfigIdentifiers(configs); d != nil {
 		for _, c := range d {
@@ -148,41 +316,28 @@ func loadProject(fs afero.Fs, context ProjectLoaderContext, projectDefinition ma
 	}, nil
 }
 
-func loadConfigsOfProject(fs afero.Fs, context ProjectLoaderContext, projectDefinition manifest.ProjectDefinition, environments []manifest.EnvironmentDefinition) ([]config.Config, []error) {
+func loadConfigsOfProject(fs afero.Fs, context ProjectLoaderContext, projectDefinition manifest.ProjectDefinition,
+	environments []manifest.EnvironmentDefinition, definitions config.Definitions) ([]config.Config, []error) {
 	var configs []config.Config
 	var errors []error
 
-	err := afero.Walk(fs, projectDefinition.Path, func(path string, info os.FileInfo, err error) error {
-
-		if !info.IsDir() {
-			return nil
-		}
-
-		pathParts := strings.Split(path, string(os.PathSeparator))
-		anyHidden := slices.AnyMatches(pathParts, func(v string) bool {
-			return strings.HasPrefix(v, ".")
-		})
-
-		if anyHidden {
-			return nil
-		}
-
+	err := walkConfigFolders(fs, projectDefinition.Path, func(path string) {
 		loaded, errs := config.LoadConfigs(fs, &config.LoaderContext{
 			ProjectId:       projectDefinition.Name,
 			Path:            path,
 			Environments:    environments,
 			KnownApis:       context.KnownApis,
 			ParametersSerDe: context.ParametersSerde,
+			Definitions:     definitions,
+			SecretKey:       context.SecretKey,
 		})
 
 		if errs != nil {
 			errors = append(errors, errs...)
-			return nil
+			return
 		}
 
 		configs = append(configs, loaded...)
-
-		return nil
 	})
 
 	if err != nil {
@@ -192,6 +347,32 @@ func loadConfigsOfProject(fs afero.Fs, context ProjectLoaderContext, projectDefi
 	return configs, errors
 }
 
+// walkConfigFolders calls the given function for the given path and each folder below it, skipping hidden folders
+func walkConfigFolders(fs afero.Fs, root string, f func(path string)) error {
+	return afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		pathParts := strings.Split(path, string(os.PathSeparator))
+		anyHidden := slices.AnyMatches(pathParts, func(v string) bool {
+			return strings.HasPrefix(v, ".")
+		})
+
+		if anyHidden {
+			return nil
+		}
+
+		f(path)
+
+		return nil
+	})
+}
+
 func findDuplicatedConfigIdentifiers(configs []config.Config) []config.Config {
 
 	coordinates := make(map[string]struct{})
@@ -223,7 +404,7 @@ func toDependenciesMap(projectId string,
 			continue
 		}
 
-		for _, ref := range c.References() {
+		for _, ref := range c.Dependencies() {
 			// ignore project on same project
 			if projectId == ref.Project {
 				continue
@@ -21,9 +21,11 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/file"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
 	"github.com/spf13/afero"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -508,6 +510,195 @@ func TestLoadProjects_ReturnsErrOnOverlappingCoordinate_InSameFile(t *testing.T)
 	assert.Equal(t, len(gotErrs), 1, "Expected to fail on overlapping coordinates")
 }
 
+func TestLoadProjects_ResolvesDependsOnAcrossProjects(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "a/alerting-profile/profile.yaml", []byte("configs:\n- id: profile\n  config:\n    name: Test Profile\n    template: profile.json\n  type:\n    api: alerting-profile\n  dependsOn:\n  - [b, dashboard, dash]"), 0644)
+	_ = afero.WriteFile(testFs, "a/alerting-profile/profile.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(testFs, "b/dashboard/dash.yaml", []byte("configs:\n- id: dash\n  config:\n    name: Test Dash\n    template: dash.json\n  type:\n    api: dashboard"), 0644)
+	_ = afero.WriteFile(testFs, "b/dashboard/dash.json", []byte("{}"), 0644)
+
+	context := getSimpleProjectLoaderContext([]string{"a", "b"})
+
+	got, gotErrs := LoadProjects(testFs, context)
+	assert.Equal(t, len(gotErrs), 0, "Expected no errors loading dependent projects, but got: %v", gotErrs)
+
+	for _, p := range got {
+		if p.Id == "a" {
+			assert.DeepEqual(t, p.Dependencies["env"], []string{"b"})
+		}
+	}
+}
+
+func TestLoadProjects_ReturnsErrOnUnknownDependsOn(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.yaml", []byte("configs:\n- id: profile\n  config:\n    name: Test Profile\n    template: profile.json\n  type:\n    api: alerting-profile\n  dependsOn:\n  - [dashboard, missing]"), 0644)
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.json", []byte("{}"), 0644)
+
+	context := getSimpleProjectLoaderContext([]string{"project"})
+
+	_, gotErrs := LoadProjects(testFs, context)
+
+	assert.Equal(t, len(gotErrs), 1, "Expected to fail on unknown dependsOn")
+	assert.ErrorContains(t, gotErrs[0], "unknown config `project:dashboard:missing`")
+}
+
+func TestLoadProjects_ResolvesExtendsAcrossProjects(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "base/alerting-profile/profile.yaml", []byte(`
+configs:
+- id: base-profile
+  config:
+    name: Base Profile
+    template: profile.json
+    parameters:
+      severity: ERROR
+      threshold: 10
+  type:
+    api: alerting-profile
+  environmentOverrides:
+  - environment: env
+    override:
+      parameters:
+        threshold: 20`), 0644)
+	_ = afero.WriteFile(testFs, "base/alerting-profile/profile.json", []byte(`{"name": "{{ .name }}"}`), 0644)
+	_ = afero.WriteFile(testFs, "a/alerting-profile/profile.yaml", []byte(`
+configs:
+- id: middle
+  extends: base:alerting-profile:base-profile
+  config:
+    parameters:
+      severity: WARN
+  type:
+    api: alerting-profile
+- id: profile
+  extends: middle
+  config:
+    name: Extending Profile
+    parameters:
+      threshold: 30
+  type:
+    api: alerting-profile`), 0644)
+
+	context := getSimpleProjectLoaderContext([]string{"a", "base"})
+
+	got, gotErrs := LoadProjects(testFs, context)
+	assert.Equal(t, len(gotErrs), 0, "Expected no errors loading extending configs, but got: %v", gotErrs)
+
+	var profile config.Config
+	for _, p := range got {
+		for _, c := range p.Configs["env"]["alerting-profile"] {
+			if c.Coordinate.ConfigId == "profile" {
+				profile = c
+			}
+		}
+	}
+
+	assert.Equal(t, profile.Template.Content(), `{"name": "{{ .name }}"}`)
+	assert.DeepEqual(t, profile.Parameters["name"], &value.ValueParameter{Value: "Extending Profile"})
+	assert.DeepEqual(t, profile.Parameters["severity"], &value.ValueParameter{Value: "WARN"})
+	// environment overrides of extended configs take precedence over the base config of extending configs
+	assert.DeepEqual(t, profile.Parameters["threshold"], &value.ValueParameter{Value: 20})
+}
+
+func TestLoadProjects_ResolvesPathsOfExtendedConfigsInOtherFolders(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "base/alerting-profile/profile.yaml", []byte(`
+configs:
+- id: base-profile
+  config:
+    name: Base Profile
+    template: profile.json
+    parameters:
+      description:
+        type: file
+        path: description.txt
+  type:
+    api: alerting-profile
+  dependsOn:
+  - [base, dashboard, dash]
+  forEach:
+    file: items.csv
+    idProperty: level`), 0644)
+	_ = afero.WriteFile(testFs, "base/alerting-profile/profile.json", []byte(`{"name": "{{ .name }}"}`), 0644)
+	_ = afero.WriteFile(testFs, "base/alerting-profile/description.txt", []byte("base description"), 0644)
+	_ = afero.WriteFile(testFs, "base/alerting-profile/items.csv", []byte("level,threshold\nlow,10\n"), 0644)
+	_ = afero.WriteFile(testFs, "base/dashboard/dash.yaml", []byte("configs:\n- id: dash\n  config:\n    name: Dash\n    template: dash.json\n  type:\n    api: dashboard"), 0644)
+	_ = afero.WriteFile(testFs, "base/dashboard/dash.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(testFs, "a/alerting-profile/profile.yaml", []byte(`
+configs:
+- id: profile
+  extends: base:alerting-profile:base-profile
+  config:
+    name: Extending Profile
+  type:
+    api: alerting-profile`), 0644)
+
+	context := getSimpleProjectLoaderContext([]string{"a", "base"})
+
+	got, gotErrs := LoadProjects(testFs, context)
+	assert.Equal(t, len(gotErrs), 0, "Expected no errors loading extending configs, but got: %v", gotErrs)
+
+	var profiles []config.Config
+	for _, p := range got {
+		if p.Id == "a" {
+			profiles = p.Configs["env"]["alerting-profile"]
+		}
+	}
+
+	assert.Equal(t, len(profiles), 1)
+	profile := profiles[0]
+	assert.Equal(t, profile.Coordinate.ConfigId, "profile-low")
+	assert.Equal(t, profile.Template.Content(), `{"name": "{{ .name }}"}`)
+	assert.DeepEqual(t, profile.Parameters["threshold"], &value.ValueParameter{Value: "10"})
+	assert.DeepEqual(t, profile.DependsOn, []coordinate.Coordinate{{Project: "base", Type: "dashboard", ConfigId: "dash"}})
+
+	description, ok := profile.Parameters["description"].(*file.FileParameter)
+	assert.Assert(t, ok, "expected description to be a file parameter")
+	assert.Equal(t, description.Content, "base description")
+	assert.Equal(t, description.Folder, filepath.Join("base", "alerting-profile"))
+}
+
+func TestLoadProjects_ReturnsErrOnExtendsCycle(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.yaml", []byte(`
+configs:
+- id: a
+  extends: b
+  config:
+    name: A
+    template: profile.json
+  type:
+    api: alerting-profile
+- id: b
+  extends: project:alerting-profile:a
+  config:
+    name: B
+    template: profile.json
+  type:
+    api: alerting-profile`), 0644)
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.json", []byte("{}"), 0644)
+
+	context := getSimpleProjectLoaderContext([]string{"project"})
+
+	_, gotErrs := LoadProjects(testFs, context)
+
+	assert.Equal(t, len(gotErrs), 1, "Expected to fail on extends cycle")
+	assert.ErrorContains(t, gotErrs[0], "configs extend each other in a cycle: project:alerting-profile:a -> project:alerting-profile:b -> project:alerting-profile:a")
+}
+
+func TestLoadProjects_ReturnsErrOnUnknownExtends(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.yaml", []byte("configs:\n- id: profile\n  extends: missing\n  config:\n    name: Test Profile\n    template: profile.json\n  type:\n    api: alerting-profile"), 0644)
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.json", []byte("{}"), 0644)
+
+	context := getSimpleProjectLoaderContext([]string{"project"})
+
+	_, gotErrs := LoadProjects(testFs, context)
+
+	assert.Equal(t, len(gotErrs), 1, "Expected to fail on unknown extends")
+	assert.ErrorContains(t, gotErrs[0], "config extends unknown config `project:alerting-profile:missing`")
+}
+
 func Test_loadProject_returnsErrorIfProjectPathDoesNotExist(t *testing.T) {
 	fs := afero.NewMemMapFs()
 	ctx := ProjectLoaderContext{}
@@ -516,7 +707,7 @@ func Test_loadProject_returnsErrorIfProjectPathDoesNotExist(t *testing.T) {
 		Path: "this/does/not/exist",
 	}
 
-	_, gotErrs := loadProject(fs, ctx, definition, []manifest.EnvironmentDefinition{})
+	_, gotErrs := loadProject(fs, ctx, definition, []manifest.EnvironmentDefinition{}, config.Definitions{})
 	assert.Assert(t, len(gotErrs) == 1)
 	assert.ErrorContains(t, gotErrs[0], "filepath `this/does/not/exist` does not exist")
 }
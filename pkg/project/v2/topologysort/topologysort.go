@@ -89,11 +89,19 @@ type CircularDependencyConfigSortError struct {
 	Config      coordinate.Coordinate
 	Environment string
 	DependsOn   []coordinate.Coordinate
+	// ExplicitDependsOn holds the coordinates of DependsOn which are declared in the `dependsOn` of the config
+	ExplicitDependsOn []coordinate.Coordinate
 }
 
 func (e CircularDependencyConfigSortError) Error() string {
-	return fmt.Sprintf("%s:%s: is part of circular dependency.\n depends on: %s",
+	msg := fmt.Sprintf("%s:%s: is part of circular dependency.\n depends on: %s",
 		e.Environment, e.Config, joinCoordinatesToString(e.DependsOn))
+
+	if len(e.ExplicitDependsOn) > 0 {
+		msg += fmt.Sprintf("\n declared in dependsOn: %s", joinCoordinatesToString(e.ExplicitDependsOn))
+	}
+
+	return msg
 }
 
 func joinCoordinatesToString(coordinates []coordinate.Coordinate) string {
@@ -234,6 +242,34 @@ func GetSortedConfigsForEnvironments(projects []project.Project, environments []
 	return result, nil
 }
 
+// GroupConfigsByDependencyLevel splits the given sorted configs into dependency levels.
+// Configs of a level only depend on configs of previous levels, so all configs within the
+// same level are independent of each other and can be deployed concurrently.
+// NOTE: the given configs need to be sorted, e.g. by GetSortedConfigsForEnvironments
+func GroupConfigsByDependencyLevel(sortedConfigs []config.Config) [][]config.Config {
+	levelOfConfig := make(map[coordinate.Coordinate]int, len(sortedConfigs))
+	result := make([][]config.Config, 0)
+
+	for _, c := range sortedConfigs {
+		level := 0
+
+		for _, ref := range c.Dependencies() {
+			if refLevel, found := levelOfConfig[ref]; found && refLevel >= level {
+				level = refLevel + 1
+			}
+		}
+
+		levelOfConfig[c.Coordinate] = level
+
+		if level == len(result) {
+			result = append(result, make([]config.Config, 0))
+		}
+		result[level] = append(result[level], c)
+	}
+
+	return result
+}
+
 func getConfigs(m map[string][]config.Config) []config.Config {
 	result := make([]config.Config, 0)
 
@@ -306,16 +342,19 @@ func parseConfigSortErrors(sortErrs []sort.TopologySortError, configs []config.C
 		for _, index := range sortErr.UnresolvedIncomingEdgesFrom {
 			dependingConfig := configs[index]
 
-			if err, exists := depErrs[dependingConfig.Coordinate]; exists {
-				err.DependsOn = append(err.DependsOn, conf.Coordinate)
-				depErrs[dependingConfig.Coordinate] = err
-			} else {
-				depErrs[dependingConfig.Coordinate] = CircularDependencyConfigSortError{
+			err, exists := depErrs[dependingConfig.Coordinate]
+			if !exists {
+				err = CircularDependencyConfigSortError{
 					Config:      dependingConfig.Coordinate,
 					Environment: dependingConfig.Environment,
-					DependsOn:   []coordinate.Coordinate{conf.Coordinate},
 				}
 			}
+
+			err.DependsOn = append(err.DependsOn, conf.Coordinate)
+			if containsCoordinate(dependingConfig.DependsOn, conf.Coordinate) {
+				err.ExplicitDependsOn = append(err.ExplicitDependsOn, conf.Coordinate)
+			}
+			depErrs[dependingConfig.Coordinate] = err
 		}
 	}
 
@@ -396,10 +435,15 @@ func logDependency(prefix string, depending string, dependedOn string) {
 }
 
 // hasDependencyOn tests whether the config given by the first argument
-// has a dependency on the config given by the second argument
+// has a dependency on the config given by the second argument, either by
+// referencing it in a parameter or by declaring it in dependsOn
 func hasDependencyOn(from, to config.Config) bool {
-	for _, ref := range from.References() {
-		if to.Coordinate.Match(ref) {
+	return containsCoordinate(from.Dependencies(), to.Coordinate)
+}
+
+func containsCoordinate(coordinates []coordinate.Coordinate, c coordinate.Coordinate) bool {
+	for _, other := range coordinates {
+		if c.Match(other) {
 			return true
 		}
 	}
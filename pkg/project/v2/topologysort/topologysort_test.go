@@ -324,6 +324,56 @@ func TestSortConfigsShouldReportAllLinksOfCyclicDependency(t *testing.T) {
 	}
 }
 
+func TestSortConfigsWithDependsOn(t *testing.T) {
+	attribute := coordinate.Coordinate{Project: "project-1", Type: "request-attributes", ConfigId: "attribute"}
+	metric := coordinate.Coordinate{Project: "project-1", Type: "calculated-metrics-service", ConfigId: "metric"}
+
+	configs := []config.Config{
+		{Coordinate: metric, Environment: "development", DependsOn: []coordinate.Coordinate{attribute}},
+		{Coordinate: attribute, Environment: "development"},
+	}
+
+	sorted, errs := sortConfigs(configs)
+	assert.Equal(t, len(errs), 0, "expected zero errors when sorting")
+
+	indexMetric := indexOfConfig(t, sorted, metric)
+	indexAttribute := indexOfConfig(t, sorted, attribute)
+	assert.Assert(t, indexAttribute < indexMetric, "depended on config (index %d) should be before config (index %d)", indexAttribute, indexMetric)
+}
+
+func TestSortConfigsShouldReportDependsOnInCyclicDependency(t *testing.T) {
+	attribute := coordinate.Coordinate{Project: "project-1", Type: "request-attributes", ConfigId: "attribute"}
+	metric := coordinate.Coordinate{Project: "project-1", Type: "calculated-metrics-service", ConfigId: "metric"}
+
+	configs := []config.Config{
+		{Coordinate: metric, Environment: "development", DependsOn: []coordinate.Coordinate{attribute}},
+		{
+			Coordinate:  attribute,
+			Environment: "development",
+			Parameters: config.Parameters{
+				"p": parameter.NewDummy(metric),
+			},
+		},
+	}
+
+	_, errs := sortConfigs(configs)
+	assert.Equal(t, len(errs), 2, "should report an error for each config")
+
+	for _, err := range errs {
+		depErr, ok := err.(CircularDependencyConfigSortError)
+		assert.Assert(t, ok, "expected errors of type CircularDependencyConfigSortError")
+
+		if depErr.Config.Match(metric) {
+			assert.DeepEqual(t, depErr.DependsOn, []coordinate.Coordinate{attribute})
+			assert.DeepEqual(t, depErr.ExplicitDependsOn, []coordinate.Coordinate{attribute})
+			assert.ErrorContains(t, depErr, "declared in dependsOn: project-1:request-attributes:attribute")
+		} else {
+			assert.DeepEqual(t, depErr.DependsOn, []coordinate.Coordinate{metric})
+			assert.Equal(t, len(depErr.ExplicitDependsOn), 0)
+		}
+	}
+}
+
 func TestSortConfigsShouldNotFailOnCyclicDependencyWhichAreSkip(t *testing.T) {
 	configCoordinates := coordinate.Coordinate{
 		Project:  "project-1",
@@ -693,6 +743,30 @@ func TestHasDependencyOn(t *testing.T) {
 	assert.Assert(t, result, "should have dependency")
 }
 
+func TestHasDependencyOnWithDependsOn(t *testing.T) {
+	dependedOn := config.Config{
+		Coordinate: coordinate.Coordinate{
+			Project:  "project1",
+			Type:     "request-attributes",
+			ConfigId: "attribute",
+		},
+		Environment: "dev",
+	}
+
+	conf := config.Config{
+		Coordinate: coordinate.Coordinate{
+			Project:  "project1",
+			Type:     "calculated-metrics-service",
+			ConfigId: "metric",
+		},
+		Environment: "dev",
+		DependsOn:   []coordinate.Coordinate{dependedOn.Coordinate},
+	}
+
+	assert.Assert(t, hasDependencyOn(conf, dependedOn), "should have dependency")
+	assert.Assert(t, !hasDependencyOn(dependedOn, conf), "should not have dependency")
+}
+
 func TestHasDependencyOnShouldReturnFalseIfNoDependenciesAreDefined(t *testing.T) {
 	conf := config.Config{
 		Coordinate: coordinate.Coordinate{
@@ -716,3 +790,38 @@ func TestHasDependencyOnShouldReturnFalseIfNoDependenciesAreDefined(t *testing.T
 
 	assert.Assert(t, !result, "should not have dependency")
 }
+
+func TestGroupConfigsByDependencyLevel(t *testing.T) {
+	tag := coordinate.Coordinate{Project: "project1", Type: "auto-tag", ConfigId: "tag"}
+	zone := coordinate.Coordinate{Project: "project1", Type: "management-zone", ConfigId: "zone"}
+	profile := coordinate.Coordinate{Project: "project1", Type: "alerting-profile", ConfigId: "profile"}
+	dashboard := coordinate.Coordinate{Project: "project1", Type: "dashboard", ConfigId: "dashboard"}
+
+	sortedConfigs := []config.Config{
+		{Coordinate: tag},
+		{Coordinate: zone, Parameters: config.Parameters{"p": parameter.NewDummy(tag)}},
+		{Coordinate: profile},
+		{Coordinate: dashboard, Parameters: config.Parameters{"p": parameter.NewDummy(zone), "q": parameter.NewDummy(profile)}},
+	}
+
+	levels := GroupConfigsByDependencyLevel(sortedConfigs)
+
+	assert.Equal(t, len(levels), 3)
+	assert.DeepEqual(t, coordinatesOf(levels[0]), []coordinate.Coordinate{tag, profile})
+	assert.DeepEqual(t, coordinatesOf(levels[1]), []coordinate.Coordinate{zone})
+	assert.DeepEqual(t, coordinatesOf(levels[2]), []coordinate.Coordinate{dashboard})
+}
+
+func TestGroupConfigsByDependencyLevelWithNoConfigs(t *testing.T) {
+	levels := GroupConfigsByDependencyLevel([]config.Config{})
+
+	assert.Equal(t, len(levels), 0)
+}
+
+func coordinatesOf(configs []config.Config) []coordinate.Coordinate {
+	result := make([]coordinate.Coordinate, len(configs))
+	for i, c := range configs {
+		result[i] = c.Coordinate
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package report records the outcome of deploying each config, and writes it in machine-readable formats for
+// further processing, e.g. by CI systems.
+package report
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	configErrors "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/errors"
+	"sync"
+	"time"
+)
+
+// Action describes what was done with a config during a deployment
+type Action string
+
+const (
+	// ActionDeployed is recorded for configs that were successfully deployed, if it is unknown whether the object
+	// was created or updated
+	ActionDeployed Action = "deployed"
+	// ActionCreated is recorded for configs that were deployed to a new object
+	ActionCreated Action = "created"
+	// ActionUpdated is recorded for configs that were deployed to an existing object
+	ActionUpdated Action = "updated"
+	// ActionUnchanged is recorded for configs whose existing object already matched the config, and was not updated
+	ActionUnchanged Action = "unchanged"
+	// ActionValidated is recorded for configs that were successfully validated during a dry-run
+	ActionValidated Action = "validated"
+	// ActionSkipped is recorded for configs that are not deployed, e.g. because they are marked as skipped
+	ActionSkipped Action = "skipped"
+	// ActionFailed is recorded for configs that failed to deploy
+	ActionFailed Action = "failed"
+	// ActionNotRun is recorded for configs whose deployment was not attempted, as the deployment was stopped after an
+	// error of another config
+	ActionNotRun Action = "not-run"
+	// ActionRolledBack is recorded for configs that were deployed, but whose object was restored or deleted again
+	// because the deployment failed
+	ActionRolledBack Action = "rolled-back"
+)
+
+// Error is the structured representation of an error that occurred while deploying a config
+type Error struct {
+	Message string
+	// Coordinate is set if the error is a configErrors.ConfigError
+	Coordinate *coordinate.Coordinate
+	// Group and Environment are set if the error is a configErrors.DetailedConfigError
+	Group       string
+	Environment string
+}
+
+// NewError converts the given error into its structured representation
+func NewError(err error) Error {
+	result := Error{Message: log.Redact(errutils.ErrorString(err))}
+
+	var configErr configErrors.ConfigError
+	if errors.As(err, &configErr) {
+		c := configErr.Coordinates()
+		result.Coordinate = &c
+	}
+
+	var detailedErr configErrors.DetailedConfigError
+	if errors.As(err, &detailedErr) {
+		details := detailedErr.LocationDetails()
+		result.Group = details.Group
+		result.Environment = details.Environment
+	}
+
+	return result
+}
+
+// Record holds the outcome of deploying a single config to an environment
+type Record struct {
+	Environment string
+	Coordinate  coordinate.Coordinate
+	Action      Action
+	// ObjectId is the ID of the Dynatrace object the config was deployed to, if any
+	ObjectId string
+	Duration time.Duration
+	Errors   []Error
+}
+
+// Recorder collects the records of a deployment. It is safe to be used concurrently by multiple goroutines.
+type Recorder struct {
+	lock    sync.Mutex
+	records []Record
+	errors  []Error
+}
+
+// NewRecorder creates a new empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Record adds the outcome of deploying a config to the recorder
+func (r *Recorder) Record(record Record) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.records = append(r.records, record)
+}
+
+// UpdateAction changes the action of the records of the given config in the given environment
+func (r *Recorder) UpdateAction(environment string, c coordinate.Coordinate, action Action) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for i := range r.records {
+		if r.records[i].Environment == environment && r.records[i].Coordinate == c {
+			r.records[i].Action = action
+		}
+	}
+}
+
+// RecordError adds an error not related to a single config, e.g. failing to connect to an environment
+func (r *Recorder) RecordError(err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.errors = append(r.errors, NewError(err))
+}
+
+// Records returns a copy of all records in the order they were added
+func (r *Recorder) Records() []Record {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]Record(nil), r.records...)
+}
+
+// CountByAction returns the number of recorded configs per action
+func (r *Recorder) CountByAction() map[Action]int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	counts := make(map[Action]int)
+	for _, rec := range r.records {
+		counts[rec.Action]++
+	}
+	return counts
+}
+
+// Errors returns a copy of all errors not related to a single config
+func (r *Recorder) Errors() []Error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]Error(nil), r.errors...)
+}
@@ -0,0 +1,189 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	configErrors "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/errors"
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"testing"
+	"time"
+)
+
+var testCoordinate = coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "my-dashboard"}
+
+func TestNewError(t *testing.T) {
+	err := fmt.Errorf("failed to deploy: %w", configErrors.InvalidJsonError{
+		Config:             testCoordinate,
+		EnvironmentDetails: configErrors.EnvironmentDetails{Group: "group", Environment: "env"},
+		WrappedError:       errors.New("invalid json"),
+	})
+
+	got := NewError(err)
+	assert.Equal(t, got.Message, "failed to deploy: invalid json")
+	assert.DeepEqual(t, got.Coordinate, &testCoordinate)
+	assert.Equal(t, got.Group, "group")
+	assert.Equal(t, got.Environment, "env")
+}
+
+func TestNewErrorWithGeneralError(t *testing.T) {
+	got := NewError(errors.New("some error"))
+	assert.DeepEqual(t, got, Error{Message: "some error"})
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("JUnit")
+	assert.NilError(t, err)
+	assert.Equal(t, f, FormatJUnit)
+
+	_, err = ParseFormat("yaml")
+	assert.ErrorContains(t, err, "unsupported report format")
+}
+
+func newTestRecorder() *Recorder {
+	r := NewRecorder()
+	r.Record(Record{
+		Environment: "env",
+		Coordinate:  testCoordinate,
+		Action:      ActionDeployed,
+		ObjectId:    "object-id",
+		Duration:    1500 * time.Millisecond,
+	})
+	r.Record(Record{
+		Environment: "env",
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "skipped"},
+		Action:      ActionSkipped,
+	})
+	r.Record(Record{
+		Environment: "env",
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "failed"},
+		Action:      ActionFailed,
+		Errors:      []Error{{Message: "deployment failed", Group: "group", Environment: "env"}},
+	})
+	r.RecordError(errors.New("cannot find environment `other`"))
+	return r
+}
+
+func TestRecorderCountByAction(t *testing.T) {
+	assert.DeepEqual(t, newTestRecorder().CountByAction(), map[Action]int{
+		ActionDeployed: 1,
+		ActionSkipped:  1,
+		ActionFailed:   1,
+	})
+}
+
+func TestRecorderUpdateAction(t *testing.T) {
+	r := newTestRecorder()
+	r.UpdateAction("env", testCoordinate, ActionRolledBack)
+	r.UpdateAction("other", testCoordinate, ActionFailed)
+
+	assert.DeepEqual(t, r.CountByAction(), map[Action]int{
+		ActionRolledBack: 1,
+		ActionSkipped:    1,
+		ActionFailed:     1,
+	})
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NilError(t, WriteJSON(&buf, newTestRecorder()))
+
+	expected := `{
+  "configs": [
+    {
+      "environment": "env",
+      "project": "project",
+      "type": "dashboard",
+      "configId": "my-dashboard",
+      "action": "deployed",
+      "objectId": "object-id",
+      "durationMs": 1500
+    },
+    {
+      "environment": "env",
+      "project": "project",
+      "type": "dashboard",
+      "configId": "skipped",
+      "action": "skipped",
+      "durationMs": 0
+    },
+    {
+      "environment": "env",
+      "project": "project",
+      "type": "dashboard",
+      "configId": "failed",
+      "action": "failed",
+      "durationMs": 0,
+      "errors": [
+        {
+          "message": "deployment failed",
+          "group": "group",
+          "environment": "env"
+        }
+      ]
+    }
+  ],
+  "errors": [
+    {
+      "message": "cannot find environment ` + "`other`" + `"
+    }
+  ]
+}
+`
+	assert.Equal(t, buf.String(), expected)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NilError(t, WriteJUnit(&buf, newTestRecorder()))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="monaco" tests="4" failures="2" skipped="1" time="1.500">
+  <testsuite name="env" tests="3" failures="1" skipped="1" time="1.500">
+    <testcase classname="project.dashboard" name="my-dashboard" time="1.500">
+      <system-out>deployed object object-id</system-out>
+    </testcase>
+    <testcase classname="project.dashboard" name="skipped" time="0.000">
+      <skipped></skipped>
+    </testcase>
+    <testcase classname="project.dashboard" name="failed" time="0.000">
+      <failure message="deployment failed">env(group) deployment failed</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="general" tests="1" failures="1" skipped="0" time="0.000">
+    <testcase classname="general" name="error-1" time="0.000">
+      <failure message="cannot find environment ` + "`other`" + `">cannot find environment ` + "`other`" + `</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	assert.Equal(t, buf.String(), expected)
+}
+
+func TestWriteFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	assert.NilError(t, WriteFile(fs, "report.xml", FormatJUnit, newTestRecorder()))
+
+	content, err := afero.ReadFile(fs, "report.xml")
+	assert.NilError(t, err)
+	assert.Assert(t, bytes.HasPrefix(content, []byte("<?xml")))
+}
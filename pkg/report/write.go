@@ -0,0 +1,281 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/spf13/afero"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Format is a supported output format of a report
+type Format string
+
+const (
+	FormatJSON  Format = "json"
+	FormatJUnit Format = "junit"
+)
+
+// Formats lists all supported report formats
+var Formats = []Format{FormatJSON, FormatJUnit}
+
+// ParseFormat returns the Format of the given name, or an error if the format is not supported
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported report format %q, supported formats are %v", name, Formats)
+}
+
+// WriteFile writes the report of the given recorder in the given format to the file at the given path
+func WriteFile(fs afero.Fs, path string, format Format, r *Recorder) error {
+	f, err := fs.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file %q: %w", path, err)
+	}
+
+	switch format {
+	case FormatJUnit:
+		err = WriteJUnit(f, r)
+	default:
+		err = WriteJSON(f, r)
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write report file %q: %w", path, err)
+	}
+	return nil
+}
+
+type jsonReport struct {
+	Configs []jsonRecord `json:"configs"`
+	Errors  []jsonError  `json:"errors,omitempty"`
+}
+
+type jsonRecord struct {
+	Environment string      `json:"environment"`
+	Project     string      `json:"project"`
+	Type        string      `json:"type"`
+	ConfigId    string      `json:"configId"`
+	Action      Action      `json:"action"`
+	ObjectId    string      `json:"objectId,omitempty"`
+	DurationMs  int64       `json:"durationMs"`
+	Errors      []jsonError `json:"errors,omitempty"`
+}
+
+type jsonError struct {
+	Message     string          `json:"message"`
+	Coordinate  *jsonCoordinate `json:"coordinate,omitempty"`
+	Group       string          `json:"group,omitempty"`
+	Environment string          `json:"environment,omitempty"`
+}
+
+type jsonCoordinate struct {
+	Project  string `json:"project"`
+	Type     string `json:"type"`
+	ConfigId string `json:"configId"`
+}
+
+// WriteJSON writes the report of the given recorder as JSON
+func WriteJSON(w io.Writer, r *Recorder) error {
+	report := jsonReport{
+		Configs: make([]jsonRecord, 0),
+		Errors:  toJsonErrors(r.Errors()),
+	}
+
+	for _, rec := range r.Records() {
+		report.Configs = append(report.Configs, jsonRecord{
+			Environment: rec.Environment,
+			Project:     rec.Coordinate.Project,
+			Type:        rec.Coordinate.Type,
+			ConfigId:    rec.Coordinate.ConfigId,
+			Action:      rec.Action,
+			ObjectId:    rec.ObjectId,
+			DurationMs:  rec.Duration.Milliseconds(),
+			Errors:      toJsonErrors(rec.Errors),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func toJsonErrors(errs []Error) []jsonError {
+	var result []jsonError
+	for _, e := range errs {
+		je := jsonError{
+			Message:     e.Message,
+			Group:       e.Group,
+			Environment: e.Environment,
+		}
+		if e.Coordinate != nil {
+			je.Coordinate = &jsonCoordinate{
+				Project:  e.Coordinate.Project,
+				Type:     e.Coordinate.Type,
+				ConfigId: e.Coordinate.ConfigId,
+			}
+		}
+		result = append(result, je)
+	}
+	return result
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Details string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report of the given recorder as JUnit XML. Each environment is represented as test suite,
+// each config as test case. Errors not related to a single config are reported as failed test cases of the
+// suite "general".
+func WriteJUnit(w io.Writer, r *Recorder) error {
+	suitesByEnv := make(map[string]*junitTestSuite)
+	secondsByEnv := make(map[string]float64)
+	var envNames []string
+
+	for _, rec := range r.Records() {
+		suite, found := suitesByEnv[rec.Environment]
+		if !found {
+			suite = &junitTestSuite{Name: rec.Environment}
+			suitesByEnv[rec.Environment] = suite
+			envNames = append(envNames, rec.Environment)
+		}
+
+		testCase := junitTestCase{
+			ClassName: rec.Coordinate.Project + "." + rec.Coordinate.Type,
+			Name:      rec.Coordinate.ConfigId,
+			Time:      formatSeconds(rec.Duration.Seconds()),
+		}
+
+		switch {
+		case len(rec.Errors) > 0:
+			testCase.Failure = junitFailureOf(rec.Errors)
+			suite.Failures++
+		case rec.Action == ActionSkipped || rec.Action == ActionNotRun:
+			testCase.Skipped = &struct{}{}
+			suite.Skipped++
+		case rec.ObjectId != "":
+			testCase.SystemOut = fmt.Sprintf("%s object %s", rec.Action, rec.ObjectId)
+		}
+
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+		secondsByEnv[rec.Environment] += rec.Duration.Seconds()
+	}
+
+	sort.Strings(envNames)
+
+	result := junitTestSuites{Name: "monaco"}
+	var totalSeconds float64
+	for _, name := range envNames {
+		suite := suitesByEnv[name]
+		suite.Time = formatSeconds(secondsByEnv[name])
+		totalSeconds += secondsByEnv[name]
+		result.Suites = append(result.Suites, *suite)
+	}
+
+	if errs := r.Errors(); len(errs) > 0 {
+		general := junitTestSuite{Name: "general", Time: formatSeconds(0)}
+		for i, e := range errs {
+			general.TestCases = append(general.TestCases, junitTestCase{
+				ClassName: "general",
+				Name:      fmt.Sprintf("error-%d", i+1),
+				Time:      formatSeconds(0),
+				Failure:   junitFailureOf([]Error{e}),
+			})
+		}
+		general.Tests, general.Failures = len(errs), len(errs)
+		result.Suites = append(result.Suites, general)
+	}
+
+	for i := range result.Suites {
+		result.Tests += result.Suites[i].Tests
+		result.Failures += result.Suites[i].Failures
+		result.Skipped += result.Suites[i].Skipped
+	}
+	result.Time = formatSeconds(totalSeconds)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitFailureOf(errs []Error) *junitFailure {
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		msg := e.Message
+		if e.Environment != "" {
+			msg = fmt.Sprintf("%s(%s) %s", e.Environment, e.Group, msg)
+		}
+		messages = append(messages, msg)
+	}
+
+	return &junitFailure{
+		Message: errs[0].Message,
+		Details: strings.Join(messages, "\n"),
+	}
+}
+
+func formatSeconds(s float64) string {
+	return fmt.Sprintf("%.3f", s)
+}
@@ -32,11 +32,14 @@ type rateLimitStrategy interface {
 	executeRequest(timelineProvider timeutils.TimelineProvider, callback func() (Response, error)) (Response, error)
 }
 
-// createRateLimitStrategy creates a rateLimitStrategy. In the future this can be extended to instantiate
-// different rate limiting strategies based on e.g. environment variables. The current implementation
-// always returns the strategy simpleSleepRateLimitStrategy, which suspends the current goroutine until
-// the time in the rate limiting header 'X-RateLimit-Reset' is up.
-func createRateLimitStrategy() rateLimitStrategy {
+// createRateLimitStrategy returns the rateLimitStrategy to use for requests executed by the given client.
+// Clients created using NewRateLimitedClient carry their strategy in their transport, so that all goroutines
+// using the same client share it. For any other client the strategy simpleSleepRateLimitStrategy is returned,
+// which suspends the current goroutine until the time in the rate limiting header 'X-RateLimit-Reset' is up.
+func createRateLimitStrategy(client *http.Client) rateLimitStrategy {
+	if t, ok := client.Transport.(*rateLimitedTransport); ok {
+		return t.strategy
+	}
 	return &simpleSleepRateLimitStrategy{}
 }
 
@@ -74,6 +77,8 @@ func (s *simpleSleepRateLimitStrategy) executeRequest(timelineProvider timeutils
 		log.Info("simpleSleepRateLimitStrategy: Attempting to sleep until %s", humanReadableTimestamp)
 
 		log.Debug("simpleSleepRateLimitStrategy: Sleeping for %f seconds...", sleepDuration.Seconds())
+		statistics.recordThrottled()
+		statistics.recordWait(sleepDuration)
 		timelineProvider.Sleep(sleepDuration)
 		log.Debug("simpleSleepRateLimitStrategy: Slept for %f seconds", sleepDuration.Seconds())
 
@@ -0,0 +1,202 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimitStrategyType names a rate limiting strategy that can be chosen per environment
+type RateLimitStrategyType string
+
+const (
+	// SimpleSleepRateLimitStrategy only reacts to HTTP 429 responses by sleeping until the rate limit is reset
+	SimpleSleepRateLimitStrategy RateLimitStrategyType = "simple"
+	// TokenBucketRateLimitStrategy limits requests client-side using a token bucket before the server has to
+	TokenBucketRateLimitStrategy RateLimitStrategyType = "token-bucket"
+)
+
+const (
+	// RateLimitStrategyEnvKey selects the rate limiting strategy for all environments not configuring one in the manifest
+	RateLimitStrategyEnvKey = "MONACO_RATE_LIMIT_STRATEGY"
+	// RateLimitRequestsPerSecondEnvKey sets the requests per second allowed by the token-bucket strategy
+	RateLimitRequestsPerSecondEnvKey = "MONACO_RATE_LIMIT_REQUESTS_PER_SECOND"
+	// RateLimitBurstEnvKey sets the number of requests the token-bucket strategy allows to be sent at once
+	RateLimitBurstEnvKey = "MONACO_RATE_LIMIT_BURST"
+)
+
+const defaultRequestsPerSecond = 10
+
+// RateLimitSettings configure how requests to an environment are rate limited
+type RateLimitSettings struct {
+	// Strategy is the rate limiting strategy to use. If empty, SimpleSleepRateLimitStrategy is used
+	Strategy RateLimitStrategyType
+	// RequestsPerSecond is the rate the token bucket is refilled with. If 0, a default of 10 is used
+	RequestsPerSecond float64
+	// Burst is the capacity of the token bucket. If 0, RequestsPerSecond rounded up is used
+	Burst int
+}
+
+// Validate returns an error if the settings contain an unknown strategy or negative limits
+func (s RateLimitSettings) Validate() error {
+	switch s.Strategy {
+	case "", SimpleSleepRateLimitStrategy, TokenBucketRateLimitStrategy:
+	default:
+		return fmt.Errorf("unknown rate limit strategy %q, supported are %q and %q", s.Strategy, SimpleSleepRateLimitStrategy, TokenBucketRateLimitStrategy)
+	}
+
+	if s.RequestsPerSecond < 0 {
+		return fmt.Errorf("rate limit requests per second must not be negative, but was %v", s.RequestsPerSecond)
+	}
+
+	if s.Burst < 0 {
+		return fmt.Errorf("rate limit burst must not be negative, but was %d", s.Burst)
+	}
+
+	return nil
+}
+
+// RateLimitSettingsFromEnv reads the RateLimitSettings from the environment variables RateLimitStrategyEnvKey,
+// RateLimitRequestsPerSecondEnvKey and RateLimitBurstEnvKey. Unset variables keep their default.
+func RateLimitSettingsFromEnv() (RateLimitSettings, error) {
+	settings := RateLimitSettings{
+		Strategy: RateLimitStrategyType(os.Getenv(RateLimitStrategyEnvKey)),
+	}
+
+	if val, found := os.LookupEnv(RateLimitRequestsPerSecondEnvKey); found && val != "" {
+		rps, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return RateLimitSettings{}, fmt.Errorf("environment variable %s is not a number: %w", RateLimitRequestsPerSecondEnvKey, err)
+		}
+		settings.RequestsPerSecond = rps
+	}
+
+	if val, found := os.LookupEnv(RateLimitBurstEnvKey); found && val != "" {
+		burst, err := strconv.Atoi(val)
+		if err != nil {
+			return RateLimitSettings{}, fmt.Errorf("environment variable %s is not an integer: %w", RateLimitBurstEnvKey, err)
+		}
+		settings.Burst = burst
+	}
+
+	if err := settings.Validate(); err != nil {
+		return RateLimitSettings{}, fmt.Errorf("invalid rate limit settings in environment variables: %w", err)
+	}
+
+	return settings, nil
+}
+
+// NewRateLimitedClient returns an http.Client applying the rate limiting strategy of the given settings to all
+// requests executed through it. All goroutines using the returned client share one rate limiter, e.g. the budget
+// of the token bucket.
+// For the SimpleSleepRateLimitStrategy, which needs no shared state, the given client is returned unchanged.
+func NewRateLimitedClient(client *http.Client, settings RateLimitSettings) (*http.Client, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	if settings.Strategy == "" || settings.Strategy == SimpleSleepRateLimitStrategy {
+		return client, nil
+	}
+
+	requestsPerSecond := settings.RequestsPerSecond
+	if requestsPerSecond == 0 {
+		requestsPerSecond = defaultRequestsPerSecond
+	}
+
+	burst := settings.Burst
+	if burst == 0 {
+		burst = int(math.Ceil(requestsPerSecond))
+	}
+
+	log.Debug("Limiting requests to %v per second with a burst of %d", requestsPerSecond, burst)
+
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	rateLimited := *client
+	rateLimited.Transport = &rateLimitedTransport{
+		next:     next,
+		strategy: newTokenBucketRateLimitStrategy(requestsPerSecond, burst),
+	}
+	return &rateLimited, nil
+}
+
+// rateLimitedTransport attaches a rateLimitStrategy to an http.Client. The requests themselves are passed on to
+// the wrapped transport unchanged, the strategy is picked up by createRateLimitStrategy.
+type rateLimitedTransport struct {
+	next     http.RoundTripper
+	strategy rateLimitStrategy
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req)
+}
+
+// RateLimitStatistics summarizes how much rate limiting slowed down the requests of the current run
+type RateLimitStatistics struct {
+	// ThrottledRequests is the number of requests the server answered with HTTP 429 (Too Many Requests)
+	ThrottledRequests int64
+	// DelayedRequests is the number of requests that had to wait before being sent
+	DelayedRequests int64
+	// WaitTime is the time all requests spent waiting, summed up across goroutines
+	WaitTime time.Duration
+}
+
+type rateLimitStatistics struct {
+	throttled atomic.Int64
+	delayed   atomic.Int64
+	waitTime  atomic.Int64
+}
+
+var statistics rateLimitStatistics
+
+func (s *rateLimitStatistics) recordThrottled() {
+	s.throttled.Add(1)
+}
+
+func (s *rateLimitStatistics) recordWait(d time.Duration) {
+	s.delayed.Add(1)
+	s.waitTime.Add(int64(d))
+}
+
+// GetRateLimitStatistics returns the RateLimitStatistics of all requests executed so far
+func GetRateLimitStatistics() RateLimitStatistics {
+	return RateLimitStatistics{
+		ThrottledRequests: statistics.throttled.Load(),
+		DelayedRequests:   statistics.delayed.Load(),
+		WaitTime:          time.Duration(statistics.waitTime.Load()),
+	}
+}
+
+// LogRateLimitStatistics logs the RateLimitStatistics of the current run, if any request was rate limited
+func LogRateLimitStatistics() {
+	s := GetRateLimitStatistics()
+	if s.ThrottledRequests == 0 && s.DelayedRequests == 0 {
+		return
+	}
+	log.Info("Rate limiting: %d request(s) were throttled by the server, %d request(s) waited for %s in total", s.ThrottledRequests, s.DelayedRequests, s.WaitTime.Round(time.Millisecond))
+}
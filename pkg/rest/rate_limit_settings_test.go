@@ -0,0 +1,118 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"gotest.tools/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRateLimitSettingsFromEnv(t *testing.T) {
+	t.Setenv(RateLimitStrategyEnvKey, "token-bucket")
+	t.Setenv(RateLimitRequestsPerSecondEnvKey, "2.5")
+	t.Setenv(RateLimitBurstEnvKey, "4")
+
+	settings, err := RateLimitSettingsFromEnv()
+
+	assert.NilError(t, err)
+	assert.Equal(t, settings, RateLimitSettings{Strategy: TokenBucketRateLimitStrategy, RequestsPerSecond: 2.5, Burst: 4})
+}
+
+func TestRateLimitSettingsFromEnvDefaultsToSimpleStrategy(t *testing.T) {
+	t.Setenv(RateLimitStrategyEnvKey, "")
+	t.Setenv(RateLimitRequestsPerSecondEnvKey, "")
+	t.Setenv(RateLimitBurstEnvKey, "")
+
+	settings, err := RateLimitSettingsFromEnv()
+
+	assert.NilError(t, err)
+	assert.Equal(t, settings, RateLimitSettings{})
+}
+
+func TestRateLimitSettingsFromEnvReturnsErrors(t *testing.T) {
+	tests := []struct {
+		name, strategy, requestsPerSecond, burst, wantErr string
+	}{
+		{"unknown strategy", "leaky-bucket", "", "", `unknown rate limit strategy "leaky-bucket"`},
+		{"invalid requests per second", "token-bucket", "fast", "", RateLimitRequestsPerSecondEnvKey},
+		{"negative requests per second", "token-bucket", "-1", "", "must not be negative"},
+		{"invalid burst", "token-bucket", "", "1.5", RateLimitBurstEnvKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(RateLimitStrategyEnvKey, tt.strategy)
+			t.Setenv(RateLimitRequestsPerSecondEnvKey, tt.requestsPerSecond)
+			t.Setenv(RateLimitBurstEnvKey, tt.burst)
+
+			_, err := RateLimitSettingsFromEnv()
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewRateLimitedClientKeepsClientForSimpleStrategy(t *testing.T) {
+	client := &http.Client{}
+
+	got, err := NewRateLimitedClient(client, RateLimitSettings{Strategy: SimpleSleepRateLimitStrategy})
+
+	assert.NilError(t, err)
+	assert.Equal(t, got, client)
+	_, isSimple := createRateLimitStrategy(got).(*simpleSleepRateLimitStrategy)
+	assert.Assert(t, isSimple)
+}
+
+func TestNewRateLimitedClientSharesTokenBucketForAllRequests(t *testing.T) {
+	got, err := NewRateLimitedClient(&http.Client{}, RateLimitSettings{Strategy: TokenBucketRateLimitStrategy, RequestsPerSecond: 2.5})
+	assert.NilError(t, err)
+
+	strategy, isTokenBucket := createRateLimitStrategy(got).(*tokenBucketRateLimitStrategy)
+	assert.Assert(t, isTokenBucket)
+	assert.Equal(t, strategy.requestsPerSecond, 2.5)
+	assert.Equal(t, strategy.burst, float64(3), "burst defaults to the requests per second rounded up")
+	assert.Equal(t, createRateLimitStrategy(got), rateLimitStrategy(strategy))
+}
+
+func TestRetriesOfRateLimitedClientTakeTokens(t *testing.T) {
+	setRetryPolicy(t, noWaitRetryPolicy)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// refilling the bucket is negligible during the test
+	client, err := NewRateLimitedClient(server.Client(), RateLimitSettings{Strategy: TokenBucketRateLimitStrategy, RequestsPerSecond: 0.0001, Burst: 5})
+	assert.NilError(t, err)
+
+	resp, err := Get(client, server.URL, "token")
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, calls, 2)
+
+	strategy := createRateLimitStrategy(client).(*tokenBucketRateLimitStrategy)
+	assert.Assert(t, strategy.tokens < 3.01, "expected the request and its retry to take a token each, but %v tokens are left", strategy.tokens)
+}
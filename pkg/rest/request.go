@@ -18,6 +18,7 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/timeutils"
@@ -75,6 +76,19 @@ func Post(client *http.Client, url string, data []byte, apiToken string) (Respon
 	return executeRequest(client, req)
 }
 
+// PostIdempotent sends a POST request like Post, but marks it as idempotent, so that it is retried on transient
+// errors. Only use it for endpoints where sending the same request twice has the same effect as sending it once,
+// e.g. upserting settings objects or validating configs.
+func PostIdempotent(client *http.Client, url string, data []byte, apiToken string) (Response, error) {
+	req, err := requestWithBody(http.MethodPost, url, bytes.NewBuffer(data), apiToken)
+
+	if err != nil {
+		return Response{}, err
+	}
+
+	return executeRequest(client, req.WithContext(context.WithValue(req.Context(), idempotentRequestKey{}, true)))
+}
+
 func PostMultiPartFile(client *http.Client, url string, data *bytes.Buffer, contentType string, apiToken string) (Response, error) {
 	req, err := requestWithBody(http.MethodPost, url, data, apiToken)
 
@@ -118,9 +132,8 @@ func requestWithBody(method string, url string, body io.Reader, apiToken string)
 }
 
 func executeRequest(client *http.Client, request *http.Request) (Response, error) {
-	var requestId string
+	requestId := uuid.NewString()
 	if log.IsRequestLoggingActive() {
-		requestId = uuid.NewString()
 		err := log.LogRequest(requestId, request)
 
 		if err != nil {
@@ -128,37 +141,25 @@ func executeRequest(client *http.Client, request *http.Request) (Response, error
 		}
 	}
 
-	rateLimitStrategy := createRateLimitStrategy()
-
-	response, err := rateLimitStrategy.executeRequest(timeutils.NewTimelineProvider(), func() (Response, error) {
-		resp, err := client.Do(request)
-		if err != nil {
-			log.Error("HTTP Request failed with Error: " + err.Error())
-			return Response{}, err
-		}
-		defer func() {
-			err = resp.Body.Close()
-		}()
-		body, err := io.ReadAll(resp.Body)
-
-		if log.IsResponseLoggingActive() {
-			err := log.LogResponse(requestId, resp, string(body))
-
-			if err != nil {
-				if requestId != "" {
-					log.Warn("error while writing response log for id `%s`: %v", requestId, err)
-				} else {
-					log.Warn("error while writing response log: %v", requestId, err)
+	timelineProvider := timeutils.NewTimelineProvider()
+	rateLimitStrategy := createRateLimitStrategy(client)
+
+	// every attempt, including retries, is subject to the rate limiting strategy
+	sent := false
+	response, err := retryPolicy.execute(timelineProvider, requestId, request, func() (Response, error) {
+		return rateLimitStrategy.executeRequest(timelineProvider, func() (Response, error) {
+			// the body of a request is consumed by sending it, so it has to be restored for every further attempt
+			if sent && request.GetBody != nil {
+				body, err := request.GetBody()
+				if err != nil {
+					return Response{}, err
 				}
+				request.Body = body
 			}
-		}
+			sent = true
 
-		return Response{
-			StatusCode:  resp.StatusCode,
-			Body:        body,
-			Headers:     resp.Header,
-			NextPageKey: GetNextPageKeyIfExists(body),
-		}, err
+			return send(client, request, requestId)
+		})
 	})
 
 	if err != nil {
@@ -166,3 +167,30 @@ func executeRequest(client *http.Client, request *http.Request) (Response, error
 	}
 	return response, nil
 }
+
+func send(client *http.Client, request *http.Request, requestId string) (Response, error) {
+	resp, err := client.Do(request)
+	if err != nil {
+		log.Error("HTTP Request failed with Error: " + err.Error())
+		return Response{}, err
+	}
+	defer func() {
+		err = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+
+	if log.IsResponseLoggingActive() {
+		err := log.LogResponse(requestId, resp, string(body))
+
+		if err != nil {
+			log.Warn("error while writing response log for id `%s`: %v", requestId, err)
+		}
+	}
+
+	return Response{
+		StatusCode:  resp.StatusCode,
+		Body:        body,
+		Headers:     resp.Header,
+		NextPageKey: GetNextPageKeyIfExists(body),
+	}, err
+}
@@ -27,6 +27,8 @@ import (
 )
 
 func Test_deleteConfig(t *testing.T) {
+	setRetryPolicy(t, noWaitRetryPolicy)
+
 	tests := []struct {
 		name            string
 		givenStatusCode int
@@ -162,3 +164,59 @@ func Test_sendWithRetryReturnContainsHttpErrorIfNotSuccess(t *testing.T) {
 	assert.ErrorContains(t, err, "400")
 	assert.ErrorContains(t, err, "{ err: 'failed to create thing'}")
 }
+
+func Test_sendWithRetryRetriesServerErrorsForAllRetriesOfTheSetting(t *testing.T) {
+	setRetryPolicy(t, RetryPolicy{MaxRetries: 1})
+
+	i := 0
+	mockCall := SendingRequest(func(client *http.Client, url string, data []byte, apiToken string) (Response, error) {
+		i++
+		return Response{StatusCode: http.StatusServiceUnavailable}, nil
+	})
+
+	_, err := SendWithRetry(nil, mockCall, "dont matter", "some/path", []byte("body"), "token", RetrySetting{MaxRetries: 6})
+	assert.ErrorContains(t, err, "503")
+	assert.Equal(t, i, 6)
+}
+
+func Test_getWithRetryRetriesServerErrors(t *testing.T) {
+	setRetryPolicy(t, RetryPolicy{})
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls < 3 {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := GetWithRetry(server.Client(), server.URL, "token", RetrySetting{MaxRetries: 3})
+
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, calls, 3)
+}
+
+func Test_getWithRetryRetriesRejectedRequests(t *testing.T) {
+	setRetryPolicy(t, noWaitRetryPolicy)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls < 3 {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := GetWithRetry(server.Client(), server.URL, "token", RetrySetting{MaxRetries: 3})
+
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, calls, 3)
+}
@@ -49,8 +49,9 @@ var DefaultRetrySettings = RetrySettings{
 	},
 }
 
-// GetWithRetry will retry a GET request for a given number of times, waiting a give duration between calls
-// this method can be used for API calls we know to have occasional timing issues on GET - e.g. paginated queries that are impacted by replication lag, returning unequal amounts of objects/pages per node
+// GetWithRetry will retry a GET request for a given number of times, waiting a given duration between calls.
+// this method can be used for API calls we know to have occasional timing issues on GET - e.g. paginated queries that are impacted by replication lag, returning unequal amounts of objects/pages per node.
+// Every single call is retried according to the RetryPolicy on transient errors first.
 func GetWithRetry(client *http.Client, url string, apiToken string, settings RetrySetting) (resp Response, err error) {
 	resp, err = Get(client, url, apiToken)
 
@@ -76,7 +77,8 @@ func GetWithRetry(client *http.Client, url string, apiToken string, settings Ret
 	return Response{}, retryErr
 }
 
-// SendWithRetry will retry a SendingRequest(PUT or POST) for a given number of times, waiting a give duration between calls
+// SendWithRetry will retry a SendingRequest(PUT or POST) for a given number of times, waiting a given duration between calls.
+// Every single call is retried according to the RetryPolicy on transient errors first.
 func SendWithRetry(client *http.Client, restCall SendingRequest, objectName string, path string, body []byte, apiToken string, setting RetrySetting) (resp Response, err error) {
 
 	for i := 0; i < setting.MaxRetries; i++ {
@@ -96,13 +98,3 @@ func SendWithRetry(client *http.Client, restCall SendingRequest, objectName stri
 	}
 	return Response{}, retryErr
 }
-
-// SendWithRetryWithInitialTry will try to send a request and later retry a SendingRequest(PUT or POST) for a given number of times, waiting a give duration between calls
-func SendWithRetryWithInitialTry(client *http.Client, restCall SendingRequest, objectName string, path string, body []byte, apiToken string, setting RetrySetting) (resp Response, err error) {
-	resp, err = restCall(client, path, body, apiToken)
-	if err == nil && resp.IsSuccess() {
-		return resp, err
-	}
-
-	return SendWithRetry(client, restCall, objectName, path, body, apiToken, setting)
-}
@@ -0,0 +1,202 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/timeutils"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy defines how requests failing with a transient error are retried. It is applied to every request
+// executed by this package. Each attempt, including retries, passes the rate limiting strategy of the client.
+//
+// Retries wait for a random duration between zero and an upper bound that starts at InitialInterval and doubles
+// with every retry, capped at MaxInterval (exponential backoff with full jitter).
+// POST requests are only retried if they are marked as idempotent, see PostIdempotent.
+//
+// Server errors (HTTP 5xx) and connection resets are considered to be transient. Retries of known timing issues done
+// by GetWithRetry and SendWithRetry are not bounded by the policy, every single call of theirs is retried by it though.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried. 0 disables retrying
+	MaxRetries int
+	// InitialInterval is the upper bound of the wait time before the first retry
+	InitialInterval time.Duration
+	// MaxInterval caps the upper bound of the wait time between two attempts
+	MaxInterval time.Duration
+	// MaxElapsedTime is the time after which a request is not retried anymore. 0 means no limit
+	MaxElapsedTime time.Duration
+}
+
+const (
+	// RetryMaxRetriesEnvKey sets RetryPolicy.MaxRetries
+	RetryMaxRetriesEnvKey = "MONACO_RETRY_MAX_RETRIES"
+	// RetryInitialIntervalEnvKey sets RetryPolicy.InitialInterval, e.g. '500ms'
+	RetryInitialIntervalEnvKey = "MONACO_RETRY_INITIAL_INTERVAL"
+	// RetryMaxIntervalEnvKey sets RetryPolicy.MaxInterval, e.g. '30s'
+	RetryMaxIntervalEnvKey = "MONACO_RETRY_MAX_INTERVAL"
+	// RetryMaxElapsedTimeEnvKey sets RetryPolicy.MaxElapsedTime, e.g. '2m'
+	RetryMaxElapsedTimeEnvKey = "MONACO_RETRY_MAX_ELAPSED_TIME"
+)
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:      5,
+	InitialInterval: 1 * time.Second,
+	MaxInterval:     30 * time.Second,
+	MaxElapsedTime:  2 * time.Minute,
+}
+
+// retryPolicy is the RetryPolicy applied to all requests, see SetRetryPolicy
+var retryPolicy = DefaultRetryPolicy
+
+// SetRetryPolicy sets the RetryPolicy applied to all requests. It is meant to be called once during startup,
+// before any request is executed.
+func SetRetryPolicy(policy RetryPolicy) {
+	retryPolicy = policy
+}
+
+// Validate returns an error if any of the limits of the policy is negative
+func (p RetryPolicy) Validate() error {
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("max retries must not be negative, but was %d", p.MaxRetries)
+	}
+	if p.InitialInterval < 0 || p.MaxInterval < 0 || p.MaxElapsedTime < 0 {
+		return errors.New("retry intervals and max elapsed time must not be negative")
+	}
+	return nil
+}
+
+// RetryPolicyFromEnv returns the DefaultRetryPolicy, overridden by the environment variables RetryMaxRetriesEnvKey,
+// RetryInitialIntervalEnvKey, RetryMaxIntervalEnvKey and RetryMaxElapsedTimeEnvKey
+func RetryPolicyFromEnv() (RetryPolicy, error) {
+	policy := DefaultRetryPolicy
+
+	if val, found := os.LookupEnv(RetryMaxRetriesEnvKey); found && val != "" {
+		maxRetries, err := strconv.Atoi(val)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("environment variable %s is not an integer: %w", RetryMaxRetriesEnvKey, err)
+		}
+		policy.MaxRetries = maxRetries
+	}
+
+	durations := []struct {
+		envKey string
+		target *time.Duration
+	}{
+		{RetryInitialIntervalEnvKey, &policy.InitialInterval},
+		{RetryMaxIntervalEnvKey, &policy.MaxInterval},
+		{RetryMaxElapsedTimeEnvKey, &policy.MaxElapsedTime},
+	}
+	for _, d := range durations {
+		if val, found := os.LookupEnv(d.envKey); found && val != "" {
+			duration, err := time.ParseDuration(val)
+			if err != nil {
+				return RetryPolicy{}, fmt.Errorf("environment variable %s is not a duration: %w", d.envKey, err)
+			}
+			*d.target = duration
+		}
+	}
+
+	if err := policy.Validate(); err != nil {
+		return RetryPolicy{}, fmt.Errorf("invalid retry policy in environment variables: %w", err)
+	}
+
+	return policy, nil
+}
+
+// execute sends a request using the given send function and retries it as long as it fails with a transient error
+// and neither the max number of retries nor the max elapsed time are exceeded.
+// The response or error of the last attempt is returned.
+func (p RetryPolicy) execute(timelineProvider timeutils.TimelineProvider, requestId string, request *http.Request, send func() (Response, error)) (Response, error) {
+	start := timelineProvider.Now()
+
+	for retry := 0; ; retry++ {
+		response, err := send()
+
+		reason, retryable := p.isRetryable(request, response, err)
+		if !retryable {
+			return response, err
+		}
+
+		if retry >= p.MaxRetries {
+			log.Warn("Request %s: %s %s failed with %s, giving up after %d retries", requestId, request.Method, request.URL, reason, retry)
+			return response, err
+		}
+
+		wait := p.backoff(retry)
+		if p.MaxElapsedTime > 0 && timelineProvider.Now().Add(wait).Sub(start) > p.MaxElapsedTime {
+			log.Warn("Request %s: %s %s failed with %s, giving up after %s", requestId, request.Method, request.URL, reason, p.MaxElapsedTime)
+			return response, err
+		}
+
+		log.Warn("Request %s: %s %s failed with %s, retrying in %s (retry %d of %d)", requestId, request.Method, request.URL, reason, wait.Round(time.Millisecond), retry+1, p.MaxRetries)
+		timelineProvider.Sleep(wait)
+	}
+}
+
+// isRetryable returns whether a request that resulted in the given response or error should be retried, and a
+// human-readable reason why it failed
+func (p RetryPolicy) isRetryable(request *http.Request, response Response, err error) (reason string, retryable bool) {
+	if err != nil {
+		return err.Error(), isIdempotent(request) && isConnectionReset(err)
+	}
+	return fmt.Sprintf("HTTP %d", response.StatusCode), isIdempotent(request) && isServerError(response)
+}
+
+// backoff returns a random duration between zero and the upper bound of the given retry
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	upperBound := p.InitialInterval
+	for i := 0; i < retry && upperBound < p.MaxInterval; i++ {
+		upperBound *= 2
+	}
+	if upperBound > p.MaxInterval {
+		upperBound = p.MaxInterval
+	}
+
+	if upperBound <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(upperBound) + 1)) //nolint:gosec
+}
+
+func isServerError(response Response) bool {
+	return response.StatusCode >= 500 && response.StatusCode <= 599
+}
+
+func isConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+type idempotentRequestKey struct{}
+
+// isIdempotent returns whether sending the request multiple times has the same effect as sending it once
+func isIdempotent(request *http.Request) bool {
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	idempotent, _ := request.Context().Value(idempotentRequestKey{}).(bool)
+	return idempotent
+}
@@ -0,0 +1,219 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// noWaitRetryPolicy retries like the DefaultRetryPolicy, but without waiting in between
+var noWaitRetryPolicy = RetryPolicy{
+	MaxRetries: DefaultRetryPolicy.MaxRetries,
+}
+
+func setRetryPolicy(t *testing.T, policy RetryPolicy) {
+	previous := retryPolicy
+	SetRetryPolicy(policy)
+	t.Cleanup(func() {
+		SetRetryPolicy(previous)
+	})
+}
+
+func TestBackoffStaysWithinExponentialUpperBound(t *testing.T) {
+	p := RetryPolicy{InitialInterval: time.Second, MaxInterval: 5 * time.Second}
+
+	for i := 0; i < 100; i++ {
+		assert.Assert(t, p.backoff(0) <= time.Second)
+		assert.Assert(t, p.backoff(1) <= 2*time.Second)
+		assert.Assert(t, p.backoff(2) <= 4*time.Second)
+		assert.Assert(t, p.backoff(3) <= 5*time.Second)
+		assert.Assert(t, p.backoff(30) <= 5*time.Second)
+	}
+}
+
+func TestBackoffIsJittered(t *testing.T) {
+	p := RetryPolicy{InitialInterval: time.Second, MaxInterval: time.Second}
+
+	producedDurations := map[time.Duration]bool{}
+	for i := 0; i < 100; i++ {
+		producedDurations[p.backoff(0)] = true
+	}
+	assert.Assert(t, len(producedDurations) > 1, "expected backoff to produce random durations")
+}
+
+func TestRetryPolicyRetriesTransientErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		response  Response
+		err       error
+		wantTries int
+	}{
+		{"GET with bad gateway", http.MethodGet, Response{StatusCode: http.StatusBadGateway}, nil, 3},
+		{"PUT with service unavailable", http.MethodPut, Response{StatusCode: http.StatusServiceUnavailable}, nil, 3},
+		{"DELETE with gateway timeout", http.MethodDelete, Response{StatusCode: http.StatusGatewayTimeout}, nil, 3},
+		{"GET with connection reset", http.MethodGet, Response{}, &url.Error{Op: "Get", Err: io.EOF}, 3},
+		{"GET with internal server error", http.MethodGet, Response{StatusCode: http.StatusInternalServerError}, nil, 3},
+		{"GET with not found", http.MethodGet, Response{StatusCode: http.StatusNotFound}, nil, 1},
+		{"GET with other error", http.MethodGet, Response{}, fmt.Errorf("no such host"), 1},
+		{"POST with service unavailable", http.MethodPost, Response{StatusCode: http.StatusServiceUnavailable}, nil, 1},
+		{"POST with connection reset", http.MethodPost, Response{}, &url.Error{Op: "Post", Err: io.EOF}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := RetryPolicy{MaxRetries: 2}
+			request, err := http.NewRequest(tt.method, "http://localhost", nil)
+			assert.NilError(t, err)
+
+			timelineProvider := createTimelineProviderMock(t)
+			timelineProvider.EXPECT().Now().AnyTimes().Return(time.Unix(0, 0))
+			timelineProvider.EXPECT().Sleep(time.Duration(0)).Times(tt.wantTries - 1)
+
+			tries := 0
+			_, _ = p.execute(timelineProvider, "request-id", request, func() (Response, error) {
+				tries++
+				return tt.response, tt.err
+			})
+
+			assert.Equal(t, tries, tt.wantTries)
+		})
+	}
+}
+
+func TestRetryPolicyReturnsFirstSuccessfulResponse(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 5}
+	request, err := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	assert.NilError(t, err)
+
+	timelineProvider := createTimelineProviderMock(t)
+	timelineProvider.EXPECT().Now().AnyTimes().Return(time.Unix(0, 0))
+	timelineProvider.EXPECT().Sleep(gomock.Any()).Times(1)
+
+	tries := 0
+	response, err := p.execute(timelineProvider, "request-id", request, func() (Response, error) {
+		tries++
+		if tries == 1 {
+			return Response{StatusCode: http.StatusServiceUnavailable}, nil
+		}
+		return Response{StatusCode: http.StatusOK}, nil
+	})
+
+	assert.NilError(t, err)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+	assert.Equal(t, tries, 2)
+}
+
+func TestRetryPolicyGivesUpAfterMaxElapsedTime(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 100, InitialInterval: time.Minute, MaxInterval: time.Minute, MaxElapsedTime: 90 * time.Second}
+	request, err := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	assert.NilError(t, err)
+
+	now := time.Unix(0, 0)
+	timelineProvider := createTimelineProviderMock(t)
+	timelineProvider.EXPECT().Now().AnyTimes().DoAndReturn(func() time.Time { return now })
+	timelineProvider.EXPECT().Sleep(gomock.Any()).AnyTimes().Do(func(d time.Duration) { now = now.Add(d) })
+
+	response, _ := p.execute(timelineProvider, "request-id", request, func() (Response, error) {
+		return Response{StatusCode: http.StatusServiceUnavailable}, nil
+	})
+
+	assert.Equal(t, response.StatusCode, http.StatusServiceUnavailable)
+	assert.Assert(t, now.Sub(time.Unix(0, 0)) <= 90*time.Second)
+}
+
+func TestPostIdempotentIsRetriedWithFullBody(t *testing.T) {
+	setRetryPolicy(t, noWaitRetryPolicy)
+
+	var receivedBodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		receivedBodies = append(receivedBodies, string(body))
+		if len(receivedBodies) < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := PostIdempotent(server.Client(), server.URL, []byte(`{"value": 42}`), "token")
+
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.DeepEqual(t, receivedBodies, []string{`{"value": 42}`, `{"value": 42}`, `{"value": 42}`})
+}
+
+func TestPostIsNotRetried(t *testing.T) {
+	setRetryPolicy(t, noWaitRetryPolicy)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	resp, err := Post(server.Client(), server.URL, []byte("{}"), "token")
+
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusServiceUnavailable)
+	assert.Equal(t, calls, 1)
+}
+
+func TestRetryPolicyFromEnv(t *testing.T) {
+	t.Setenv(RetryMaxRetriesEnvKey, "7")
+	t.Setenv(RetryInitialIntervalEnvKey, "200ms")
+	t.Setenv(RetryMaxIntervalEnvKey, "")
+	t.Setenv(RetryMaxElapsedTimeEnvKey, "5m")
+
+	policy, err := RetryPolicyFromEnv()
+
+	assert.NilError(t, err)
+	assert.Equal(t, policy.MaxRetries, 7)
+	assert.Equal(t, policy.InitialInterval, 200*time.Millisecond)
+	assert.Equal(t, policy.MaxInterval, DefaultRetryPolicy.MaxInterval)
+	assert.Equal(t, policy.MaxElapsedTime, 5*time.Minute)
+}
+
+func TestRetryPolicyFromEnvReturnsErrors(t *testing.T) {
+	tests := []struct {
+		name, envKey, value, wantErr string
+	}{
+		{"invalid max retries", RetryMaxRetriesEnvKey, "many", RetryMaxRetriesEnvKey},
+		{"negative max retries", RetryMaxRetriesEnvKey, "-1", "must not be negative"},
+		{"invalid duration", RetryMaxElapsedTimeEnvKey, "10", RetryMaxElapsedTimeEnvKey},
+		{"negative duration", RetryInitialIntervalEnvKey, "-1s", "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.envKey, tt.value)
+
+			_, err := RetryPolicyFromEnv()
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
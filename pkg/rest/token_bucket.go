@@ -0,0 +1,147 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/timeutils"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxThrottledRetries is the number of times the tokenBucketRateLimitStrategy retries a request the server
+// answered with HTTP 429
+const maxThrottledRetries = 10
+
+// tokenBucketRateLimitStrategy is a rate limiting strategy which limits requests client-side before they are sent.
+// Each request takes a token out of a bucket that holds at most burst tokens and is refilled with requestsPerSecond.
+// If the bucket is empty, the request waits until a token becomes available.
+//
+// The bucket adapts to the rate limit headers returned by the server: If the server reports that no requests are
+// remaining ('X-RateLimit-Remaining: 0') or answers with HTTP 429, the bucket is drained and only refilled after
+// the time in 'X-RateLimit-Reset', holding back the requests of all goroutines sharing it.
+type tokenBucketRateLimitStrategy struct {
+	mutex             sync.Mutex
+	requestsPerSecond float64
+	burst             float64
+	// tokens available in the bucket. Negative if requests already reserved tokens that are yet to be refilled
+	tokens float64
+	// refilled is the time up to which tokens were refilled. Lies in the future if the bucket is paused
+	refilled time.Time
+	// sleep is used to determine wait times from rate limit headers
+	sleep simpleSleepRateLimitStrategy
+}
+
+func newTokenBucketRateLimitStrategy(requestsPerSecond float64, burst int) *tokenBucketRateLimitStrategy {
+	return &tokenBucketRateLimitStrategy{
+		requestsPerSecond: requestsPerSecond,
+		burst:             float64(burst),
+		tokens:            float64(burst),
+	}
+}
+
+func (s *tokenBucketRateLimitStrategy) executeRequest(timelineProvider timeutils.TimelineProvider, callback func() (Response, error)) (Response, error) {
+	for iteration := 0; ; iteration++ {
+		if wait := s.reserve(timelineProvider.Now()); wait > 0 {
+			log.Debug("tokenBucketRateLimitStrategy: Waiting for %f seconds...", wait.Seconds())
+			statistics.recordWait(wait)
+			timelineProvider.Sleep(wait)
+		}
+
+		response, err := callback()
+		if err != nil {
+			return Response{}, err
+		}
+
+		if response.StatusCode != http.StatusTooManyRequests {
+			s.adaptToRemainingRequests(response, timelineProvider.Now())
+			return response, nil
+		}
+
+		statistics.recordThrottled()
+		if iteration >= maxThrottledRetries {
+			return response, nil
+		}
+
+		sleepDuration, humanReadableTimestamp, err := s.sleep.getSleepDurationFromResponseHeader(response, timelineProvider)
+		if err != nil {
+			log.Debug("Failed to Get rate limiting details from API response, generating wait time instead...")
+			sleepDuration, humanReadableTimestamp = s.sleep.generateSleepDuration(iteration, timelineProvider)
+		}
+		sleepDuration = s.sleep.applyMinMaxDefaults(sleepDuration)
+
+		log.Info("Rate limit reached: Applying rate limit strategy (tokenBucketRateLimitStrategy, iteration: %d)", iteration+1)
+		log.Info("tokenBucketRateLimitStrategy: Pausing requests until %s", humanReadableTimestamp)
+		s.pauseUntil(timelineProvider.Now().Add(sleepDuration))
+	}
+}
+
+// reserve takes a token out of the bucket and returns how long the caller has to wait until it may send its request
+func (s *tokenBucketRateLimitStrategy) reserve(now time.Time) time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if now.After(s.refilled) {
+		s.tokens = math.Min(s.burst, s.tokens+now.Sub(s.refilled).Seconds()*s.requestsPerSecond)
+		s.refilled = now
+	}
+
+	s.tokens--
+
+	wait := s.refilled.Sub(now)
+	if s.tokens < 0 {
+		wait += time.Duration(-s.tokens / s.requestsPerSecond * float64(time.Second))
+	}
+	return wait
+}
+
+// pauseUntil drains the bucket and refills it again starting at the given time
+func (s *tokenBucketRateLimitStrategy) pauseUntil(t time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if t.After(s.refilled) {
+		s.tokens = math.Min(s.tokens, 0)
+		s.refilled = t
+	}
+}
+
+// adaptToRemainingRequests pauses the bucket until the rate limit is reset if the response reports that no
+// requests are remaining
+func (s *tokenBucketRateLimitStrategy) adaptToRemainingRequests(response Response, now time.Time) {
+	remaining := response.Headers[http.CanonicalHeaderKey("X-RateLimit-Remaining")]
+	if len(remaining) == 0 || remaining[0] != "0" {
+		return
+	}
+
+	reset := response.Headers[http.CanonicalHeaderKey("X-RateLimit-Reset")]
+	if len(reset) == 0 {
+		return
+	}
+
+	humanReadableTimestamp, resetTimeInMicroseconds, err := timeutils.StringTimestampToHumanReadableFormat(reset[0])
+	if err != nil {
+		log.Debug("tokenBucketRateLimitStrategy: Ignoring invalid rate limit header: %v", err)
+		return
+	}
+
+	log.Debug("tokenBucketRateLimitStrategy: No requests remaining, pausing requests until %s", humanReadableTimestamp)
+	pause := s.sleep.applyMinMaxDefaults(timeutils.ConvertMicrosecondsToUnixTime(resetTimeInMicroseconds).Sub(now))
+	s.pauseUntil(now.Add(pause))
+}
@@ -0,0 +1,152 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketAllowsBurstWithoutWaiting(t *testing.T) {
+	s := newTokenBucketRateLimitStrategy(2, 3)
+	now := time.Unix(0, 0)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, s.reserve(now), time.Duration(0))
+	}
+	assert.Equal(t, s.reserve(now), 500*time.Millisecond)
+	assert.Equal(t, s.reserve(now), time.Second)
+}
+
+func TestTokenBucketRefillsWithRequestsPerSecond(t *testing.T) {
+	s := newTokenBucketRateLimitStrategy(2, 2)
+	now := time.Unix(0, 0)
+
+	s.reserve(now)
+	s.reserve(now)
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, s.reserve(now), time.Duration(0))
+	assert.Equal(t, s.reserve(now), 500*time.Millisecond)
+}
+
+func TestTokenBucketNeverHoldsMoreThanBurst(t *testing.T) {
+	s := newTokenBucketRateLimitStrategy(10, 2)
+	now := time.Unix(0, 0)
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, s.reserve(now), time.Duration(0))
+	assert.Equal(t, s.reserve(now), time.Duration(0))
+	assert.Equal(t, s.reserve(now), 100*time.Millisecond)
+}
+
+func TestTokenBucketPausesUntilResetIfNoRequestsAreRemaining(t *testing.T) {
+	s := newTokenBucketRateLimitStrategy(10, 10)
+	now := time.Unix(0, 0)
+
+	response := Response{
+		StatusCode: http.StatusOK,
+		Headers: map[string][]string{
+			"X-Ratelimit-Remaining": {"0"},
+			"X-Ratelimit-Reset":     {strconv.FormatInt((5 * time.Second).Microseconds(), 10)},
+		},
+	}
+	s.adaptToRemainingRequests(response, now)
+
+	assert.Equal(t, s.reserve(now), 5*time.Second+100*time.Millisecond)
+}
+
+func TestTokenBucketIgnoresResponsesWithRemainingRequests(t *testing.T) {
+	s := newTokenBucketRateLimitStrategy(10, 10)
+	now := time.Unix(0, 0)
+
+	response := Response{
+		StatusCode: http.StatusOK,
+		Headers: map[string][]string{
+			"X-Ratelimit-Remaining": {"3"},
+			"X-Ratelimit-Reset":     {strconv.FormatInt((5 * time.Second).Microseconds(), 10)},
+		},
+	}
+	s.adaptToRemainingRequests(response, now)
+
+	assert.Equal(t, s.reserve(now), time.Duration(0))
+}
+
+func TestTokenBucketRateLimitStrategyPausesAndRetriesThrottledRequests(t *testing.T) {
+	s := newTokenBucketRateLimitStrategy(10, 10)
+	timelineProvider := createTimelineProviderMock(t)
+	headers := createTestHeaders(42 * time.Second.Microseconds()) // in 42 seconds
+	before := GetRateLimitStatistics()
+
+	invocationCount := 0
+	callback := func() (Response, error) {
+		invocationCount++
+		if invocationCount == 1 {
+			return Response{
+				StatusCode: http.StatusTooManyRequests,
+				Headers:    headers,
+			}, nil
+		}
+		return Response{
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+
+	timelineProvider.EXPECT().Now().AnyTimes().Return(time.Unix(0, 0)) // time travel to the 70s
+	timelineProvider.EXPECT().Sleep(gomock.Any()).Times(1).Do(func(duration time.Duration) {
+		assert.Assert(t, duration >= 42*time.Second)
+	})
+
+	response, err := s.executeRequest(timelineProvider, callback)
+
+	assert.NilError(t, err)
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+	assert.Equal(t, invocationCount, 2)
+
+	after := GetRateLimitStatistics()
+	assert.Equal(t, after.ThrottledRequests-before.ThrottledRequests, int64(1))
+	assert.Equal(t, after.DelayedRequests-before.DelayedRequests, int64(1))
+	assert.Assert(t, after.WaitTime-before.WaitTime >= 42*time.Second)
+}
+
+func TestTokenBucketRateLimitStrategyGivesUpAfterMaxRetries(t *testing.T) {
+	s := newTokenBucketRateLimitStrategy(10, 10)
+	timelineProvider := createTimelineProviderMock(t)
+
+	invocationCount := 0
+	callback := func() (Response, error) {
+		invocationCount++
+		return Response{
+			StatusCode: http.StatusTooManyRequests,
+		}, nil
+	}
+
+	timelineProvider.EXPECT().Now().AnyTimes().Return(time.Unix(0, 0))
+	timelineProvider.EXPECT().Sleep(gomock.Any()).Times(maxThrottledRetries)
+
+	response, err := s.executeRequest(timelineProvider, callback)
+
+	assert.NilError(t, err)
+	assert.Equal(t, response.StatusCode, http.StatusTooManyRequests)
+	assert.Equal(t, invocationCount, maxThrottledRetries+1)
+}
@@ -0,0 +1,203 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrSchemaNotCached is returned if a schema, or the requested version of it, is not part of a Cache
+var ErrSchemaNotCached = errors.New("schema is not cached")
+
+// Cache holds all versions of all schemas stored in a cache folder. The folder contains one JSON file per schema
+// version, as returned by the settings API. Cache files are written by Download.
+type Cache struct {
+	// schemas holds all schemas by schema ID and version
+	schemas map[string]map[string]Schema
+}
+
+// LoadCache loads all schemas stored in the given cache folder
+func LoadCache(fs afero.Fs, folder string) (*Cache, error) {
+	cache := &Cache{schemas: make(map[string]map[string]Schema)}
+
+	exists, err := afero.DirExists(fs, folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema cache %q: %w", folder, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("schema cache %q does not exist", folder)
+	}
+
+	err = afero.Walk(fs, folder, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		data, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+
+		s, err := Parse(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		cache.add(s)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema cache %q: %w", folder, err)
+	}
+
+	return cache, nil
+}
+
+func (c *Cache) add(s Schema) {
+	if _, found := c.schemas[s.SchemaId]; !found {
+		c.schemas[s.SchemaId] = make(map[string]Schema)
+	}
+	c.schemas[s.SchemaId][s.Version] = s
+}
+
+// Get returns the given version of the schema with the given ID. If version is empty, the latest cached version is
+// returned. ErrSchemaNotCached is returned if the schema or version is not cached.
+func (c *Cache) Get(schemaId string, schemaVersion string) (Schema, error) {
+	versions, found := c.schemas[schemaId]
+	if !found {
+		return Schema{}, fmt.Errorf("%w: %s", ErrSchemaNotCached, schemaId)
+	}
+
+	if schemaVersion == "" {
+		return versions[latestVersion(versions)], nil
+	}
+
+	s, found := versions[schemaVersion]
+	if !found {
+		return Schema{}, fmt.Errorf("%w: %s (version %s)", ErrSchemaNotCached, schemaId, schemaVersion)
+	}
+	return s, nil
+}
+
+// latestVersion returns the highest of the given schema versions. Versions which are not semantic versions are only
+// chosen if no other version exists.
+func latestVersion(versions map[string]Schema) string {
+	var latest string
+	var latestParsed version.Version
+
+	for v := range versions {
+		parsed, err := version.ParseVersion(v)
+		if err != nil {
+			if latest == "" {
+				latest = v
+			}
+			continue
+		}
+		if latestParsed == version.UnknownVersion || parsed.GreaterThan(latestParsed) {
+			latest, latestParsed = v, parsed
+		}
+	}
+
+	return latest
+}
+
+// Validate validates the given JSON value of a settings object against the given version of the schema with the
+// given ID. If version is empty, the latest cached version is used. Objects of schemas which are not cached are not
+// validated, and ErrSchemaNotCached is returned. If the object violates the schema, an InvalidObjectError is returned.
+func (c *Cache) Validate(schemaId string, schemaVersion string, value []byte) error {
+	s, err := c.Get(schemaId, schemaVersion)
+	if err != nil {
+		return err
+	}
+
+	if errs := s.Validate(value); len(errs) > 0 {
+		return InvalidObjectError{SchemaId: s.SchemaId, Version: s.Version, Errors: errs}
+	}
+	return nil
+}
+
+// InvalidObjectError is returned if a settings object violates its schema
+type InvalidObjectError struct {
+	SchemaId string
+	Version  string
+	Errors   []ValidationError
+}
+
+func (e InvalidObjectError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("settings object does not match schema %q (version %s):\n\t- %s", e.SchemaId, e.Version, strings.Join(messages, "\n\t- "))
+}
+
+// Download downloads the latest version of all schemas of the environment of the given client into the given cache
+// folder. Previously downloaded versions are kept, so that configs targeting older schema versions can still be
+// validated. It returns the amount of downloaded schemas.
+func Download(c client.SettingsClient, fs afero.Fs, folder string) (int, error) {
+	schemas, err := c.ListSchemas()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	var errs []error
+	for _, item := range schemas {
+		log.Debug("Downloading schema %q", item.SchemaId)
+
+		data, err := c.GetSchemaById(item.SchemaId)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to download schema %q: %w", item.SchemaId, err))
+			continue
+		}
+
+		if err := write(fs, folder, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return len(schemas) - len(errs), errors.Join(errs...)
+}
+
+// write stores the given schema definition in the cache folder, at '<folder>/<schema ID>/<version>.json'. Colons
+// are not allowed in file names on all platforms, and are replaced in the folder name of the schema.
+func write(fs afero.Fs, folder string, data []byte) error {
+	s, err := Parse(data)
+	if err != nil {
+		return err
+	}
+
+	schemaFolder := filepath.Join(folder, strings.ReplaceAll(s.SchemaId, ":", "_"))
+	if err := fs.MkdirAll(schemaFolder, 0755); err != nil {
+		return fmt.Errorf("failed to create schema cache folder %q: %w", schemaFolder, err)
+	}
+
+	path := filepath.Join(schemaFolder, s.Version+".json")
+	if err := afero.WriteFile(fs, path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write schema %q to %q: %w", s.SchemaId, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"path/filepath"
+	"testing"
+)
+
+func schemaWithVersion(schemaId string, version string) string {
+	return fmt.Sprintf(`{"schemaId": %q, "version": %q, "properties": {"name": {"type": "text", "nullable": false}}}`, schemaId, version)
+}
+
+func TestDownload_WritesSchemasToCache(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas().Return(client.SchemaList{{SchemaId: "builtin:alerting.profile"}, {SchemaId: "builtin:tags.auto-tagging"}}, nil)
+	c.EXPECT().GetSchemaById("builtin:alerting.profile").Return([]byte(testSchema), nil)
+	c.EXPECT().GetSchemaById("builtin:tags.auto-tagging").Return([]byte(schemaWithVersion("builtin:tags.auto-tagging", "1.0.0")), nil)
+
+	count, err := Download(c, fs, "cache")
+	assert.NilError(t, err)
+	assert.Equal(t, count, 2)
+
+	for _, path := range []string{"cache/builtin_alerting.profile/8.0.1.json", "cache/builtin_tags.auto-tagging/1.0.0.json"} {
+		exists, err := afero.Exists(fs, filepath.FromSlash(path))
+		assert.NilError(t, err)
+		assert.Assert(t, exists, "expected schema file %q to exist", path)
+	}
+}
+
+func TestDownload_ContinuesOnFailedSchemas(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas().Return(client.SchemaList{{SchemaId: "builtin:alerting.profile"}, {SchemaId: "builtin:broken"}}, nil)
+	c.EXPECT().GetSchemaById("builtin:broken").Return(nil, errors.New("HTTP 500"))
+	c.EXPECT().GetSchemaById("builtin:alerting.profile").Return([]byte(testSchema), nil)
+
+	count, err := Download(c, fs, "cache")
+	assert.ErrorContains(t, err, `failed to download schema "builtin:broken"`)
+	assert.Equal(t, count, 1)
+
+	cache, err := LoadCache(fs, "cache")
+	assert.NilError(t, err)
+	_, err = cache.Get("builtin:alerting.profile", "")
+	assert.NilError(t, err)
+}
+
+func TestLoadCache_FailsForMissingFolder(t *testing.T) {
+	_, err := LoadCache(afero.NewMemMapFs(), "cache")
+	assert.ErrorContains(t, err, `schema cache "cache" does not exist`)
+}
+
+func TestCache_Get(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for _, v := range []string{"1.9.0", "1.10.0", "1.2"} {
+		assert.NilError(t, write(fs, "cache", []byte(schemaWithVersion("builtin:alerting.profile", v))))
+	}
+
+	cache, err := LoadCache(fs, "cache")
+	assert.NilError(t, err)
+
+	s, err := cache.Get("builtin:alerting.profile", "")
+	assert.NilError(t, err)
+	assert.Equal(t, s.Version, "1.10.0", "latest version should be returned if no version is requested")
+
+	s, err = cache.Get("builtin:alerting.profile", "1.9.0")
+	assert.NilError(t, err)
+	assert.Equal(t, s.Version, "1.9.0")
+
+	_, err = cache.Get("builtin:alerting.profile", "2.0.0")
+	assert.Assert(t, errors.Is(err, ErrSchemaNotCached))
+
+	_, err = cache.Get("builtin:unknown", "")
+	assert.Assert(t, errors.Is(err, ErrSchemaNotCached))
+}
+
+func TestValidatingClient(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NilError(t, write(fs, "cache", []byte(testSchema)))
+
+	cache, err := LoadCache(fs, "cache")
+	assert.NilError(t, err)
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().UpsertSettings(gomock.Any()).Times(2).Return(api.DynatraceEntity{Id: "id", Name: "id"}, nil)
+
+	validating := NewValidatingClient(c, cache)
+
+	t.Run("valid objects are passed on", func(t *testing.T) {
+		_, err := validating.UpsertSettings(client.SettingsObject{
+			SchemaId:      "builtin:alerting.profile",
+			SchemaVersion: "8.0.1",
+			Content:       []byte(`{"name": "profile", "enabled": true, "severity": "ERROR", "rules": [{"delay": 1}]}`),
+		})
+		assert.NilError(t, err)
+	})
+
+	t.Run("invalid objects are rejected", func(t *testing.T) {
+		_, err := validating.UpsertSettings(client.SettingsObject{
+			SchemaId: "builtin:alerting.profile",
+			Content:  []byte(`{"name": "profile", "enabled": true, "severity": "ERROR", "rules": []}`),
+		})
+
+		var invalidErr InvalidObjectError
+		assert.Assert(t, errors.As(err, &invalidErr))
+		assert.DeepEqual(t, invalidErr.Errors, []ValidationError{{Path: "rules", Message: "expected at least 1 elements, but got 0"}})
+	})
+
+	t.Run("objects of schemas not cached are passed on", func(t *testing.T) {
+		_, err := validating.UpsertSettings(client.SettingsObject{
+			SchemaId: "builtin:unknown",
+			Content:  []byte(`{}`),
+		})
+		assert.NilError(t, err)
+	})
+}
@@ -0,0 +1,46 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+)
+
+type validatingClient struct {
+	client.Client
+	cache *Cache
+}
+
+// NewValidatingClient utilizes the decorator pattern to validate settings objects against the schemas of the given
+// cache before they are passed to the given client. Objects violating their schema are rejected with an
+// InvalidObjectError. Objects of schemas which are not cached are passed on without validation.
+func NewValidatingClient(c client.Client, cache *Cache) client.Client {
+	return &validatingClient{c, cache}
+}
+
+func (v validatingClient) UpsertSettings(obj client.SettingsObject) (api.DynatraceEntity, error) {
+	err := v.cache.Validate(obj.SchemaId, obj.SchemaVersion, obj.Content)
+
+	if errors.Is(err, ErrSchemaNotCached) {
+		log.Warn("Unable to validate settings object %q: %s", obj.Id, err)
+	} else if err != nil {
+		return api.DynatraceEntity{}, err
+	}
+
+	return v.Client.UpsertSettings(obj)
+}
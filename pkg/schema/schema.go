@@ -0,0 +1,125 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema caches Settings 2.0 schemas locally, and validates settings objects against the cached schemas
+// without access to a Dynatrace environment.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Schema is the definition of a Settings 2.0 schema, as returned by the settings API
+type Schema struct {
+	SchemaId   string                 `json:"schemaId"`
+	Version    string                 `json:"version"`
+	Properties map[string]Property    `json:"properties"`
+	Types      map[string]ComplexType `json:"types"`
+	Enums      map[string]Enum        `json:"enums"`
+}
+
+// Property defines a single property of a schema or complex type
+type Property struct {
+	Type        PropertyType `json:"type"`
+	Nullable    bool         `json:"nullable"`
+	Constraints []Constraint `json:"constraints"`
+	// Items defines the elements of list and set properties
+	Items      *Property `json:"items"`
+	MinObjects *int      `json:"minObjects"`
+	MaxObjects *int      `json:"maxObjects"`
+	// Precondition is set for properties which are only required if other properties have certain values. Such
+	// properties are never required, as preconditions are not evaluated.
+	Precondition json.RawMessage `json:"precondition"`
+}
+
+// PropertyType is either the name of a primitive type, e.g. 'text' or 'list', or a reference to a complex type or
+// enum of the schema, e.g. '#/types/Rule'
+type PropertyType struct {
+	Name string
+	Ref  string
+}
+
+func (t *PropertyType) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &t.Name); err == nil {
+		return nil
+	}
+
+	var ref struct {
+		Ref string `json:"$ref"`
+	}
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return fmt.Errorf("type is neither a name, nor a reference: %s", data)
+	}
+	t.Ref = ref.Ref
+	return nil
+}
+
+const (
+	typesRefPrefix = "#/types/"
+	enumsRefPrefix = "#/enums/"
+)
+
+// complexType returns the name of the referenced complex type, if the type references one
+func (t PropertyType) complexType() (string, bool) {
+	return strings.CutPrefix(t.Ref, typesRefPrefix)
+}
+
+// enum returns the name of the referenced enum, if the type references one
+func (t PropertyType) enum() (string, bool) {
+	return strings.CutPrefix(t.Ref, enumsRefPrefix)
+}
+
+// Constraint restricts the values of a property. Depending on the Type, only some of the fields are set.
+type Constraint struct {
+	Type      string   `json:"type"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+}
+
+// ComplexType is a nested object type defined by a schema
+type ComplexType struct {
+	Properties map[string]Property `json:"properties"`
+}
+
+// Enum defines the allowed values of an enum property
+type Enum struct {
+	Items []EnumItem `json:"items"`
+}
+
+type EnumItem struct {
+	Value interface{} `json:"value"`
+}
+
+// Parse parses the JSON definition of a schema, as returned by the settings API
+func Parse(data []byte) (Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Schema{}, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	if s.SchemaId == "" {
+		return Schema{}, fmt.Errorf("failed to parse schema: no schemaId defined")
+	}
+
+	if s.Version == "" {
+		return Schema{}, fmt.Errorf("failed to parse schema %q: no version defined", s.SchemaId)
+	}
+
+	return s, nil
+}
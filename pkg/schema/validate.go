@@ -0,0 +1,230 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError describes a single violation of a schema by a settings object
+type ValidationError struct {
+	// Path of the violating property within the object, e.g. 'rules[0].name'. It is empty for the object itself.
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate validates the given JSON value of a settings object against the schema. It checks the structure of the
+// object, required properties, types, enums and the length and range constraints of properties.
+func (s Schema) Validate(value []byte) []ValidationError {
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return []ValidationError{{Message: fmt.Sprintf("value is not valid JSON: %s", err)}}
+	}
+
+	var errs []ValidationError
+	s.validateObject("", s.Properties, v, &errs)
+	return errs
+}
+
+func (s Schema) validateObject(path string, properties map[string]Property, value interface{}, errs *[]ValidationError) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		addError(errs, path, "expected an object, but got %s", describe(value))
+		return
+	}
+
+	for _, name := range sortedKeys(properties) {
+		p := properties[name]
+		v, found := object[name]
+
+		if !found || v == nil {
+			if !p.Nullable && p.Precondition == nil {
+				addError(errs, joinPath(path, name), "required property is missing")
+			}
+			continue
+		}
+
+		s.validateValue(joinPath(path, name), p, v, errs)
+	}
+
+	for _, name := range sortedKeys(object) {
+		if _, found := properties[name]; !found {
+			addError(errs, joinPath(path, name), "property is not defined by the schema")
+		}
+	}
+}
+
+func (s Schema) validateValue(path string, p Property, value interface{}, errs *[]ValidationError) {
+	if typeName, ok := p.Type.complexType(); ok {
+		if t, found := s.Types[typeName]; found {
+			s.validateObject(path, t.Properties, value, errs)
+		}
+		return
+	}
+
+	if enumName, ok := p.Type.enum(); ok {
+		if e, found := s.Enums[enumName]; found {
+			validateEnum(path, e, value, errs)
+		}
+		return
+	}
+
+	switch p.Type.Name {
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			addError(errs, path, "expected a boolean, but got %s", describe(value))
+			return
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			addError(errs, path, "expected an integer, but got %s", describe(value))
+			return
+		}
+	case "float":
+		if _, ok := value.(float64); !ok {
+			addError(errs, path, "expected a number, but got %s", describe(value))
+			return
+		}
+	case "text", "secret", "local_time", "local_date", "time", "zoned_date_time", "date_time":
+		if _, ok := value.(string); !ok {
+			addError(errs, path, "expected a string, but got %s", describe(value))
+			return
+		}
+	case "list", "set":
+		list, ok := value.([]interface{})
+		if !ok {
+			addError(errs, path, "expected a list, but got %s", describe(value))
+			return
+		}
+		if p.MinObjects != nil && len(list) < *p.MinObjects {
+			addError(errs, path, "expected at least %d elements, but got %d", *p.MinObjects, len(list))
+		}
+		if p.MaxObjects != nil && len(list) > *p.MaxObjects {
+			addError(errs, path, "expected at most %d elements, but got %d", *p.MaxObjects, len(list))
+		}
+		if p.Items != nil {
+			for i, item := range list {
+				s.validateValue(fmt.Sprintf("%s[%d]", path, i), *p.Items, item, errs)
+			}
+		}
+	default:
+		// types not known to monaco are not validated
+		return
+	}
+
+	for _, c := range p.Constraints {
+		validateConstraint(path, c, value, errs)
+	}
+}
+
+func validateEnum(path string, e Enum, value interface{}, errs *[]ValidationError) {
+	allowed := make([]string, len(e.Items))
+	for i, item := range e.Items {
+		if reflect.DeepEqual(item.Value, value) {
+			return
+		}
+		allowed[i] = fmt.Sprint(item.Value)
+	}
+	addError(errs, path, "%s is not one of the allowed values [%s]", describe(value), strings.Join(allowed, ", "))
+}
+
+func validateConstraint(path string, c Constraint, value interface{}, errs *[]ValidationError) {
+	switch c.Type {
+	case "LENGTH":
+		s, ok := value.(string)
+		if !ok {
+			return
+		}
+		length := utf8.RuneCountInString(s)
+		if c.MinLength != nil && length < *c.MinLength {
+			addError(errs, path, "expected at least %d characters, but got %d", *c.MinLength, length)
+		}
+		if c.MaxLength != nil && length > *c.MaxLength {
+			addError(errs, path, "expected at most %d characters, but got %d", *c.MaxLength, length)
+		}
+	case "RANGE":
+		n, ok := value.(float64)
+		if !ok {
+			return
+		}
+		if c.Minimum != nil && n < *c.Minimum {
+			addError(errs, path, "%v is less than the minimum of %v", n, *c.Minimum)
+		}
+		if c.Maximum != nil && n > *c.Maximum {
+			addError(errs, path, "%v is greater than the maximum of %v", n, *c.Maximum)
+		}
+	case "NOT_BLANK":
+		if s, ok := value.(string); ok && strings.TrimSpace(s) == "" {
+			addError(errs, path, "must not be blank")
+		}
+	case "PATTERN":
+		s, ok := value.(string)
+		if !ok || c.Pattern == "" {
+			return
+		}
+		// the whole value needs to match. patterns which are not valid Go regular expressions are not validated
+		if r, err := regexp.Compile("^(?:" + c.Pattern + ")$"); err == nil && !r.MatchString(s) {
+			addError(errs, path, "%q does not match pattern %q", s, c.Pattern)
+		}
+	}
+}
+
+func addError(errs *[]ValidationError, path string, format string, args ...interface{}) {
+	*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// describe returns a short description of a JSON value for error messages
+func describe(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "a list"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	sort.Strings(keys)
+	return keys
+}
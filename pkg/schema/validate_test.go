@@ -0,0 +1,146 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package schema
+
+import (
+	"gotest.tools/assert"
+	"testing"
+)
+
+const testSchema = `{
+  "schemaId": "builtin:alerting.profile",
+  "version": "8.0.1",
+  "properties": {
+    "name": {"type": "text", "nullable": false, "constraints": [{"type": "LENGTH", "minLength": 1, "maxLength": 10}, {"type": "NOT_BLANK"}]},
+    "enabled": {"type": "boolean", "nullable": false},
+    "threshold": {"type": "integer", "nullable": true, "constraints": [{"type": "RANGE", "minimum": 1, "maximum": 100}]},
+    "severity": {"type": {"$ref": "#/enums/Severity"}, "nullable": false},
+    "tag": {"type": "text", "nullable": false, "precondition": {"type": "EQUALS", "property": "enabled", "expectedValue": true}, "constraints": [{"type": "PATTERN", "pattern": "[a-z]+"}]},
+    "rules": {"type": "list", "nullable": false, "minObjects": 1, "maxObjects": 2, "items": {"type": {"$ref": "#/types/Rule"}}}
+  },
+  "types": {
+    "Rule": {"properties": {"delay": {"type": "float", "nullable": false}}}
+  },
+  "enums": {
+    "Severity": {"items": [{"value": "AVAILABILITY"}, {"value": "ERROR"}]}
+  }
+}`
+
+func parseTestSchema(t *testing.T) Schema {
+	s, err := Parse([]byte(testSchema))
+	assert.NilError(t, err)
+	return s
+}
+
+func TestValidate_ValidObject(t *testing.T) {
+	s := parseTestSchema(t)
+
+	errs := s.Validate([]byte(`{"name": "profile", "enabled": true, "threshold": 5, "severity": "ERROR", "tag": "abc", "rules": [{"delay": 1.5}]}`))
+	assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+}
+
+func TestValidate_NullableAndPreconditionPropertiesAreOptional(t *testing.T) {
+	s := parseTestSchema(t)
+
+	errs := s.Validate([]byte(`{"name": "profile", "enabled": false, "threshold": null, "severity": "ERROR", "rules": [{"delay": 1}]}`))
+	assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+}
+
+func TestValidate_ReportsViolations(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []ValidationError
+	}{
+		{
+			name:  "missing required properties",
+			value: `{"rules": [{"delay": 1}]}`,
+			want: []ValidationError{
+				{Path: "enabled", Message: "required property is missing"},
+				{Path: "name", Message: "required property is missing"},
+				{Path: "severity", Message: "required property is missing"},
+			},
+		},
+		{
+			name:  "wrong types",
+			value: `{"name": 1, "enabled": "true", "threshold": 1.5, "severity": "ERROR", "rules": {}}`,
+			want: []ValidationError{
+				{Path: "enabled", Message: `expected a boolean, but got "true"`},
+				{Path: "name", Message: "expected a string, but got 1"},
+				{Path: "rules", Message: "expected a list, but got an object"},
+				{Path: "threshold", Message: "expected an integer, but got 1.5"},
+			},
+		},
+		{
+			name:  "unknown property",
+			value: `{"name": "profile", "enabled": true, "severity": "ERROR", "rules": [{"delay": 1, "unknown": 1}], "unknown": true}`,
+			want: []ValidationError{
+				{Path: "rules[0].unknown", Message: "property is not defined by the schema"},
+				{Path: "unknown", Message: "property is not defined by the schema"},
+			},
+		},
+		{
+			name:  "enum",
+			value: `{"name": "profile", "enabled": true, "severity": "WARNING", "rules": [{"delay": 1}]}`,
+			want: []ValidationError{
+				{Path: "severity", Message: `"WARNING" is not one of the allowed values [AVAILABILITY, ERROR]`},
+			},
+		},
+		{
+			name:  "constraints",
+			value: `{"name": "  ", "enabled": true, "threshold": 101, "severity": "ERROR", "tag": "ABC", "rules": [{"delay": 1}, {"delay": 2}, {"delay": "3"}]}`,
+			want: []ValidationError{
+				{Path: "name", Message: "must not be blank"},
+				{Path: "rules", Message: "expected at most 2 elements, but got 3"},
+				{Path: "rules[2].delay", Message: `expected a number, but got "3"`},
+				{Path: "tag", Message: `"ABC" does not match pattern "[a-z]+"`},
+				{Path: "threshold", Message: "101 is greater than the maximum of 100"},
+			},
+		},
+		{
+			name:  "length",
+			value: `{"name": "", "enabled": true, "severity": "ERROR", "rules": []}`,
+			want: []ValidationError{
+				{Path: "name", Message: "expected at least 1 characters, but got 0"},
+				{Path: "name", Message: "must not be blank"},
+				{Path: "rules", Message: "expected at least 1 elements, but got 0"},
+			},
+		},
+		{
+			name:  "not an object",
+			value: `[]`,
+			want: []ValidationError{
+				{Message: "expected an object, but got a list"},
+			},
+		},
+	}
+
+	s := parseTestSchema(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, s.Validate([]byte(tt.value)), tt.want)
+		})
+	}
+}
+
+func TestParse_RequiresIdAndVersion(t *testing.T) {
+	_, err := Parse([]byte(`{"version": "1.0"}`))
+	assert.ErrorContains(t, err, "no schemaId")
+
+	_, err = Parse([]byte(`{"schemaId": "builtin:alerting.profile"}`))
+	assert.ErrorContains(t, err, "no version")
+}
@@ -0,0 +1,178 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package state holds the local deployment state of monaco projects.
+//
+// The state maps the coordinate of each deployed config to the ID of the Dynatrace object it was deployed to,
+// its name, and a checksum of the last deployed payload. It allows deployments to update known objects directly by
+// their ID instead of searching them by name, and to detect configs renamed in the project.
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/spf13/afero"
+	"os"
+	"sync"
+)
+
+// FileName is the name of the state file, which is stored next to the manifest
+const FileName = "monaco-state.json"
+
+const currentVersion = 1
+
+// ErrLocked is returned by Lock if the state file is already locked by another deployment
+var ErrLocked = errors.New("state file is locked by another deployment")
+
+// Entry holds the deployment state of a single config
+type Entry struct {
+	// ObjectId is the Dynatrace object ID the config was deployed to
+	ObjectId string `json:"objectId"`
+	// Name is the name the config was deployed with
+	Name string `json:"name"`
+	// Checksum is the checksum of the last deployed payload, see Checksum
+	Checksum string `json:"checksum"`
+}
+
+// State holds the deployment state of configs per environment. It is safe for concurrent use.
+type State struct {
+	lock sync.RWMutex
+	// environments maps environment names to the state of the environment's configs, identified by their coordinate
+	environments map[string]map[string]Entry
+}
+
+type persistedState struct {
+	Version      int                         `json:"version"`
+	Environments map[string]map[string]Entry `json:"environments"`
+}
+
+// New creates a new empty State
+func New() *State {
+	return &State{environments: make(map[string]map[string]Entry)}
+}
+
+// Load reads the state from the given file. If the file does not exist, an empty state is returned.
+func Load(fs afero.Fs, path string) (*State, error) {
+	exists, err := afero.Exists(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if state file %q exists: %w", path, err)
+	}
+
+	if !exists {
+		return New(), nil
+	}
+
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %q: %w", path, err)
+	}
+
+	var persisted persistedState
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %q: %w", path, err)
+	}
+
+	if persisted.Version != currentVersion {
+		return nil, fmt.Errorf("state file %q has unsupported version %d, expected %d", path, persisted.Version, currentVersion)
+	}
+
+	s := New()
+	for env, entries := range persisted.Environments {
+		if entries != nil {
+			s.environments[env] = entries
+		}
+	}
+
+	return s, nil
+}
+
+// Write persists the state to the given file
+func (s *State) Write(fs afero.Fs, path string) error {
+	s.lock.RLock()
+	data, err := json.MarshalIndent(persistedState{
+		Version:      currentVersion,
+		Environments: s.environments,
+	}, "", "  ")
+	s.lock.RUnlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	if err := afero.WriteFile(fs, path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// Get returns the state entry of the given config in the given environment
+func (s *State) Get(environment string, c coordinate.Coordinate) (Entry, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	e, found := s.environments[environment][c.String()]
+	return e, found
+}
+
+// Put stores the state entry of the given config in the given environment
+func (s *State) Put(environment string, c coordinate.Coordinate, e Entry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, found := s.environments[environment]; !found {
+		s.environments[environment] = make(map[string]Entry)
+	}
+	s.environments[environment][c.String()] = e
+}
+
+// Delete removes the state entry of the given config in the given environment
+func (s *State) Delete(environment string, c coordinate.Coordinate) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.environments[environment], c.String())
+}
+
+// Checksum calculates the checksum of a rendered config payload as stored in Entry
+func Checksum(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Lock acquires an exclusive lock on the state file at the given path, by creating a lock file next to it.
+// If the state file is already locked, ErrLocked is returned. The returned function releases the lock.
+func Lock(fs afero.Fs, path string) (unlock func() error, err error) {
+	lockPath := path + ".lock"
+
+	f, err := fs.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("%w: remove %q if no other deployment is running", ErrLocked, lockPath)
+		}
+		return nil, fmt.Errorf("failed to create lock file %q: %w", lockPath, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create lock file %q: %w", lockPath, err)
+	}
+
+	return func() error {
+		return fs.Remove(lockPath)
+	}, nil
+}
@@ -0,0 +1,107 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"testing"
+)
+
+var testCoordinate = coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: "profile"}
+
+func TestLoad_NotExistingFileReturnsEmptyState(t *testing.T) {
+	s, err := Load(afero.NewMemMapFs(), "monaco-state.json")
+
+	assert.NilError(t, err)
+	_, found := s.Get("dev", testCoordinate)
+	assert.Assert(t, !found)
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	entry := Entry{ObjectId: "object-id", Name: "profile", Checksum: Checksum([]byte("{}"))}
+
+	s := New()
+	s.Put("dev", testCoordinate, entry)
+	assert.NilError(t, s.Write(fs, "monaco-state.json"))
+
+	loaded, err := Load(fs, "monaco-state.json")
+	assert.NilError(t, err)
+
+	got, found := loaded.Get("dev", testCoordinate)
+	assert.Assert(t, found)
+	assert.DeepEqual(t, got, entry)
+
+	_, found = loaded.Get("prod", testCoordinate)
+	assert.Assert(t, !found, "entries must be stored per environment")
+}
+
+func TestDelete(t *testing.T) {
+	s := New()
+	s.Put("dev", testCoordinate, Entry{ObjectId: "object-id"})
+
+	s.Delete("dev", testCoordinate)
+	s.Delete("prod", testCoordinate)
+
+	_, found := s.Get("dev", testCoordinate)
+	assert.Assert(t, !found)
+}
+
+func TestLoad_FailsOnInvalidContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"invalid json", `{`, "failed to parse"},
+		{"unsupported version", `{"version": 42, "environments": {}}`, "unsupported version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			assert.NilError(t, afero.WriteFile(fs, "monaco-state.json", []byte(tt.content), 0644))
+
+			_, err := Load(fs, "monaco-state.json")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	assert.Equal(t, Checksum([]byte("{}")), Checksum([]byte("{}")))
+	assert.Assert(t, Checksum([]byte(`{"a": 1}`)) != Checksum([]byte(`{"a": 2}`)))
+}
+
+func TestLock(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	unlock, err := Lock(fs, "monaco-state.json")
+	assert.NilError(t, err)
+
+	_, err = Lock(fs, "monaco-state.json")
+	assert.Assert(t, errors.Is(err, ErrLocked), "expected ErrLocked, got %v", err)
+
+	assert.NilError(t, unlock())
+
+	unlock, err = Lock(fs, "monaco-state.json")
+	assert.NilError(t, err)
+	assert.NilError(t, unlock())
+}